@@ -2,9 +2,15 @@ package config
 
 import (
 	"os"
+	"path/filepath"
+	"time"
 
 	"feldrise.com/animal-api/database"
 	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/keystore"
+	"feldrise.com/animal-api/pkg/mailer"
+	"feldrise.com/animal-api/pkg/model"
+	"feldrise.com/animal-api/pkg/storage"
 	"github.com/spf13/viper"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -19,22 +25,78 @@ func doesEnvExists(name string) bool {
 type Constants struct {
 	// Constants
 	Port           string `yaml:"port"`
+	BehindProxy    bool   `yaml:"behindProxy"` // trust X-Forwarded-For and X-Real-IP for the client address
 	JWTSecret      string `yaml:"jwtSecret"`
 	DataPath       string `yaml:"dataPath"`
 	BaseURL        string `yaml:"baseURL"`
 	ApplicationURL string `yaml:"applicationURL"`
+	Timezone       string `yaml:"timezone"` // the time zone of the clinic, like Europe/Paris
+
+	// Tokens
+	AccessTokenLifetime  time.Duration `yaml:"accessTokenLifetime"`
+	RefreshTokenLifetime time.Duration `yaml:"refreshTokenLifetime"`
+	JWTAlgorithm         string        `yaml:"jwtAlgorithm"`      // RS256 or EdDSA
+	JWTKeyGracePeriod    time.Duration `yaml:"jwtKeyGracePeriod"` // how long a retired key still verifies tokens
+
+	// Vitals
+	WeightLossThreshold float64       `yaml:"weightLossThreshold"` // the weight loss, in percent, flagged as rapid
+	WeightLossPeriod    time.Duration `yaml:"weightLossPeriod"`    // the period the weight loss is looked for on
+
+	// Photos
+	MaxPhotoSize  int64  `yaml:"maxPhotoSize"`  // in bytes
+	HEICConverter string `yaml:"heicConverter"` // command converting HEIC to JPEG, like heif-convert, HEIC is refused when empty
+
+	// Storage
+	StorageBackend string `yaml:"storageBackend"` // local or s3
+	S3Endpoint     string `yaml:"s3Endpoint"`     // like http://localhost:9000 for MinIO
+	S3Region       string `yaml:"s3Region"`
+	S3Bucket       string `yaml:"s3Bucket"`
+	S3AccessKey    string `yaml:"s3AccessKey"`
+	S3SecretKey    string `yaml:"s3SecretKey"`
+	S3PathStyle    bool   `yaml:"s3PathStyle"` // bucket in the path rather than in the host name, needed by MinIO
 
 	// Database
 	ConnectionString string `yaml:"connectionString"`
+
+	// Mails
+	Mailer       string `yaml:"mailer"` // memory, file or smtp
+	MailFrom     string `yaml:"mailFrom"`
+	SMTPHost     string `yaml:"smtpHost"`
+	SMTPPort     string `yaml:"smtpPort"`
+	SMTPUsername string `yaml:"smtpUsername"`
+	SMTPPassword string `yaml:"smtpPassword"`
 }
 
 type Config struct {
 	Constants
 
 	// Repositories
-	CatsRepository   dbmodel.CatsRepository
-	UserRepository   dbmodel.UserRepository
-	VisitsRepository dbmodel.VisitsRepository
+	AnimalsRepository dbmodel.AnimalsRepository
+	SpeciesRepository dbmodel.SpeciesRepository
+	UserRepository    dbmodel.UserRepository
+	VisitsRepository  dbmodel.VisitsRepository
+	VitalsRepository  dbmodel.VitalsRepository
+	PhotosRepository  dbmodel.PhotosRepository
+
+	TreatmentsRepository      dbmodel.TreatmentsRepository
+	VisitTreatmentsRepository dbmodel.VisitTreatmentsRepository
+
+	AppointmentTypesRepository dbmodel.AppointmentTypesRepository
+	WorkingHoursRepository     dbmodel.WorkingHoursRepository
+	TimeOffRepository          dbmodel.TimeOffRepository
+
+	RefreshTokensRepository dbmodel.RefreshTokensRepository
+	RevokedTokensRepository dbmodel.RevokedTokensRepository
+	RecoveryCodesRepository dbmodel.RecoveryCodesRepository
+	RolesRepository         dbmodel.RolesRepository
+
+	LoginThrottlesRepository dbmodel.LoginThrottlesRepository
+
+	// Services
+	Mailer   mailer.Mailer
+	Keys     *keystore.KeyStore
+	Storage  storage.Storage
+	Location *time.Location // the time zone of the clinic
 }
 
 func initViper(configName string) (Constants, error) {
@@ -42,6 +104,8 @@ func initViper(configName string) (Constants, error) {
 	viper.SetConfigType("yaml")
 	viper.SetConfigName(configName)
 
+	setOptionalDefaults()
+
 	err := viper.ReadInConfig()
 	if _, ok := err.(viper.ConfigFileNotFoundError); !ok && err != nil {
 		return Constants{}, err
@@ -80,17 +144,77 @@ func initViperEnv() error {
 	return nil
 }
 
+// setOptionalDefaults sets the values used when an optional setting is
+// neither in the config file nor in the environment
+func setOptionalDefaults() {
+	setDefaultFromEnv("BehindProxy", "BEHIND_PROXY", "false")
+	setDefaultFromEnv("Timezone", "TIMEZONE", "Europe/Paris")
+
+	setDefaultFromEnv("AccessTokenLifetime", "ACCESS_TOKEN_LIFETIME", "15m")
+	setDefaultFromEnv("RefreshTokenLifetime", "REFRESH_TOKEN_LIFETIME", "720h")
+	setDefaultFromEnv("JWTAlgorithm", "JWT_ALGORITHM", keystore.AlgorithmEdDSA)
+	setDefaultFromEnv("JWTKeyGracePeriod", "JWT_KEY_GRACE_PERIOD", "24h")
+
+	setDefaultFromEnv("WeightLossThreshold", "WEIGHT_LOSS_THRESHOLD", "10")
+	setDefaultFromEnv("WeightLossPeriod", "WEIGHT_LOSS_PERIOD", "720h")
+
+	setDefaultFromEnv("MaxPhotoSize", "MAX_PHOTO_SIZE", "20971520")
+	setDefaultFromEnv("HEICConverter", "HEIC_CONVERTER", "")
+
+	setDefaultFromEnv("StorageBackend", "STORAGE_BACKEND", "local")
+	setDefaultFromEnv("S3Endpoint", "S3_ENDPOINT", "")
+	setDefaultFromEnv("S3Region", "S3_REGION", "us-east-1")
+	setDefaultFromEnv("S3Bucket", "S3_BUCKET", "")
+	setDefaultFromEnv("S3AccessKey", "S3_ACCESS_KEY", "")
+	setDefaultFromEnv("S3SecretKey", "S3_SECRET_KEY", "")
+	setDefaultFromEnv("S3PathStyle", "S3_PATH_STYLE", "true")
+
+	setDefaultFromEnv("Mailer", "MAILER", "memory")
+	setDefaultFromEnv("MailFrom", "MAIL_FROM", "no-reply@feldrise.com")
+	setDefaultFromEnv("SMTPHost", "SMTP_HOST", "localhost")
+	setDefaultFromEnv("SMTPPort", "SMTP_PORT", "25")
+	setDefaultFromEnv("SMTPUsername", "SMTP_USERNAME", "")
+	setDefaultFromEnv("SMTPPassword", "SMTP_PASSWORD", "")
+}
+
+func setDefaultFromEnv(key string, name string, fallback string) {
+	if value, exists := os.LookupEnv(name); exists {
+		viper.SetDefault(key, value)
+		return
+	}
+
+	viper.SetDefault(key, fallback)
+}
+
+// NewConstants only loads the settings, without connecting to the database
+func NewConstants() (Constants, error) {
+	return initViper("config")
+}
+
+// OpenKeyStore opens the keys signing the tokens, stored under DataPath
+func OpenKeyStore(constants Constants) (*keystore.KeyStore, error) {
+	return keystore.Open(filepath.Join(constants.DataPath, "keys"), constants.JWTAlgorithm, constants.JWTKeyGracePeriod)
+}
+
 func New() (*Config, error) {
 	config := Config{}
 
 	// Constants
-	constants, err := initViper("config")
+	constants, err := NewConstants()
 
 	config.Constants = constants
 	if err != nil {
 		return &config, err
 	}
 
+	model.SetBaseURL(config.Constants.BaseURL)
+	model.SetSigningKey(config.Constants.JWTSecret)
+
+	config.Location, err = time.LoadLocation(config.Constants.Timezone)
+	if err != nil {
+		return &config, err
+	}
+
 	// Database
 	databaseSession, err := gorm.Open(postgres.Open(config.Constants.ConnectionString), &gorm.Config{
 		// Logger: logger.Default.LogMode(logger.Info),
@@ -102,9 +226,61 @@ func New() (*Config, error) {
 	database.Migrate(databaseSession)
 	database.ApplySeeds(databaseSession)
 
-	config.CatsRepository = dbmodel.NewCatsRepository(databaseSession)
+	config.AnimalsRepository = dbmodel.NewAnimalsRepository(databaseSession)
+	config.SpeciesRepository = dbmodel.NewSpeciesRepository(databaseSession)
 	config.UserRepository = dbmodel.NewUserRepository(databaseSession)
 	config.VisitsRepository = dbmodel.NewVisitsRepository(databaseSession)
+	config.VitalsRepository = dbmodel.NewVitalsRepository(databaseSession)
+	config.PhotosRepository = dbmodel.NewPhotosRepository(databaseSession)
+
+	config.TreatmentsRepository = dbmodel.NewTreatmentsRepository(databaseSession)
+	config.VisitTreatmentsRepository = dbmodel.NewVisitTreatmentsRepository(databaseSession)
+
+	config.AppointmentTypesRepository = dbmodel.NewAppointmentTypesRepository(databaseSession)
+	config.WorkingHoursRepository = dbmodel.NewWorkingHoursRepository(databaseSession)
+	config.TimeOffRepository = dbmodel.NewTimeOffRepository(databaseSession)
+
+	config.RefreshTokensRepository = dbmodel.NewRefreshTokensRepository(databaseSession)
+	config.RevokedTokensRepository = dbmodel.NewRevokedTokensRepository(databaseSession)
+	config.RecoveryCodesRepository = dbmodel.NewRecoveryCodesRepository(databaseSession)
+	config.RolesRepository = dbmodel.NewRolesRepository(databaseSession)
+
+	config.LoginThrottlesRepository = dbmodel.NewLoginThrottlesRepository(databaseSession)
+
+	// Services
+	config.Mailer, err = mailer.New(mailer.Options{
+		Name:         config.Constants.Mailer,
+		From:         config.Constants.MailFrom,
+		OutboxPath:   filepath.Join(config.Constants.DataPath, "outbox"),
+		SMTPHost:     config.Constants.SMTPHost,
+		SMTPPort:     config.Constants.SMTPPort,
+		SMTPUsername: config.Constants.SMTPUsername,
+		SMTPPassword: config.Constants.SMTPPassword,
+	})
+	if err != nil {
+		return &config, err
+	}
+
+	config.Keys, err = OpenKeyStore(config.Constants)
+	if err != nil {
+		return &config, err
+	}
+
+	config.Storage, err = storage.New(storage.Options{
+		Name: config.Constants.StorageBackend,
+		Path: filepath.Join(config.Constants.DataPath, "files"),
+		S3: storage.S3Options{
+			Endpoint:  config.Constants.S3Endpoint,
+			Region:    config.Constants.S3Region,
+			Bucket:    config.Constants.S3Bucket,
+			AccessKey: config.Constants.S3AccessKey,
+			SecretKey: config.Constants.S3SecretKey,
+			PathStyle: config.Constants.S3PathStyle,
+		},
+	})
+	if err != nil {
+		return &config, err
+	}
 
 	return &config, nil
 }
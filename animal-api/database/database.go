@@ -9,11 +9,44 @@ import (
 )
 
 func Migrate(database *gorm.DB) {
+	if err := migrateCatsToAnimals(database); err != nil {
+		log.Fatalf("Error migrating the cats to animals: %s", err)
+	}
+
+	if err := migratePhotoKeys(database); err != nil {
+		log.Fatalf("Error migrating the photo keys: %s", err)
+	}
+
+	if err := migrateVisitStatuses(database); err != nil {
+		log.Fatalf("Error migrating the visit statuses: %s", err)
+	}
+
+	if err := migrateVisitIndexes(database); err != nil {
+		log.Fatalf("Error migrating the visit indexes: %s", err)
+	}
+
 	database.AutoMigrate(
 		&seed.Seed{},
 		&dbmodel.User{},
 		&dbmodel.Role{},
 		&dbmodel.UserProfile{},
+		&dbmodel.Species{},
+		&dbmodel.Animal{},
+		&dbmodel.AppointmentType{},
+		&dbmodel.VisitSeries{},
+		&dbmodel.Visit{},
+		&dbmodel.Vital{},
+		&dbmodel.Photo{},
+		&dbmodel.PhotoLock{},
+		&dbmodel.Treatment{},
+		&dbmodel.VisitTreatment{},
+		&dbmodel.VisitTransition{},
+		&dbmodel.WorkingHours{},
+		&dbmodel.TimeOff{},
+		&dbmodel.RefreshToken{},
+		&dbmodel.RevokedToken{},
+		&dbmodel.RecoveryCode{},
+		&dbmodel.LoginThrottle{},
 	)
 
 	log.Println("Database migrated successfully")
@@ -25,6 +58,9 @@ func ApplySeeds(database *gorm.DB) {
 		SeedFunc func(*gorm.DB) error
 	}{
 		{"SeedV1", seed.SeedV1},
+		{"SeedV2", seed.SeedV2},
+		{"SeedV3", seed.SeedV3},
+		{"SeedV4", seed.SeedV4},
 	}
 
 	for _, seedToApply := range seedsToApply {
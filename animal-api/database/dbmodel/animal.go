@@ -0,0 +1,450 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Animal struct {
+	gorm.Model
+
+	Name string `gorm:"not null"`
+
+	SpeciesID  uint    `gorm:"not null;index"`
+	Species    Species `gorm:"constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;"`
+	Attributes JSONMap `gorm:"type:jsonb;not null;default:'{}'"` // the attributes specific to the species
+
+	// Medical identity
+	Breed      *string
+	BirthDate  *time.Time `gorm:"type:date"`
+	Sex        string     `gorm:"not null;default:unknown"`
+	Neutered   bool       `gorm:"not null;default:false"`
+	NeuteredAt *time.Time `gorm:"type:date"`
+	Color      *string
+	Microchip  *string `gorm:"uniqueIndex:idx_animals_microchip,where:deleted_at IS NULL"` // ISO 11784 code
+	Tattoo     *string `gorm:"uniqueIndex:idx_animals_tattoo,where:deleted_at IS NULL"`
+
+	// Animals created before owners were tracked have none
+	OwnerID *uint `gorm:"index"`
+	Owner   *User `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+
+	Visits []*Visit `gorm:"foreignKey:AnimalID"`
+	Vitals []*Vital `gorm:"foreignKey:AnimalID"`
+	Photos []*Photo `gorm:"foreignKey:AnimalID"`
+
+	// The photo of Photos marked as the profile picture
+	ProfilePhoto *Photo `gorm:"foreignKey:AnimalID;constraint:-"`
+}
+
+func (animal *Animal) ToModel() *model.Animal {
+	var owner *model.User
+	var vitals []*model.Vital
+	var profilePhoto *model.Photo
+	var ageYears, ageMonths *int
+
+	if animal.Owner != nil {
+		owner = animal.Owner.ToModel(true, true)
+	}
+
+	if animal.ProfilePhoto != nil {
+		profilePhoto = animal.ProfilePhoto.ToModel()
+	}
+
+	for _, vital := range animal.Vitals {
+		vitals = append(vitals, vital.ToModel())
+	}
+
+	if animal.BirthDate != nil {
+		years, months := model.Age(*animal.BirthDate, time.Now())
+		ageYears, ageMonths = &years, &months
+	}
+
+	return &model.Animal{
+		ID:         animal.ID,
+		DeletedAt:  formatDeletedAt(animal.DeletedAt),
+		CreatedAt:  animal.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  animal.UpdatedAt.Format(time.RFC3339),
+		Links:      model.AnimalLinks(animal.ID),
+		Name:       animal.Name,
+		Species:    animal.Species.Code,
+		Attributes: animal.Attributes,
+		OwnerID:    animal.OwnerID,
+		Owner:      owner,
+		Breed:      animal.Breed,
+		BirthDate:  animal.BirthDate,
+		AgeYears:   ageYears,
+		AgeMonths:  ageMonths,
+		Sex:        animal.Sex,
+		Neutered:   animal.Neutered,
+		NeuteredAt: animal.NeuteredAt,
+		Color:      animal.Color,
+		Microchip:  animal.Microchip,
+		Tattoo:     animal.Tattoo,
+		Vitals:     vitals,
+
+		ProfilePhoto: profilePhoto,
+	}
+}
+
+type AnimalsFieldsToInclude struct {
+	Visits bool
+	Owner  bool
+	Vitals bool
+}
+
+type AnimalsFilter struct {
+	OwnerID   *uint
+	SpeciesID *uint
+}
+
+// AnimalsFilterForUser returns the filter restricting the animals to the ones
+// the user can access: clients only see their own animals while the staff
+// sees them all
+func AnimalsFilterForUser(user *User) *AnimalsFilter {
+	if user == nil || user.RoleID != model.RoleClient {
+		return &AnimalsFilter{}
+	}
+
+	return &AnimalsFilter{OwnerID: &user.ID}
+}
+
+type AnimalsRepository interface {
+	FindByID(id uint, filter *AnimalsFilter, fields *AnimalsFieldsToInclude) (*Animal, error)
+	FindAll(filter *AnimalsFilter, fields *AnimalsFieldsToInclude) ([]*Animal, error)
+	FindByMicrochip(number string, fields *AnimalsFieldsToInclude) (*Animal, error)
+	FindByTattoo(identifier string) (*Animal, error)
+	Create(animal *Animal) (*Animal, error)
+	Update(animal *Animal) (*Animal, error)
+	Delete(animal *Animal) error
+
+	// Trash
+	FindDeleted() ([]*Animal, error)
+	FindDeletedByID(id uint) (*Animal, error)
+	Restore(animal *Animal) error
+	Purge(animal *Animal) error
+}
+
+type animalsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnimalsRepository(db *gorm.DB) AnimalsRepository {
+	return &animalsRepository{
+		db: db,
+	}
+}
+
+func (r *animalsRepository) FindByID(id uint, filter *AnimalsFilter, fields *AnimalsFieldsToInclude) (*Animal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var animal Animal
+	tx := r.db.WithContext(ctx).Model(&animal).Preload("Species").Preload("ProfilePhoto", "profile = ?", true)
+
+	if fields != nil && fields.Visits {
+		tx = tx.Preload("Visits")
+	}
+
+	if fields != nil && fields.Owner {
+		tx = tx.Preload("Owner.UserProfile")
+	}
+
+	if fields != nil && fields.Vitals {
+		tx = tx.Preload("Vitals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("recorded_at, id")
+		})
+	}
+
+	tx = filterAnimals(tx, filter)
+
+	err := tx.Where("id = ?", id).First(&animal).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &animal, nil
+}
+
+func (r *animalsRepository) FindAll(filter *AnimalsFilter, fields *AnimalsFieldsToInclude) ([]*Animal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var animals []*Animal
+	tx := r.db.WithContext(ctx).Model(&Animal{}).Preload("Species").Preload("ProfilePhoto", "profile = ?", true)
+
+	if fields != nil && fields.Visits {
+		tx = tx.Preload("Visits")
+	}
+
+	if fields != nil && fields.Owner {
+		tx = tx.Preload("Owner.UserProfile")
+	}
+
+	if fields != nil && fields.Vitals {
+		tx = tx.Preload("Vitals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("recorded_at, id")
+		})
+	}
+
+	tx = filterAnimals(tx, filter)
+
+	err := tx.Find(&animals).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return animals, nil
+}
+
+func (r *animalsRepository) FindByMicrochip(number string, fields *AnimalsFieldsToInclude) (*Animal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var animal Animal
+	tx := r.db.WithContext(ctx).Model(&animal).Preload("Species").Preload("ProfilePhoto", "profile = ?", true)
+
+	if fields != nil && fields.Owner {
+		tx = tx.Preload("Owner.UserProfile")
+	}
+
+	err := tx.Where("microchip = ?", number).First(&animal).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &animal, nil
+}
+
+func (r *animalsRepository) FindByTattoo(identifier string) (*Animal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var animal Animal
+	err := r.db.WithContext(ctx).Preload("Species").Where("tattoo = ?", identifier).First(&animal).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &animal, nil
+}
+
+func (r *animalsRepository) Create(animal *Animal) (*Animal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Create(animal).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return animal, nil
+}
+
+func (r *animalsRepository) Update(animal *Animal) (*Animal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Saved as a whole so the fields set back to their zero value are updated
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Save(animal).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return animal, nil
+}
+
+// Delete moves the animal and its visits, vitals and photos to the trash.
+// They share the same deletion date so restoring the animal only restores
+// the records deleted with it.
+func (r *animalsRepository) Delete(animal *Animal) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The database only keeps microseconds
+	now := time.Now().Truncate(time.Microsecond)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Visit{}).Where("animal_id = ?", animal.ID).UpdateColumn("deleted_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&Vital{}).Where("animal_id = ?", animal.ID).UpdateColumn("deleted_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&Photo{}).Where("animal_id = ?", animal.ID).UpdateColumn("deleted_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(animal).UpdateColumn("deleted_at", now).Error
+	})
+}
+
+func (r *animalsRepository) FindDeleted() ([]*Animal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var animals []*Animal
+	err := r.db.WithContext(ctx).Unscoped().Preload("Species").Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&animals).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return animals, nil
+}
+
+func (r *animalsRepository) FindDeletedByID(id uint) (*Animal, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var animal Animal
+	err := r.db.WithContext(ctx).Unscoped().Preload("Species").Where("id = ? AND deleted_at IS NOT NULL", id).First(&animal).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &animal, nil
+}
+
+// Restore takes the animal and the records deleted with it out of the trash,
+// ErrVisitConflict is returned when the time of one of its visits was given
+// to another visit of its veterinarian meanwhile
+func (r *animalsRepository) Restore(animal *Animal) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var visits []*Visit
+		err := tx.Unscoped().Where("animal_id = ? AND deleted_at = ?", animal.ID, animal.DeletedAt.Time).Find(&visits).Error
+		if err != nil {
+			return err
+		}
+
+		ids := make([]uint, 0, len(visits))
+
+		for _, visit := range visits {
+			ids = append(ids, visit.ID)
+		}
+
+		for _, visit := range visits {
+			if _, err := checkVetFree(tx, visit, ids...); err != nil {
+				return err
+			}
+		}
+
+		err = tx.Unscoped().Model(&Visit{}).Where("animal_id = ? AND deleted_at = ?", animal.ID, animal.DeletedAt.Time).UpdateColumn("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(&Vital{}).Where("animal_id = ? AND deleted_at = ?", animal.ID, animal.DeletedAt.Time).UpdateColumn("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(&Photo{}).Where("animal_id = ? AND deleted_at = ?", animal.ID, animal.DeletedAt.Time).UpdateColumn("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(animal).UpdateColumn("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		animal.DeletedAt = gorm.DeletedAt{}
+
+		return nil
+	})
+}
+
+// Purge permanently deletes the animal and all its visits, series,
+// treatments, transitions, vitals and photos, the files of the photos must
+// be removed from the storage by the caller
+func (r *animalsRepository) Purge(animal *Animal) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("animal_id = ?", animal.ID).Delete(&Vital{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Where("animal_id = ?", animal.ID).Delete(&Photo{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Where("animal_id = ?", animal.ID).Delete(&VisitTreatment{}).Error
+		if err != nil {
+			return err
+		}
+
+		visits := tx.Unscoped().Model(&Visit{}).Select("id").Where("animal_id = ?", animal.ID)
+
+		err = tx.Where("visit_id IN (?)", visits).Delete(&VisitTransition{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Where("animal_id = ?", animal.ID).Delete(&Visit{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("animal_id = ?", animal.ID).Delete(&VisitSeries{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(animal).Error
+	})
+}
+
+func filterAnimals(tx *gorm.DB, filter *AnimalsFilter) *gorm.DB {
+	if filter == nil {
+		return tx
+	}
+
+	if filter.OwnerID != nil {
+		tx = tx.Where("owner_id = ?", *filter.OwnerID)
+	}
+
+	if filter.SpeciesID != nil {
+		tx = tx.Where("species_id = ?", *filter.SpeciesID)
+	}
+
+	return tx
+}
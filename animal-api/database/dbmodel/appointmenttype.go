@@ -0,0 +1,116 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+)
+
+// AppointmentType is a kind of visit the clients can book, like a
+// vaccination, with the time it takes
+type AppointmentType struct {
+	gorm.Model
+
+	Name        string `gorm:"not null"`
+	Description string `gorm:"type:text;not null;default:''"`
+	Duration    int    `gorm:"not null"` // in minutes
+}
+
+func (appointmentType *AppointmentType) ToModel() *model.AppointmentType {
+	return &model.AppointmentType{
+		ID:          appointmentType.ID,
+		CreatedAt:   appointmentType.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   appointmentType.UpdatedAt.Format(time.RFC3339),
+		DeletedAt:   formatDeletedAt(appointmentType.DeletedAt),
+		Links:       model.AppointmentTypeLinks(appointmentType.ID),
+		Name:        appointmentType.Name,
+		Description: appointmentType.Description,
+		Duration:    appointmentType.Duration,
+	}
+}
+
+type AppointmentTypesRepository interface {
+	FindByID(id uint) (*AppointmentType, error)
+	FindAll() ([]*AppointmentType, error)
+	Create(appointmentType *AppointmentType) (*AppointmentType, error)
+	Update(appointmentType *AppointmentType) (*AppointmentType, error)
+	Delete(appointmentType *AppointmentType) error
+}
+
+type appointmentTypesRepository struct {
+	db *gorm.DB
+}
+
+func NewAppointmentTypesRepository(db *gorm.DB) AppointmentTypesRepository {
+	return &appointmentTypesRepository{
+		db: db,
+	}
+}
+
+func (r *appointmentTypesRepository) FindByID(id uint) (*AppointmentType, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var appointmentType AppointmentType
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&appointmentType).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &appointmentType, nil
+}
+
+func (r *appointmentTypesRepository) FindAll() ([]*AppointmentType, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var appointmentTypes []*AppointmentType
+	err := r.db.WithContext(ctx).Order("name, id").Find(&appointmentTypes).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return appointmentTypes, nil
+}
+
+func (r *appointmentTypesRepository) Create(appointmentType *AppointmentType) (*AppointmentType, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Create(appointmentType).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return appointmentType, nil
+}
+
+func (r *appointmentTypesRepository) Update(appointmentType *AppointmentType) (*AppointmentType, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Save(appointmentType).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return appointmentType, nil
+}
+
+// Delete stops offering the type, the visits booked as it keep it
+func (r *appointmentTypesRepository) Delete(appointmentType *AppointmentType) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Delete(appointmentType).Error
+}
@@ -0,0 +1,56 @@
+package dbmodel
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// JSONMap is a JSON object stored in a jsonb column
+type JSONMap map[string]interface{}
+
+func (m JSONMap) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+
+	bytes, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(bytes), nil
+}
+
+func (m *JSONMap) Scan(value interface{}) error {
+	var bytes []byte
+
+	switch v := value.(type) {
+	case nil:
+		*m = JSONMap{}
+		return nil
+	case []byte:
+		bytes = v
+	case string:
+		bytes = []byte(v)
+	default:
+		return errors.New("unsupported JSON value")
+	}
+
+	return json.Unmarshal(bytes, m)
+}
+
+// formatDeletedAt returns the date a record was moved to the trash, nil when
+// it isn't in the trash
+func formatDeletedAt(deletedAt gorm.DeletedAt) *string {
+	if !deletedAt.Valid {
+		return nil
+	}
+
+	formatted := deletedAt.Time.Format(time.RFC3339)
+
+	return &formatted
+}
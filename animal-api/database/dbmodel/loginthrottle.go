@@ -0,0 +1,99 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LoginThrottle counts the failed logins for an account or an IP address
+type LoginThrottle struct {
+	gorm.Model
+
+	Key           string    `gorm:"not null;uniqueIndex"` // account:<email> or ip:<address>
+	Failures      int       `gorm:"not null;default:0"`
+	LastFailureAt time.Time `gorm:"not null"`
+	LockedUntil   *time.Time
+}
+
+type LoginThrottlesRepository interface {
+	FindByKeys(keys ...string) ([]*LoginThrottle, error)
+	// RecordFailure counts a failure, the counter restarts when the previous
+	// failure is older than window
+	RecordFailure(key string, window time.Duration) (*LoginThrottle, error)
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+}
+
+type loginThrottlesRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottlesRepository(db *gorm.DB) LoginThrottlesRepository {
+	return &loginThrottlesRepository{
+		db: db,
+	}
+}
+
+func (r *loginThrottlesRepository) FindByKeys(keys ...string) ([]*LoginThrottle, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var throttles []*LoginThrottle
+	err := r.db.WithContext(ctx).Model(&LoginThrottle{}).Where("key IN ?", keys).Find(&throttles).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return throttles, nil
+}
+
+func (r *loginThrottlesRepository) RecordFailure(key string, window time.Duration) (*LoginThrottle, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var throttle LoginThrottle
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// A single statement, so concurrent failures are all counted
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "key"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failures":        gorm.Expr("CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END", now.Add(-window)),
+				"last_failure_at": now,
+				"updated_at":      now,
+				"deleted_at":      nil,
+			}),
+		}).Create(&LoginThrottle{Key: key, Failures: 1, LastFailureAt: now}).Error
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("key = ?", key).First(&throttle).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &throttle, nil
+}
+
+func (r *loginThrottlesRepository) Lock(key string, until time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Model(&LoginThrottle{}).Where("key = ?", key).Update("locked_until", until).Error
+}
+
+func (r *loginThrottlesRepository) Reset(key string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Unscoped().Where("key = ?", key).Delete(&LoginThrottle{}).Error
+}
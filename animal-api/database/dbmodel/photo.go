@@ -0,0 +1,210 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Photo struct {
+	gorm.Model
+
+	AnimalID uint   `gorm:"not null;index"`
+	Key      string `gorm:"not null;index:idx_photos_content_key"` // the SHA-256 of the uploaded file, photos of the same file share their sizes
+	Profile  bool   `gorm:"not null;default:false"`
+	Width    int    `gorm:"not null"`
+	Height   int    `gorm:"not null"`
+}
+
+func (photo *Photo) ToModel() *model.Photo {
+	return &model.Photo{
+		ID:        photo.ID,
+		CreatedAt: photo.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: photo.UpdatedAt.Format(time.RFC3339),
+		Links:     model.PhotoLinks(photo.AnimalID, photo.ID),
+		AnimalID:  photo.AnimalID,
+		Profile:   photo.Profile,
+		Width:     photo.Width,
+		Height:    photo.Height,
+		URLs:      model.PhotoURLs(photo.ID),
+	}
+}
+
+// StorageKey returns the key a size of the photo is stored at
+func (photo *Photo) StorageKey(size string) string {
+	return "photos/" + photo.Key + "/" + size + ".jpg"
+}
+
+// PhotoLock is held while the files of a key are stored or deleted. It is a
+// lease rather than a lock of the database, so no transaction stays open
+// during the uploads, and the lease of a crashed server ends by itself.
+type PhotoLock struct {
+	Key       string    `gorm:"primaryKey"`
+	Token     string    `gorm:"not null"` // tells the holder apart once the lease was taken over
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+type PhotosRepository interface {
+	FindByID(id uint) (*Photo, error)
+	FindAll(animalID uint, withDeleted bool) ([]*Photo, error)
+	Create(photo *Photo) (*Photo, error)
+	SetProfile(photo *Photo) error
+	Delete(photo *Photo) error
+	CountByKey(key string) (int64, error)
+	// LockKey takes the lock of the key for the lifetime, it returns false
+	// when another token holds it
+	LockKey(key string, token string, lifetime time.Duration) (bool, error)
+	UnlockKey(key string, token string) error
+}
+
+type photosRepository struct {
+	db *gorm.DB
+}
+
+func NewPhotosRepository(db *gorm.DB) PhotosRepository {
+	return &photosRepository{
+		db: db,
+	}
+}
+
+func (r *photosRepository) FindByID(id uint) (*Photo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var photo Photo
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&photo).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &photo, nil
+}
+
+// FindAll returns the photos of an animal, the most recent first. The ones
+// in the trash with their animal are only returned withDeleted.
+func (r *photosRepository) FindAll(animalID uint, withDeleted bool) ([]*Photo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var photos []*Photo
+	tx := r.db.WithContext(ctx)
+
+	if withDeleted {
+		tx = tx.Unscoped()
+	}
+
+	err := tx.Where("animal_id = ?", animalID).Order("created_at DESC, id DESC").Find(&photos).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return photos, nil
+}
+
+func (r *photosRepository) Create(photo *Photo) (*Photo, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Create(photo).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return photo, nil
+}
+
+// SetProfile makes the photo the profile picture of its animal, in place of
+// the previous one
+func (r *photosRepository) SetProfile(photo *Photo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&Photo{}).Where("animal_id = ? AND id <> ?", photo.AnimalID, photo.ID).UpdateColumn("profile", false).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(photo).UpdateColumn("profile", true).Error
+		if err != nil {
+			return err
+		}
+
+		photo.Profile = true
+
+		return nil
+	})
+}
+
+// Delete permanently deletes the photo, its files must be removed from the
+// storage by the caller. The most recent photo left becomes the profile
+// picture when it was this one.
+func (r *photosRepository) Delete(photo *Photo) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Delete(photo).Error
+		if err != nil || !photo.Profile {
+			return err
+		}
+
+		latest := tx.Model(&Photo{}).Select("id").Where("animal_id = ?", photo.AnimalID).Order("created_at DESC, id DESC").Limit(1)
+
+		return tx.Model(&Photo{}).Where("id = (?)", latest).UpdateColumn("profile", true).Error
+	})
+}
+
+// CountByKey returns the number of photos stored under the key, including
+// the ones in the trash with their animal
+func (r *photosRepository) CountByKey(key string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&Photo{}).Where("key = ?", key).Count(&count).Error
+
+	return count, err
+}
+
+func (r *photosRepository) LockKey(key string, token string, lifetime time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	// A single statement, the lease is only taken over once it expired
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"token":      token,
+			"expires_at": now.Add(lifetime),
+		}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			gorm.Expr("photo_locks.expires_at < ?", now),
+		}},
+	}).Create(&PhotoLock{Key: key, Token: token, ExpiresAt: now.Add(lifetime)})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *photosRepository) UnlockKey(key string, token string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Where("key = ? AND token = ?", key, token).Delete(&PhotoLock{}).Error
+}
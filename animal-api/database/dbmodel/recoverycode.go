@@ -0,0 +1,78 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RecoveryCode lets a user log in without its second factor, once
+type RecoveryCode struct {
+	gorm.Model
+
+	CodeHash string `gorm:"not null"`
+	UsedAt   *time.Time
+
+	UserID uint `gorm:"not null;index"`
+	User   User `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+type RecoveryCodesRepository interface {
+	// Replace drops the user's codes in favor of the new ones
+	Replace(userID uint, codeHashes []string) error
+	// Use marks the code as used, it returns false if there is no such
+	// unused code
+	Use(userID uint, codeHash string) (bool, error)
+	DeleteAllForUser(userID uint) error
+}
+
+type recoveryCodesRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodesRepository(db *gorm.DB) RecoveryCodesRepository {
+	return &recoveryCodesRepository{
+		db: db,
+	}
+}
+
+func (r *recoveryCodesRepository) Replace(userID uint, codeHashes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]*RecoveryCode, 0, len(codeHashes))
+		for _, codeHash := range codeHashes {
+			codes = append(codes, &RecoveryCode{CodeHash: codeHash, UserID: userID})
+		}
+
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *recoveryCodesRepository) Use(userID uint, codeHash string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Model(&RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *recoveryCodesRepository) DeleteAllForUser(userID uint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&RecoveryCode{}).Error
+}
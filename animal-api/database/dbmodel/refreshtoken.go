@@ -0,0 +1,139 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type RefreshToken struct {
+	gorm.Model
+
+	TokenHash string    `gorm:"not null;uniqueIndex"`
+	FamilyID  string    `gorm:"not null;index"` // shared by all the tokens rotated from the same login
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+
+	UserID uint `gorm:"not null;index"`
+	User   User `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
+}
+
+type RefreshTokensRepository interface {
+	FindByHash(tokenHash string) (*RefreshToken, error)
+	Create(token *RefreshToken) (*RefreshToken, error)
+	// Rotate marks the token as used and creates its successor. It returns
+	// false when the token has already been used or revoked in between.
+	Rotate(token *RefreshToken, next *RefreshToken) (bool, error)
+	// IsFamilyActive tells whether the family still has tokens which aren't
+	// revoked
+	IsFamilyActive(familyID string) (bool, error)
+	RevokeFamily(familyID string) error
+	RevokeAllForUser(userID uint) error
+}
+
+type refreshTokensRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokensRepository(db *gorm.DB) RefreshTokensRepository {
+	return &refreshTokensRepository{
+		db: db,
+	}
+}
+
+func (r *refreshTokensRepository) FindByHash(tokenHash string) (*RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var token RefreshToken
+	err := r.db.WithContext(ctx).Model(&token).Where("token_hash = ?", tokenHash).First(&token).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *refreshTokensRepository) Create(token *RefreshToken) (*RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Create(token).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func (r *refreshTokensRepository) Rotate(token *RefreshToken, next *RefreshToken) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rotated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&RefreshToken{}).
+			Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", token.ID).
+			Update("used_at", time.Now())
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Someone else used the token in between
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+
+		rotated = true
+		return nil
+	})
+
+	return rotated, err
+}
+
+func (r *refreshTokensRepository) IsFamilyActive(familyID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *refreshTokensRepository) RevokeFamily(familyID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Model(&RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *refreshTokensRepository) RevokeAllForUser(userID uint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Model(&RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
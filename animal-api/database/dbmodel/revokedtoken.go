@@ -0,0 +1,62 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RevokedToken is an access token rejected before its expiration, it can
+// be forgotten once expired
+type RevokedToken struct {
+	gorm.Model
+
+	JTI       string    `gorm:"not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+}
+
+type RevokedTokensRepository interface {
+	IsRevoked(jti string) (bool, error)
+	Revoke(jti string, expiresAt time.Time) error
+}
+
+type revokedTokensRepository struct {
+	db *gorm.DB
+}
+
+func NewRevokedTokensRepository(db *gorm.DB) RevokedTokensRepository {
+	return &revokedTokensRepository{
+		db: db,
+	}
+}
+
+func (r *revokedTokensRepository) IsRevoked(jti string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var count int64
+	err := r.db.WithContext(ctx).Model(&RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *revokedTokensRepository) Revoke(jti string, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Expired tokens are rejected anyway, no need to keep them
+		if err := tx.Unscoped().Where("expires_at < ?", time.Now()).Delete(&RevokedToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&RevokedToken{JTI: jti, ExpiresAt: expiresAt}).Error
+	})
+}
@@ -1,9 +1,91 @@
 package dbmodel
 
-import "gorm.io/gorm"
+import (
+	"context"
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+)
 
 type Role struct {
 	gorm.Model
 	Name        string `gorm:"not null"`
 	Description string `gorm:"not null"`
+
+	// Users having the role must enable two-factor authentication
+	RequireMFA bool `gorm:"not null;default:false"`
+}
+
+func (role *Role) ToModel() *model.Role {
+	return &model.Role{
+		ID:          role.ID,
+		CreatedAt:   role.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   role.UpdatedAt.Format(time.RFC3339),
+		Links:       model.RoleLinks(role.ID),
+		Name:        role.Name,
+		Description: role.Description,
+		RequireMFA:  role.RequireMFA,
+	}
+}
+
+type RolesRepository interface {
+	FindByID(id uint) (*Role, error)
+	FindAll() ([]*Role, error)
+	Update(role *Role) (*Role, error)
+}
+
+type rolesRepository struct {
+	db *gorm.DB
+}
+
+func NewRolesRepository(db *gorm.DB) RolesRepository {
+	return &rolesRepository{
+		db: db,
+	}
+}
+
+func (r *rolesRepository) FindByID(id uint) (*Role, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var role Role
+	err := r.db.WithContext(ctx).Model(&role).Where("id = ?", id).First(&role).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (r *rolesRepository) FindAll() ([]*Role, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var roles []*Role
+	err := r.db.WithContext(ctx).Model(&Role{}).Order("id").Find(&roles).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (r *rolesRepository) Update(role *Role) (*Role, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Save(role).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
 }
@@ -0,0 +1,82 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+)
+
+type Species struct {
+	gorm.Model
+	Code string `gorm:"not null;unique"`
+	Name string `gorm:"not null"`
+
+	// The optional attributes the animals of the species can have, with the
+	// type of their value
+	Attributes JSONMap `gorm:"type:jsonb;not null;default:'{}'"`
+}
+
+func (species *Species) ToModel() *model.Species {
+	attributes := make(map[string]string, len(species.Attributes))
+	for name, kind := range species.Attributes {
+		attributes[name], _ = kind.(string)
+	}
+
+	return &model.Species{
+		ID:         species.ID,
+		CreatedAt:  species.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  species.UpdatedAt.Format(time.RFC3339),
+		Code:       species.Code,
+		Name:       species.Name,
+		Attributes: attributes,
+	}
+}
+
+type SpeciesRepository interface {
+	FindByCode(code string) (*Species, error)
+	FindAll() ([]*Species, error)
+}
+
+type speciesRepository struct {
+	db *gorm.DB
+}
+
+func NewSpeciesRepository(db *gorm.DB) SpeciesRepository {
+	return &speciesRepository{
+		db: db,
+	}
+}
+
+func (r *speciesRepository) FindByCode(code string) (*Species, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var species Species
+	err := r.db.WithContext(ctx).Model(&species).Where("code = ?", code).First(&species).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &species, nil
+}
+
+func (r *speciesRepository) FindAll() ([]*Species, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var species []*Species
+	err := r.db.WithContext(ctx).Model(&Species{}).Order("id").Find(&species).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return species, nil
+}
@@ -0,0 +1,125 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+)
+
+// TimeOff is a period a veterinarian doesn't work during their working
+// hours, like holidays
+type TimeOff struct {
+	gorm.Model
+
+	VetID  uint      `gorm:"not null;index:idx_time_offs_vet_start"`
+	Start  time.Time `gorm:"not null;index:idx_time_offs_vet_start"`
+	End    time.Time `gorm:"not null"`
+	Reason string    `gorm:"not null;default:''"`
+
+	// Foreign object
+	Vet User `gorm:"foreignKey:VetID;constraint:OnDelete:CASCADE"`
+}
+
+func (timeOff *TimeOff) ToModel() *model.TimeOff {
+	return &model.TimeOff{
+		ID:        timeOff.ID,
+		CreatedAt: timeOff.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: timeOff.UpdatedAt.Format(time.RFC3339),
+		Links:     model.TimeOffLinks(timeOff.VetID, timeOff.ID),
+		VetID:     timeOff.VetID,
+		Start:     timeOff.Start,
+		End:       timeOff.End,
+		Reason:    timeOff.Reason,
+	}
+}
+
+type TimeOffFilter struct {
+	VetID uint
+	From  *time.Time // only the time off ending after this date
+	To    *time.Time // only the time off starting before this date
+}
+
+type TimeOffRepository interface {
+	FindByID(id uint, vetID uint) (*TimeOff, error)
+	FindAll(filter *TimeOffFilter) ([]*TimeOff, error)
+	Create(timeOff *TimeOff) (*TimeOff, error)
+	Delete(timeOff *TimeOff) error
+}
+
+type timeOffRepository struct {
+	db *gorm.DB
+}
+
+func NewTimeOffRepository(db *gorm.DB) TimeOffRepository {
+	return &timeOffRepository{
+		db: db,
+	}
+}
+
+func (r *timeOffRepository) FindByID(id uint, vetID uint) (*TimeOff, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var timeOff TimeOff
+	err := r.db.WithContext(ctx).Where("id = ? AND vet_id = ?", id, vetID).First(&timeOff).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &timeOff, nil
+}
+
+func (r *timeOffRepository) FindAll(filter *TimeOffFilter) ([]*TimeOff, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var timeOffs []*TimeOff
+	tx := r.db.WithContext(ctx).Model(&TimeOff{})
+
+	if filter != nil && filter.VetID != 0 {
+		tx = tx.Where("vet_id = ?", filter.VetID)
+	}
+
+	if filter != nil && filter.From != nil {
+		tx = tx.Where(`"end" > ?`, *filter.From)
+	}
+
+	if filter != nil && filter.To != nil {
+		tx = tx.Where("start < ?", *filter.To)
+	}
+
+	err := tx.Order("start, id").Find(&timeOffs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return timeOffs, nil
+}
+
+func (r *timeOffRepository) Create(timeOff *TimeOff) (*TimeOff, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Omit("Vet").Create(timeOff).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return timeOff, nil
+}
+
+func (r *timeOffRepository) Delete(timeOff *TimeOff) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Unscoped().Delete(timeOff).Error
+}
@@ -0,0 +1,153 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+)
+
+// Treatment is an entry of the catalog of the treatments and medications the
+// clinic gives. Removed entries stay in the database for the visits which
+// recorded them.
+type Treatment struct {
+	gorm.Model
+
+	Name        string `gorm:"not null;uniqueIndex:idx_treatments_name,where:deleted_at IS NULL"`
+	Description string `gorm:"type:text;not null;default:''"`
+	Category    string `gorm:"not null;index"`
+}
+
+func (treatment *Treatment) ToModel() *model.Treatment {
+	return &model.Treatment{
+		ID:          treatment.ID,
+		CreatedAt:   treatment.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   treatment.UpdatedAt.Format(time.RFC3339),
+		DeletedAt:   formatDeletedAt(treatment.DeletedAt),
+		Links:       model.TreatmentLinks(treatment.ID),
+		Name:        treatment.Name,
+		Description: treatment.Description,
+		Category:    treatment.Category,
+	}
+}
+
+type TreatmentsFilter struct {
+	Search   string // part of the name
+	Category string
+}
+
+type TreatmentsRepository interface {
+	FindByID(id uint) (*Treatment, error)
+	FindByName(name string) (*Treatment, error)
+	FindAll(filter *TreatmentsFilter) ([]*Treatment, error)
+	Create(treatment *Treatment) (*Treatment, error)
+	Update(treatment *Treatment) (*Treatment, error)
+	Delete(treatment *Treatment) error
+}
+
+type treatmentsRepository struct {
+	db *gorm.DB
+}
+
+func NewTreatmentsRepository(db *gorm.DB) TreatmentsRepository {
+	return &treatmentsRepository{
+		db: db,
+	}
+}
+
+func (r *treatmentsRepository) FindByID(id uint) (*Treatment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var treatment Treatment
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&treatment).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &treatment, nil
+}
+
+// FindByName returns the treatment of the catalog with the name, whatever
+// its case
+func (r *treatmentsRepository) FindByName(name string) (*Treatment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var treatment Treatment
+	err := r.db.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).First(&treatment).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &treatment, nil
+}
+
+func (r *treatmentsRepository) FindAll(filter *TreatmentsFilter) ([]*Treatment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var treatments []*Treatment
+	tx := r.db.WithContext(ctx).Model(&Treatment{})
+
+	if filter != nil && filter.Search != "" {
+		tx = tx.Where("name ILIKE ?", "%"+filter.Search+"%")
+	}
+
+	if filter != nil && filter.Category != "" {
+		tx = tx.Where("category = ?", filter.Category)
+	}
+
+	err := tx.Order("name, id").Find(&treatments).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return treatments, nil
+}
+
+func (r *treatmentsRepository) Create(treatment *Treatment) (*Treatment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Create(treatment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return treatment, nil
+}
+
+func (r *treatmentsRepository) Update(treatment *Treatment) (*Treatment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Save(treatment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return treatment, nil
+}
+
+// Delete removes the treatment from the catalog, the visits keep it
+func (r *treatmentsRepository) Delete(treatment *Treatment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Delete(treatment).Error
+}
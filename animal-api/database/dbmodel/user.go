@@ -10,14 +10,35 @@ import (
 
 type User struct {
 	gorm.Model
-	Email          string `gorm:"not null;unique"`
-	EmailToken     *string
-	EmailVerifedAt *time.Time
-
-	PasswordHash       string `gorm:"not null"`
-	PasswordResetToken *string
-
-	RoleID uint `gorm:"not null;DEFAULT:2;"`
+	Email            string  `gorm:"not null;unique"`
+	EmailToken       *string // hash of the token sent by email
+	EmailTokenSentAt *time.Time
+	EmailVerifedAt   *time.Time
+	PendingEmail     *string // the new email, switched to once verified
+
+	PasswordHash           string  `gorm:"not null"`
+	PasswordResetToken     *string // hash of the code sent by email
+	PasswordResetExpiresAt *time.Time
+	PasswordResetSentAt    *time.Time
+	PasswordResetAttempts  int `gorm:"not null;default:0"`
+
+	// Tokens issued before this date are rejected
+	SessionsRevokedAt *time.Time
+
+	// Suspended users can't login nor use their tokens
+	SuspendedAt *time.Time
+
+	// Two-factor authentication, the secret is set on enrollment and only
+	// required once enabled
+	TOTPSecret    *string
+	TOTPEnabledAt *time.Time
+	TOTPLastStep  int64 `gorm:"not null;default:0"` // the last accepted time step, to prevent replays
+
+	// Hash of the token of the calendar feed, calendar applications can't
+	// send the access tokens
+	CalendarToken *string `gorm:"uniqueIndex"`
+
+	RoleID uint `gorm:"not null;DEFAULT:3;"`
 	Role   Role `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
 
 	UserProfile *UserProfile `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;"`
@@ -25,6 +46,12 @@ type User struct {
 
 type UserFilter struct {
 	SearchString string
+	RoleID       *uint
+	Suspended    *bool
+
+	// Pagination, ignored by Count
+	Offset int
+	Limit  int
 }
 
 func (user *User) ToModel(includeEmail bool, includePhone bool) *model.User {
@@ -40,20 +67,39 @@ func (user *User) ToModel(includeEmail bool, includePhone bool) *model.User {
 	}
 
 	return &model.User{
-		ID:        user.ID,
-		CreatedAt: user.CreatedAt.Format(time.RFC3339),
-		Email:     email,
-		Profile:   userProfile,
+		ID:            user.ID,
+		DeletedAt:     formatDeletedAt(user.DeletedAt),
+		CreatedAt:     user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:     user.UpdatedAt.Format(time.RFC3339),
+		Links:         model.UserLinks(user.ID),
+		Email:         email,
+		EmailVerified: user.EmailVerifedAt != nil,
+		RoleID:        user.RoleID,
+		MFAEnabled:    user.TOTPEnabledAt != nil,
+		Suspended:     user.SuspendedAt != nil,
+		Profile:       userProfile,
 	}
 }
 
 type UserRepository interface {
 	FindByID(id uint, includeProfile bool) (*User, error)
 	FindByEmail(email string, includeProfile bool) (*User, error)
+	FindByEmailToken(tokenHash string) (*User, error)
+	FindByCalendarToken(tokenHash string) (*User, error)
 	FindAll(filter *UserFilter) ([]*User, error)
+	Count(filter *UserFilter) (int64, error)
 	Create(user *User) (*User, error)
 	Update(user *User) (*User, error)
-	// Delete(user *User) error
+	SetPasswordResetToken(userID uint, tokenHash string, lifetime time.Duration, cooldown time.Duration) (bool, error)
+	CountPasswordResetAttempt(userID uint, maxAttempts int) (bool, error)
+	UsePasswordResetToken(userID uint, tokenHash string) (bool, error)
+	Delete(user *User) error
+
+	// Trash
+	FindDeleted() ([]*User, error)
+	FindDeletedByID(id uint) (*User, error)
+	Restore(user *User) error
+	Purge(user *User) error
 }
 
 type userRepository struct {
@@ -114,6 +160,42 @@ func (r *userRepository) FindByEmail(email string, includeProfile bool) (*User,
 	return &user, nil
 }
 
+func (r *userRepository) FindByEmailToken(tokenHash string) (*User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var user User
+	err := r.db.WithContext(ctx).Model(&user).Where("email_token = ?", tokenHash).First(&user).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *userRepository) FindByCalendarToken(tokenHash string) (*User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var user User
+	err := r.db.WithContext(ctx).Model(&user).Where("calendar_token = ?", tokenHash).First(&user).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &user, nil
+}
+
 func (r *userRepository) FindByPhone(phone string, includeProfile bool) (*User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -145,11 +227,14 @@ func (r *userRepository) FindAll(filter *UserFilter) ([]*User, error) {
 	var users []*User
 	tx := r.db.WithContext(ctx).Model(&User{})
 	tx = tx.Preload("UserProfile")
+	tx = filterUsers(tx, filter).Order("users.id")
 
-	if filter != nil && filter.SearchString != "" {
-		searchString := "%" + filter.SearchString + "%"
-		tx = tx.Joins("LEFT JOIN user_profiles ON user_profiles.user_id = users.id").
-			Where("users.email ILIKE ? OR user_profiles.first_name ILIKE ? OR user_profiles.last_name ILIKE ?", searchString, searchString, searchString)
+	if filter != nil && filter.Offset > 0 {
+		tx = tx.Offset(filter.Offset)
+	}
+
+	if filter != nil && filter.Limit > 0 {
+		tx = tx.Limit(filter.Limit)
 	}
 
 	err := tx.Find(&users).Error
@@ -161,6 +246,20 @@ func (r *userRepository) FindAll(filter *UserFilter) ([]*User, error) {
 	return users, nil
 }
 
+func (r *userRepository) Count(filter *UserFilter) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var count int64
+	err := filterUsers(r.db.WithContext(ctx).Model(&User{}), filter).Count(&count).Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (r *userRepository) Create(user *User) (*User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -186,3 +285,183 @@ func (r *userRepository) Update(user *User) (*User, error) {
 
 	return user, nil
 }
+
+// SetPasswordResetToken gives the user a new password reset code for the
+// lifetime, false is returned when one was given less than cooldown ago. The
+// attempts are kept until the previous code expired, so asking for new codes
+// doesn't give more attempts.
+func (r *userRepository) SetPasswordResetToken(userID uint, tokenHash string, lifetime time.Duration, cooldown time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	result := r.db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND (password_reset_sent_at IS NULL OR password_reset_sent_at < ?)", userID, now.Add(-cooldown)).
+		UpdateColumns(map[string]interface{}{
+			"password_reset_token":      tokenHash,
+			"password_reset_expires_at": now.Add(lifetime),
+			"password_reset_sent_at":    now,
+			"password_reset_attempts":   gorm.Expr("CASE WHEN password_reset_expires_at > ? THEN password_reset_attempts ELSE 0 END", now),
+		})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// CountPasswordResetAttempt counts an attempt to use the password reset code
+// of the user, false is returned when the code expired or had maxAttempts
+// already. The row is updated in place so parallel attempts all count.
+func (r *userRepository) CountPasswordResetAttempt(userID uint, maxAttempts int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND password_reset_token IS NOT NULL AND password_reset_expires_at > ? AND password_reset_attempts < ?", userID, time.Now(), maxAttempts).
+		UpdateColumn("password_reset_attempts", gorm.Expr("password_reset_attempts + 1"))
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UsePasswordResetToken clears the password reset code of the user if its
+// hash matches, false is returned otherwise. Only one of parallel uses of the
+// code succeeds.
+func (r *userRepository) UsePasswordResetToken(userID uint, tokenHash string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Model(&User{}).
+		Where("id = ? AND password_reset_token = ?", userID, tokenHash).
+		UpdateColumns(map[string]interface{}{
+			"password_reset_token":      nil,
+			"password_reset_expires_at": nil,
+			"password_reset_attempts":   0,
+		})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// Delete moves the user and its profile to the trash
+func (r *userRepository) Delete(user *User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The database only keeps microseconds
+	now := time.Now().Truncate(time.Microsecond)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&UserProfile{}).Where("user_id = ?", user.ID).UpdateColumn("deleted_at", now).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(user).UpdateColumn("deleted_at", now).Error
+	})
+}
+
+func (r *userRepository) FindDeleted() ([]*User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var users []*User
+	err := r.db.WithContext(ctx).Unscoped().Preload("UserProfile", func(tx *gorm.DB) *gorm.DB {
+		return tx.Unscoped()
+	}).Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&users).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (r *userRepository) FindDeletedByID(id uint) (*User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var user User
+	err := r.db.WithContext(ctx).Unscoped().Preload("UserProfile", func(tx *gorm.DB) *gorm.DB {
+		return tx.Unscoped()
+	}).Where("id = ? AND deleted_at IS NOT NULL", id).First(&user).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Restore takes the user and the profile deleted with it out of the trash
+func (r *userRepository) Restore(user *User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&UserProfile{}).Where("user_id = ? AND deleted_at = ?", user.ID, user.DeletedAt.Time).UpdateColumn("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(user).UpdateColumn("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		user.DeletedAt = gorm.DeletedAt{}
+		if user.UserProfile != nil {
+			user.UserProfile.DeletedAt = gorm.DeletedAt{}
+		}
+
+		return nil
+	})
+}
+
+// Purge permanently deletes the user, the foreign keys delete its profile
+// and sessions with it while its animals are kept without owner
+func (r *userRepository) Purge(user *User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Unscoped().Delete(user).Error
+}
+
+func filterUsers(tx *gorm.DB, filter *UserFilter) *gorm.DB {
+	if filter == nil {
+		return tx
+	}
+
+	if filter.SearchString != "" {
+		searchString := "%" + filter.SearchString + "%"
+		tx = tx.Joins("LEFT JOIN user_profiles ON user_profiles.user_id = users.id").
+			Where("(users.email ILIKE ? OR user_profiles.first_name ILIKE ? OR user_profiles.last_name ILIKE ?)", searchString, searchString, searchString)
+	}
+
+	if filter.RoleID != nil {
+		tx = tx.Where("users.role_id = ?", *filter.RoleID)
+	}
+
+	if filter.Suspended != nil {
+		if *filter.Suspended {
+			tx = tx.Where("users.suspended_at IS NOT NULL")
+		} else {
+			tx = tx.Where("users.suspended_at IS NULL")
+		}
+	}
+
+	return tx
+}
@@ -1,6 +1,8 @@
 package dbmodel
 
 import (
+	"time"
+
 	"feldrise.com/animal-api/pkg/model"
 	"gorm.io/gorm"
 )
@@ -17,6 +19,9 @@ type UserProfile struct {
 
 func (userProfile *UserProfile) ToModel() *model.UserProfile {
 	return &model.UserProfile{
+		ID:                   userProfile.ID,
+		CreatedAt:            userProfile.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:            userProfile.UpdatedAt.Format(time.RFC3339),
 		FirstName:            userProfile.FirstName,
 		LastName:             userProfile.LastName,
 		SocialSecurityNumber: userProfile.SocialSecurityNumber,
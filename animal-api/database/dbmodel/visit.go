@@ -2,49 +2,153 @@ package dbmodel
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"feldrise.com/animal-api/helper"
 	"feldrise.com/animal-api/pkg/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Visit struct {
 	gorm.Model
 
-	Date  time.Time `gorm:"not null"`
-	CatID uint      `gorm:"not null"`
+	// The calendars search the visits by date, of the whole clinic or of a
+	// veterinarian
+	Date     time.Time `gorm:"not null;index:idx_visits_date;index:idx_visits_vet_date,priority:2"`
+	AnimalID uint      `gorm:"not null;index"`
+
+	Reason            string `gorm:"not null;default:''"`
+	VetID             *uint  `gorm:"index:idx_visits_vet_date,priority:1"`
+	AppointmentTypeID *uint
+	Duration          int       `gorm:"not null;default:30"` // in minutes
+	Status            string    `gorm:"not null;default:'confirmed'"`
+	Notes             SOAPNotes `gorm:"embedded;embeddedPrefix:soap_"`
+
+	// Incremented on every change, for the calendar applications
+	Sequence int `gorm:"not null;default:0"`
+
+	SeriesID     *uint `gorm:"index"`
+	FollowUpOfID *uint
 
 	// Foreign object
-	Cat Cat `gorm:"foreignKey:CatID"`
+	Animal          Animal           `gorm:"foreignKey:AnimalID"`
+	Vet             *User            `gorm:"foreignKey:VetID;constraint:OnDelete:SET NULL"`
+	AppointmentType *AppointmentType `gorm:"foreignKey:AppointmentTypeID"`
+	FollowUpOf      *Visit           `gorm:"foreignKey:FollowUpOfID;constraint:OnDelete:SET NULL"`
+
+	Treatments  []*VisitTreatment  `gorm:"foreignKey:VisitID"`
+	Transitions []*VisitTransition `gorm:"foreignKey:VisitID"`
+}
+
+// SOAPNotes are stored in the soap_ columns of the visits
+type SOAPNotes struct {
+	Subjective string `gorm:"type:text;not null;default:''"`
+	Objective  string `gorm:"type:text;not null;default:''"`
+	Assessment string `gorm:"type:text;not null;default:''"`
+	Plan       string `gorm:"type:text;not null;default:''"`
 }
 
 func (visit *Visit) ToModel() *model.Visit {
-	var cat *model.Cat
+	var animal *model.Animal
+	var treatments []*model.VisitTreatment
+	var transitions []*model.VisitTransition
+
+	// The relations are only there when preloaded
+	if visit.Animal.ID != 0 {
+		animal = visit.Animal.ToModel()
+	}
+
+	if visit.Treatments != nil {
+		treatments = make([]*model.VisitTreatment, 0, len(visit.Treatments))
+
+		for _, treatment := range visit.Treatments {
+			treatments = append(treatments, treatment.ToModel())
+		}
+	}
+
+	if visit.Transitions != nil {
+		transitions = make([]*model.VisitTransition, 0, len(visit.Transitions))
 
-	if visit.CatID != 0 {
-		cat = visit.Cat.ToModel()
+		for _, transition := range visit.Transitions {
+			transitions = append(transitions, transition.ToModel())
+		}
 	}
 
 	return &model.Visit{
-		Date: visit.Date,
-		Cat:  cat,
+		ID:                visit.ID,
+		DeletedAt:         formatDeletedAt(visit.DeletedAt),
+		CreatedAt:         visit.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         visit.UpdatedAt.Format(time.RFC3339),
+		Links:             model.VisitLinks(visit.AnimalID, visit.ID),
+		Date:              visit.Date,
+		AnimalID:          visit.AnimalID,
+		Animal:            animal,
+		AppointmentTypeID: visit.AppointmentTypeID,
+		Reason:            visit.Reason,
+		VetID:             visit.VetID,
+		Duration:          visit.Duration,
+		Status:            visit.Status,
+		SeriesID:          visit.SeriesID,
+		FollowUpOfID:      visit.FollowUpOfID,
+		Notes: model.SOAPNotes{
+			Subjective: visit.Notes.Subjective,
+			Objective:  visit.Notes.Objective,
+			Assessment: visit.Notes.Assessment,
+			Plan:       visit.Notes.Plan,
+		},
+		Treatments:  treatments,
+		Transitions: transitions,
 	}
 }
 
 type VisitsFieldsToInclude struct {
-	Cat bool
+	Animal      bool
+	Owner       bool // the owner of the animal
+	Treatments  bool
+	Transitions bool
 }
 
 type VisitsFilter struct {
-	CatID uint
+	AnimalID uint
+	OwnerID  *uint      // only the visits of this owner's animals
+	VetID    *uint      // only the visits assigned to this veterinarian
+	Statuses []string   // only the visits of these statuses
+	From     *time.Time // only the visits ending after this date
+	To       *time.Time // only the visits starting before this date
+	Active   bool       // leave out the visits which don't take the time of their veterinarian
 }
 
+// ErrVisitConflict is returned when a visit would overlap another visit of
+// its veterinarian
+var ErrVisitConflict = errors.New("the veterinarian has another visit at this time")
+
+// ErrVisitVetUnavailable is returned when a visit is booked with a
+// veterinarian who was deleted or is suspended
+var ErrVisitVetUnavailable = errors.New("the veterinarian was deleted or is suspended")
+
+// ErrVisitStatusChanged is returned when the status of a visit was changed
+// by someone else meanwhile
+var ErrVisitStatusChanged = errors.New("the status of the visit was changed meanwhile")
+
 type VisitsRepository interface {
 	FindByID(id uint, fields *VisitsFieldsToInclude) (*Visit, error)
 	FindAll(filter *VisitsFilter, fields *VisitsFieldsToInclude) ([]*Visit, error)
 	Create(visit *Visit) (*Visit, error)
 	Update(visit *Visit) (*Visit, error)
+	Book(visit *Visit) (*Visit, error)
+	BookAll(visits []*Visit) error
+	BookSeries(series *VisitSeries, visits []*Visit) (*VisitSeries, error)
+	FindFollowing(visit *Visit) ([]*Visit, error)
+	Transition(visit *Visit, transition *VisitTransition, followUp *Visit, following ...*Visit) (*Visit, error)
 	Delete(visit *Visit) error
+
+	// Trash
+	FindDeleted() ([]*Visit, error)
+	FindDeletedByID(id uint) (*Visit, error)
+	Restore(visit *Visit) error
+	Purge(visit *Visit) error
 }
 
 type visitsRepository struct {
@@ -64,13 +168,29 @@ func (r *visitsRepository) FindByID(id uint, fields *VisitsFieldsToInclude) (*Vi
 	var visit Visit
 	tx := r.db.WithContext(ctx).Model(&visit)
 
-	if fields != nil && fields.Cat {
-		tx = tx.Preload("Cat")
+	if fields != nil && fields.Animal {
+		tx = tx.Preload("Animal.Species")
+	}
+
+	if fields != nil && fields.Treatments {
+		tx = tx.Preload("Treatments", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("id")
+		}).Preload("Treatments.Treatment", unscoped)
+	}
+
+	if fields != nil && fields.Transitions {
+		tx = tx.Preload("Transitions", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("id")
+		})
 	}
 
 	err := tx.Where("id = ?", id).First(&visit).Error
 
 	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
 		return nil, err
 	}
 
@@ -84,15 +204,45 @@ func (r *visitsRepository) FindAll(filter *VisitsFilter, fields *VisitsFieldsToI
 	var visits []*Visit
 	tx := r.db.WithContext(ctx).Model(&visits)
 
-	if fields != nil && fields.Cat {
-		tx = tx.Preload("Cat")
+	if fields != nil && fields.Animal {
+		tx = tx.Preload("Animal.Species")
+	}
+
+	if fields != nil && fields.Owner {
+		tx = tx.Preload("Animal.Owner")
+	}
+
+	if filter != nil && filter.AnimalID != 0 {
+		tx = tx.Where("visits.animal_id = ?", filter.AnimalID)
+	}
+
+	if filter != nil && filter.OwnerID != nil {
+		tx = tx.Joins("JOIN animals ON animals.id = visits.animal_id AND animals.deleted_at IS NULL").
+			Where("animals.owner_id = ?", *filter.OwnerID)
+	}
+
+	if filter != nil && filter.VetID != nil {
+		tx = tx.Where("visits.vet_id = ?", *filter.VetID)
+	}
+
+	// The bound on the start date alone lets the index be used
+	if filter != nil && filter.From != nil {
+		tx = tx.Where("visits.date > ? AND visits.date + visits.duration * INTERVAL '1 minute' > ?", filter.From.Add(-model.MaxVisitDuration*time.Minute), *filter.From)
+	}
+
+	if filter != nil && filter.To != nil {
+		tx = tx.Where("visits.date < ?", *filter.To)
 	}
 
-	if filter != nil {
-		tx = tx.Where("cat_id = ?", filter.CatID)
+	if filter != nil && len(filter.Statuses) > 0 {
+		tx = tx.Where("visits.status IN ?", filter.Statuses)
 	}
 
-	err := tx.Find(&visits).Error
+	if filter != nil && filter.Active {
+		tx = tx.Where("visits.status NOT IN ?", model.VisitFreeStatuses)
+	}
+
+	err := tx.Order("visits.date, visits.id").Find(&visits).Error
 
 	if err != nil {
 		return nil, err
@@ -127,6 +277,158 @@ func (r *visitsRepository) Update(visit *Visit) (*Visit, error) {
 	return visit, nil
 }
 
+// Book creates or saves the visit unless its veterinarian has another visit
+// overlapping it, ErrVisitConflict is returned then
+func (r *visitsRepository) Book(visit *Visit) (*Visit, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return book(tx, visit, visit.ID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return visit, nil
+}
+
+// BookAll saves the visits of a series together, ErrVisitConflict is
+// returned when one of them overlaps another visit of its veterinarian
+// outside the series
+func (r *visitsRepository) BookAll(visits []*Visit) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids := make([]uint, 0, len(visits))
+
+	for _, visit := range visits {
+		ids = append(ids, visit.ID)
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, visit := range visits {
+			if err := book(tx, visit, ids...); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// BookSeries creates the series and its visits, ErrVisitConflict is returned
+// when one of them overlaps another visit of its veterinarian
+func (r *visitsRepository) BookSeries(series *VisitSeries, visits []*Visit) (*VisitSeries, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(series).Error; err != nil {
+			return err
+		}
+
+		for _, visit := range visits {
+			visit.SeriesID = &series.ID
+
+			if err := book(tx, visit); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	series.Visits = visits
+
+	return series, nil
+}
+
+// FindFollowing returns the open visits of the series of the visit after it,
+// the earliest first
+func (r *visitsRepository) FindFollowing(visit *Visit) ([]*Visit, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var visits []*Visit
+
+	if visit.SeriesID == nil {
+		return visits, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("series_id = ? AND id <> ? AND date >= ? AND status NOT IN ?", *visit.SeriesID, visit.ID, visit.Date, model.VisitClosedStatuses).
+		Order("date, id").
+		Find(&visits).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return visits, nil
+}
+
+// Transition changes the status of the visit and records the transition,
+// ErrVisitStatusChanged is returned when the visit no longer has the status
+// the transition starts from. The follow-up, if any, is booked along, and the
+// following visits take the same transition from their own status in the
+// same transaction, the ones whose status changed meanwhile left aside.
+func (r *visitsRepository) Transition(visit *Visit, transition *VisitTransition, followUp *Visit, following ...*Visit) (*Visit, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var changed []*Visit
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := transitionVisit(tx, visit, transition); err != nil {
+			return err
+		}
+
+		for _, followingVisit := range following {
+			err := transitionVisit(tx, followingVisit, &VisitTransition{
+				UserID:     transition.UserID,
+				FromStatus: followingVisit.Status,
+				ToStatus:   transition.ToStatus,
+				Reason:     transition.Reason,
+			})
+
+			if err == ErrVisitStatusChanged {
+				continue
+			}
+
+			if err != nil {
+				return err
+			}
+
+			changed = append(changed, followingVisit)
+		}
+
+		if followUp == nil {
+			return nil
+		}
+
+		followUp.FollowUpOfID = &visit.ID
+
+		return book(tx, followUp)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, changedVisit := range append(changed, visit) {
+		changedVisit.Status = transition.ToStatus
+		changedVisit.Sequence++
+	}
+
+	return visit, nil
+}
+
 func (r *visitsRepository) Delete(visit *Visit) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -139,3 +441,170 @@ func (r *visitsRepository) Delete(visit *Visit) error {
 
 	return nil
 }
+
+func (r *visitsRepository) FindDeleted() ([]*Visit, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var visits []*Visit
+	err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&visits).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return visits, nil
+}
+
+func (r *visitsRepository) FindDeletedByID(id uint) (*Visit, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var visit Visit
+	err := r.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&visit).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &visit, nil
+}
+
+// Restore takes the visit out of the trash, ErrVisitConflict is returned
+// when its time was given to another visit of its veterinarian meanwhile
+func (r *visitsRepository) Restore(visit *Visit) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := checkVetFree(tx, visit, visit.ID); err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(visit).UpdateColumn("deleted_at", nil).Error
+	})
+
+	if err != nil {
+		return err
+	}
+
+	visit.DeletedAt = gorm.DeletedAt{}
+
+	return nil
+}
+
+// Purge permanently deletes the visit, its treatments and its transitions
+func (r *visitsRepository) Purge(visit *Visit) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("visit_id = ?", visit.ID).Delete(&VisitTreatment{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("visit_id = ?", visit.ID).Delete(&VisitTransition{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Delete(visit).Error
+	})
+}
+
+// book creates or saves the visit in the transaction unless its veterinarian
+// has another visit overlapping it, the excluded visits left aside, or was
+// deleted or suspended
+func book(tx *gorm.DB, visit *Visit, excluded ...uint) error {
+	vet, err := checkVetFree(tx, visit, excluded...)
+	if err != nil {
+		return err
+	}
+
+	if vet != nil && (vet.DeletedAt.Valid || vet.SuspendedAt != nil) {
+		return ErrVisitVetUnavailable
+	}
+
+	if visit.ID == 0 {
+		return tx.Omit(clause.Associations).Save(visit).Error
+	}
+
+	// The status of a booked visit only changes through Transition
+	err = tx.Omit(clause.Associations, "Status", "Sequence").Save(visit).Error
+	if err != nil {
+		return err
+	}
+
+	visit.Sequence++
+
+	return tx.Model(visit).UpdateColumn("sequence", gorm.Expr("sequence + 1")).Error
+}
+
+// checkVetFree returns ErrVisitConflict when the veterinarian of the visit has
+// another visit overlapping it, the excluded visits left aside. The
+// veterinarian is locked and returned, so two bookings can't both find the
+// time free. Nothing is checked for the visits without veterinarian or
+// taking no time, nil is returned then.
+func checkVetFree(tx *gorm.DB, visit *Visit, excluded ...uint) (*User, error) {
+	if visit.VetID == nil || helper.Contains(model.VisitFreeStatuses, visit.Status) {
+		return nil, nil
+	}
+
+	// The deleted veterinarians keep their visits, they are locked as well
+	var vet User
+	err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "deleted_at", "suspended_at").Where("id = ?", *visit.VetID).First(&vet).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, ErrVisitVetUnavailable
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	end := visit.Date.Add(time.Duration(visit.Duration) * time.Minute)
+
+	query := tx.Model(&Visit{}).
+		Where("vet_id = ? AND status NOT IN ?", *visit.VetID, model.VisitFreeStatuses).
+		Where("date < ? AND date + duration * INTERVAL '1 minute' > ?", end, visit.Date)
+
+	if len(excluded) > 0 {
+		query = query.Where("id NOT IN ?", excluded)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, err
+	}
+
+	if count > 0 {
+		return nil, ErrVisitConflict
+	}
+
+	return &vet, nil
+}
+
+// transitionVisit changes the status of the visit in the transaction and
+// records the transition, unless the visit no longer has the status the
+// transition starts from
+func transitionVisit(tx *gorm.DB, visit *Visit, transition *VisitTransition) error {
+	result := tx.Model(visit).Where("status = ?", transition.FromStatus).Updates(map[string]interface{}{
+		"status":   transition.ToStatus,
+		"sequence": gorm.Expr("sequence + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return ErrVisitStatusChanged
+	}
+
+	transition.VisitID = visit.ID
+
+	return tx.Omit(clause.Associations).Create(transition).Error
+}
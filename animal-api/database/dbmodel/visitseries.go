@@ -0,0 +1,36 @@
+package dbmodel
+
+import (
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+)
+
+// VisitSeries groups the visits created from a recurrence, the visits are
+// booked one by one and then changed on their own or with the following ones
+type VisitSeries struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	AnimalID   uint   `gorm:"not null;index"`
+	Recurrence string `gorm:"not null"`
+
+	Visits []*Visit `gorm:"foreignKey:SeriesID;constraint:OnDelete:SET NULL"`
+}
+
+func (series *VisitSeries) ToModel() *model.VisitSeries {
+	visits := make([]*model.Visit, 0, len(series.Visits))
+
+	for _, visit := range series.Visits {
+		visits = append(visits, visit.ToModel())
+	}
+
+	return &model.VisitSeries{
+		ID:         series.ID,
+		CreatedAt:  series.CreatedAt.Format(time.RFC3339),
+		Links:      model.VisitSeriesLinks(series.AnimalID),
+		AnimalID:   series.AnimalID,
+		Recurrence: series.Recurrence,
+		Visits:     visits,
+	}
+}
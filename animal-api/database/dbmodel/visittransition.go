@@ -0,0 +1,34 @@
+package dbmodel
+
+import (
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+)
+
+// VisitTransition records who changed the status of a visit and why, the
+// rows are never changed
+type VisitTransition struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+
+	VisitID    uint   `gorm:"not null;index"`
+	UserID     *uint  `gorm:"index"`
+	FromStatus string `gorm:"not null"`
+	ToStatus   string `gorm:"not null"`
+	Reason     string `gorm:"type:text;not null;default:''"`
+
+	// Foreign object
+	User *User `gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL"`
+}
+
+func (transition *VisitTransition) ToModel() *model.VisitTransition {
+	return &model.VisitTransition{
+		ID:        transition.ID,
+		CreatedAt: transition.CreatedAt.Format(time.RFC3339),
+		UserID:    transition.UserID,
+		From:      transition.FromStatus,
+		To:        transition.ToStatus,
+		Reason:    transition.Reason,
+	}
+}
@@ -0,0 +1,157 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+)
+
+// VisitTreatment is a treatment of the catalog given or prescribed during a
+// visit
+type VisitTreatment struct {
+	gorm.Model
+
+	AnimalID    uint `gorm:"not null;index"` // the animal of the visit, for its history
+	VisitID     uint `gorm:"not null;index"`
+	TreatmentID uint `gorm:"not null;index"`
+
+	Kind         string  `gorm:"not null"` // administered or prescribed
+	Dosage       float64 `gorm:"not null"`
+	Unit         string  `gorm:"not null"`
+	Route        string  `gorm:"not null"`
+	Frequency    string  `gorm:"not null;default:''"`
+	DurationDays *int
+	Notes        string `gorm:"type:text;not null;default:''"`
+
+	// Foreign objects
+	Visit     *Visit    `gorm:"foreignKey:VisitID"`
+	Treatment Treatment `gorm:"foreignKey:TreatmentID"`
+}
+
+func (treatment *VisitTreatment) ToModel() *model.VisitTreatment {
+	var catalog *model.Treatment
+	var visitDate *time.Time
+
+	// The relations are only there when preloaded
+	if treatment.Treatment.ID != 0 {
+		catalog = treatment.Treatment.ToModel()
+	}
+
+	if treatment.Visit != nil {
+		visitDate = &treatment.Visit.Date
+	}
+
+	return &model.VisitTreatment{
+		ID:           treatment.ID,
+		CreatedAt:    treatment.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    treatment.UpdatedAt.Format(time.RFC3339),
+		Links:        model.VisitTreatmentLinks(treatment.AnimalID, treatment.VisitID, treatment.ID, treatment.TreatmentID),
+		VisitID:      treatment.VisitID,
+		VisitDate:    visitDate,
+		TreatmentID:  treatment.TreatmentID,
+		Treatment:    catalog,
+		Kind:         treatment.Kind,
+		Dosage:       treatment.Dosage,
+		Unit:         treatment.Unit,
+		Route:        treatment.Route,
+		Frequency:    treatment.Frequency,
+		DurationDays: treatment.DurationDays,
+		Notes:        treatment.Notes,
+	}
+}
+
+type VisitTreatmentsRepository interface {
+	FindByID(id uint, visitID uint) (*VisitTreatment, error)
+	FindByAnimal(animalID uint) ([]*VisitTreatment, error)
+	Create(treatment *VisitTreatment) (*VisitTreatment, error)
+	Update(treatment *VisitTreatment) (*VisitTreatment, error)
+	Delete(treatment *VisitTreatment) error
+}
+
+type visitTreatmentsRepository struct {
+	db *gorm.DB
+}
+
+func NewVisitTreatmentsRepository(db *gorm.DB) VisitTreatmentsRepository {
+	return &visitTreatmentsRepository{
+		db: db,
+	}
+}
+
+func (r *visitTreatmentsRepository) FindByID(id uint, visitID uint) (*VisitTreatment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var treatment VisitTreatment
+	err := r.db.WithContext(ctx).Preload("Treatment", unscoped).Where("id = ? AND visit_id = ?", id, visitID).First(&treatment).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &treatment, nil
+}
+
+// FindByAnimal returns the treatments of all the visits of an animal, the
+// most recent visit first. The visits in the trash are left out.
+func (r *visitTreatmentsRepository) FindByAnimal(animalID uint) ([]*VisitTreatment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var treatments []*VisitTreatment
+	err := r.db.WithContext(ctx).Preload("Treatment", unscoped).Preload("Visit").
+		Joins("JOIN visits ON visits.id = visit_treatments.visit_id AND visits.deleted_at IS NULL").
+		Where("visit_treatments.animal_id = ?", animalID).
+		Order("visits.date DESC, visit_treatments.id").
+		Find(&treatments).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return treatments, nil
+}
+
+func (r *visitTreatmentsRepository) Create(treatment *VisitTreatment) (*VisitTreatment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Omit("Visit", "Treatment").Create(treatment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return treatment, nil
+}
+
+func (r *visitTreatmentsRepository) Update(treatment *VisitTreatment) (*VisitTreatment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Omit("Visit", "Treatment").Save(treatment).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return treatment, nil
+}
+
+func (r *visitTreatmentsRepository) Delete(treatment *VisitTreatment) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Unscoped().Delete(treatment).Error
+}
+
+// unscoped preloads the records in the trash too
+func unscoped(tx *gorm.DB) *gorm.DB {
+	return tx.Unscoped()
+}
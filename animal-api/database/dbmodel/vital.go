@@ -0,0 +1,220 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Vital is a set of measurements taken at the same time, during a visit or
+// on its own
+type Vital struct {
+	gorm.Model
+
+	AnimalID   uint      `gorm:"not null;index:idx_vitals_animal_recorded_at"`
+	VisitID    *uint     `gorm:"index"`
+	RecordedAt time.Time `gorm:"not null;index:idx_vitals_animal_recorded_at"`
+
+	Weight             *float64 // kg
+	Temperature        *float64 // °C
+	HeartRate          *int     // bpm
+	RespiratoryRate    *int     // breaths per minute
+	BodyConditionScore *int     // 1 to 9
+
+	// Foreign objects
+	Animal Animal `gorm:"foreignKey:AnimalID"`
+	Visit  *Visit `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL;"`
+}
+
+func (vital *Vital) ToModel() *model.Vital {
+	return &model.Vital{
+		ID:                 vital.ID,
+		CreatedAt:          vital.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:          vital.UpdatedAt.Format(time.RFC3339),
+		Links:              model.VitalLinks(vital.AnimalID, vital.ID),
+		AnimalID:           vital.AnimalID,
+		VisitID:            vital.VisitID,
+		RecordedAt:         vital.RecordedAt,
+		Weight:             vital.Weight,
+		Temperature:        vital.Temperature,
+		HeartRate:          vital.HeartRate,
+		RespiratoryRate:    vital.RespiratoryRate,
+		BodyConditionScore: vital.BodyConditionScore,
+	}
+}
+
+type VitalsFilter struct {
+	AnimalID uint
+	From     *time.Time
+	To       *time.Time
+}
+
+type VitalsRepository interface {
+	FindByID(id uint, animalID uint) (*Vital, error)
+	FindAll(filter *VitalsFilter) ([]*Vital, error)
+	FindLastWeights(animalID uint, period time.Duration) ([]*Vital, error)
+	Series(filter *VitalsFilter, bucket string) ([]model.VitalsPoint, error)
+	Create(vital *Vital) (*Vital, error)
+	Update(vital *Vital) (*Vital, error)
+	Delete(vital *Vital) error
+}
+
+type vitalsRepository struct {
+	db *gorm.DB
+}
+
+func NewVitalsRepository(db *gorm.DB) VitalsRepository {
+	return &vitalsRepository{
+		db: db,
+	}
+}
+
+func (r *vitalsRepository) FindByID(id uint, animalID uint) (*Vital, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var vital Vital
+	err := r.db.WithContext(ctx).Where("id = ? AND animal_id = ?", id, animalID).First(&vital).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &vital, nil
+}
+
+func (r *vitalsRepository) FindAll(filter *VitalsFilter) ([]*Vital, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var vitals []*Vital
+	tx := filterVitals(r.db.WithContext(ctx).Model(&Vital{}), filter)
+
+	err := tx.Order("recorded_at, id").Find(&vitals).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return vitals, nil
+}
+
+// FindLastWeights returns the weights measured during the period before the
+// last one, the oldest first
+func (r *vitalsRepository) FindLastWeights(animalID uint, period time.Duration) ([]*Vital, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var last Vital
+	tx := r.db.WithContext(ctx).Where("animal_id = ? AND weight IS NOT NULL", animalID)
+
+	err := tx.Order("recorded_at DESC").First(&last).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var vitals []*Vital
+	err = r.db.WithContext(ctx).
+		Where("animal_id = ? AND weight IS NOT NULL AND recorded_at >= ?", animalID, last.RecordedAt.Add(-period)).
+		Order("recorded_at, id").
+		Find(&vitals).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return vitals, nil
+}
+
+// Series averages the measurements by day, week or month
+func (r *vitalsRepository) Series(filter *VitalsFilter, bucket string) ([]model.VitalsPoint, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	points := []model.VitalsPoint{}
+	tx := filterVitals(r.db.WithContext(ctx).Model(&Vital{}), filter)
+
+	err := tx.Select(`date_trunc(?, recorded_at) AS date,
+		count(*) AS count,
+		avg(weight)::float8 AS weight,
+		avg(temperature)::float8 AS temperature,
+		avg(heart_rate)::float8 AS heart_rate,
+		avg(respiratory_rate)::float8 AS respiratory_rate,
+		avg(body_condition_score)::float8 AS body_condition_score`, bucket).
+		Group("date").
+		Order("date").
+		Scan(&points).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return points, nil
+}
+
+func (r *vitalsRepository) Create(vital *Vital) (*Vital, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Create(vital).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return vital, nil
+}
+
+func (r *vitalsRepository) Update(vital *Vital) (*Vital, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.db.WithContext(ctx).Omit(clause.Associations).Save(vital).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return vital, nil
+}
+
+// Delete permanently deletes the measurement, they only go to the trash with
+// their animal
+func (r *vitalsRepository) Delete(vital *Vital) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Unscoped().Delete(vital).Error
+}
+
+func filterVitals(tx *gorm.DB, filter *VitalsFilter) *gorm.DB {
+	if filter == nil {
+		return tx
+	}
+
+	if filter.AnimalID != 0 {
+		tx = tx.Where("animal_id = ?", filter.AnimalID)
+	}
+
+	if filter.From != nil {
+		tx = tx.Where("recorded_at >= ?", *filter.From)
+	}
+
+	if filter.To != nil {
+		tx = tx.Where("recorded_at <= ?", *filter.To)
+	}
+
+	return tx
+}
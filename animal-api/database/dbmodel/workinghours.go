@@ -0,0 +1,94 @@
+package dbmodel
+
+import (
+	"context"
+	"time"
+
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+)
+
+// WorkingHours is a period a veterinarian works every week, in the time zone
+// of the clinic
+type WorkingHours struct {
+	ID uint `gorm:"primarykey"`
+
+	VetID   uint `gorm:"not null;index"`
+	Weekday int  `gorm:"not null"` // from 0 for Sunday to 6 for Saturday
+	Start   int  `gorm:"not null"` // in minutes since midnight
+	End     int  `gorm:"not null"` // in minutes since midnight
+
+	// Foreign object
+	Vet User `gorm:"foreignKey:VetID;constraint:OnDelete:CASCADE"`
+}
+
+func (hours *WorkingHours) Period() model.WorkingPeriod {
+	return model.WorkingPeriod{
+		Weekday: time.Weekday(hours.Weekday),
+		Start:   hours.Start,
+		End:     hours.End,
+	}
+}
+
+type WorkingHoursRepository interface {
+	FindByVet(vetID uint) ([]*WorkingHours, error)
+	Replace(vetID uint, periods []model.WorkingPeriod) ([]*WorkingHours, error)
+}
+
+type workingHoursRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkingHoursRepository(db *gorm.DB) WorkingHoursRepository {
+	return &workingHoursRepository{
+		db: db,
+	}
+}
+
+// FindByVet returns the working hours of a veterinarian through the week,
+// starting on Sunday
+func (r *workingHoursRepository) FindByVet(vetID uint) ([]*WorkingHours, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var hours []*WorkingHours
+	err := r.db.WithContext(ctx).Where("vet_id = ?", vetID).Order("weekday, start").Find(&hours).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
+
+// Replace sets all the working hours of a veterinarian at once
+func (r *workingHoursRepository) Replace(vetID uint, periods []model.WorkingPeriod) ([]*WorkingHours, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hours := make([]*WorkingHours, 0, len(periods))
+
+	for _, period := range periods {
+		hours = append(hours, &WorkingHours{
+			VetID:   vetID,
+			Weekday: int(period.Weekday),
+			Start:   period.Start,
+			End:     period.End,
+		})
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("vet_id = ?", vetID).Delete(&WorkingHours{}).Error
+		if err != nil || len(hours) == 0 {
+			return err
+		}
+
+		return tx.Omit("Vet").Create(&hours).Error
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hours, nil
+}
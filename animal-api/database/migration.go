@@ -0,0 +1,79 @@
+package database
+
+import (
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/database/seed"
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+)
+
+// migrateCatsToAnimals renames the cats to animals before the automatic
+// migration, which would otherwise create an empty animals table. The
+// existing cats keep their id, so the visits stay attached to them.
+func migrateCatsToAnimals(database *gorm.DB) error {
+	migrator := database.Migrator()
+
+	if !migrator.HasTable("cats") || migrator.HasTable("animals") {
+		return nil
+	}
+
+	return database.Transaction(func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&dbmodel.Species{}); err != nil {
+			return err
+		}
+
+		if err := seed.SeedV4(tx); err != nil {
+			return err
+		}
+
+		var cat dbmodel.Species
+		if err := tx.Where("code = ?", model.SpeciesCat).First(&cat).Error; err != nil {
+			return err
+		}
+
+		statements := []string{
+			`ALTER TABLE cats RENAME TO animals`,
+			`ALTER SEQUENCE IF EXISTS cats_id_seq RENAME TO animals_id_seq`,
+			`ALTER INDEX IF EXISTS cats_pkey RENAME TO animals_pkey`,
+			`ALTER INDEX IF EXISTS idx_cats_deleted_at RENAME TO idx_animals_deleted_at`,
+			`ALTER INDEX IF EXISTS idx_cats_owner_id RENAME TO idx_animals_owner_id`,
+			`ALTER INDEX IF EXISTS idx_cats_microchip RENAME TO idx_animals_microchip`,
+			`ALTER INDEX IF EXISTS idx_cats_tattoo RENAME TO idx_animals_tattoo`,
+			`ALTER TABLE animals DROP CONSTRAINT IF EXISTS fk_cats_owner`,
+			`ALTER TABLE visits DROP CONSTRAINT IF EXISTS fk_cats_visists`,
+			`ALTER TABLE visits DROP CONSTRAINT IF EXISTS fk_visits_cat`,
+			`ALTER TABLE visits RENAME COLUMN cat_id TO animal_id`,
+			`ALTER TABLE animals ADD COLUMN species_id bigint`,
+		}
+
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Exec(`UPDATE animals SET species_id = ?`, cat.ID).Error
+	})
+}
+
+// migratePhotoKeys drops the unique index of the photo keys, which became
+// the hash of their content and are shared by the photos of the same file
+func migratePhotoKeys(database *gorm.DB) error {
+	return database.Exec(`DROP INDEX IF EXISTS idx_photos_key`).Error
+}
+
+// migrateVisitStatuses renames the scheduled visits to confirmed, the status
+// they have in the lifecycle of the visits
+func migrateVisitStatuses(database *gorm.DB) error {
+	if !database.Migrator().HasColumn(&dbmodel.Visit{}, "Status") {
+		return nil
+	}
+
+	return database.Exec(`UPDATE visits SET status = ? WHERE status = 'scheduled'`, model.VisitStatusConfirmed).Error
+}
+
+// migrateVisitIndexes drops the index of the veterinarians of the visits,
+// replaced by the index of their veterinarian and date
+func migrateVisitIndexes(database *gorm.DB) error {
+	return database.Exec(`DROP INDEX IF EXISTS idx_visits_vet_id`).Error
+}
@@ -0,0 +1,15 @@
+package seed
+
+import (
+	"feldrise.com/animal-api/database/dbmodel"
+	"gorm.io/gorm"
+)
+
+// SeedV2 considers the emails of the users created before the email
+// verification as verified, so they are not locked out of the routes
+// requiring it
+func SeedV2(database *gorm.DB) error {
+	return database.Model(&dbmodel.User{}).
+		Where("email_verifed_at IS NULL").
+		Update("email_verifed_at", gorm.Expr("created_at")).Error
+}
@@ -0,0 +1,30 @@
+package seed
+
+import (
+	"log"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+)
+
+// SeedV3 reports the accounts with the veterinaire role. The registration gave
+// that role until the roles were enforced, so nothing tells the veterinarians
+// apart from the clients who registered: their role is left as it is and an
+// admin reviews the listed ids, changing the role of the clients.
+func SeedV3(database *gorm.DB) error {
+	var ids []uint
+
+	err := database.Model(&dbmodel.User{}).
+		Where("role_id = ?", model.RoleVeterinaire).
+		Order("id").
+		Pluck("id", &ids).Error
+
+	if err != nil || len(ids) == 0 {
+		return err
+	}
+
+	log.Printf("%d users have the veterinaire role and may have registered as clients, an admin should review their role: %v", len(ids), ids)
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package seed
+
+import (
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SeedV4 creates the species of the animals treated by the clinic. It is also
+// applied by the migration of the cats, so it must be idempotent.
+func SeedV4(database *gorm.DB) error {
+	species := []dbmodel.Species{
+		{
+			Model: gorm.Model{ID: 1},
+			Code:  model.SpeciesCat,
+			Name:  "Cat",
+			Attributes: dbmodel.JSONMap{
+				"indoor":      model.AttributeBoolean,
+				"fiv_status":  model.AttributeString,
+				"felv_status": model.AttributeString,
+			},
+		},
+		{
+			Model: gorm.Model{ID: 2},
+			Code:  model.SpeciesDog,
+			Name:  "Dog",
+			Attributes: dbmodel.JSONMap{
+				"size":            model.AttributeString,
+				"weight_category": model.AttributeString,
+				"pedigree_number": model.AttributeString,
+			},
+		},
+		{
+			Model: gorm.Model{ID: 3},
+			Code:  model.SpeciesRabbit,
+			Name:  "Rabbit",
+			Attributes: dbmodel.JSONMap{
+				"ear_type": model.AttributeString,
+				"indoor":   model.AttributeBoolean,
+			},
+		},
+		{
+			Model: gorm.Model{ID: 4},
+			Code:  model.SpeciesNAC,
+			Name:  "New companion animal",
+			Attributes: dbmodel.JSONMap{
+				"kind": model.AttributeString, // ferret, guinea pig, bird...
+			},
+		},
+	}
+
+	return database.Clauses(clause.OnConflict{DoNothing: true}).Create(&species).Error
+}
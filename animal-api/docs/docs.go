@@ -15,28 +15,47 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/authentication/check-email": {
+        "/.well-known/jwks.json": {
             "get": {
-                "description": "Check if the email exists",
+                "description": "Get the public keys verifying the access tokens, as a JSON Web Key Set",
                 "tags": [
                     "autentication"
                 ],
-                "summary": "Check if the email exists",
-                "operationId": "check-if-email-exists",
+                "summary": "Get the token verification keys",
+                "operationId": "jwks",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/JSONWebKeySet"
+                        }
+                    }
+                }
+            }
+        },
+        "/animals": {
+            "get": {
+                "description": "Get all animals, clients only get the animals they own. The /cat route only returns cats.",
+                "tags": [
+                    "animals"
+                ],
+                "summary": "Get all animals",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "email",
-                        "name": "email",
-                        "in": "query",
-                        "required": true
+                        "description": "only the animals of this species",
+                        "name": "species",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Animal"
+                            }
                         }
                     },
                     "400": {
@@ -58,32 +77,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/authentication/login": {
+            },
             "post": {
-                "description": "Login a user",
+                "description": "Create an animal, clients own the animals they create while the staff can set their owner. The species is required, except on the /cat route.",
                 "tags": [
-                    "autentication"
+                    "animals"
                 ],
-                "summary": "Login a user",
-                "operationId": "login",
+                "summary": "Create an animal",
                 "parameters": [
                     {
-                        "description": "user's info",
-                        "name": "request",
+                        "description": "Animal data",
+                        "name": "animal",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/LoginPostPayload"
+                            "$ref": "#/definitions/AnimalCreatePayload"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "ok",
+                    "201": {
+                        "description": "created",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Animal"
                         }
                     },
                     "400": {
@@ -98,6 +114,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "identifier already registered",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -107,17 +135,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/authentication/me": {
+        "/animals/by-chip/{number}": {
             "get": {
-                "description": "Get the current user",
+                "description": "Identify an animal, with its owner, from its ISO 11784 microchip number. Only available to the staff.",
                 "tags": [
-                    "autentication"
+                    "animals"
+                ],
+                "summary": "Get an animal by its microchip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Microchip number, 15 digits",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get the current user",
-                "operationId": "me",
                 "responses": {
                     "200": {
                         "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Animal"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
                         "schema": {
                             "type": "string"
                         }
@@ -128,6 +170,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -137,36 +191,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/authentication/register": {
-            "post": {
-                "description": "Register a new user",
+        "/animals/species": {
+            "get": {
+                "description": "Get the species treated by the clinic, with the optional attributes of their animals",
                 "tags": [
-                    "autentication"
-                ],
-                "summary": "Register a new user",
-                "operationId": "register",
-                "parameters": [
-                    {
-                        "description": "user's info",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/RegisterPostPayload"
-                        }
-                    }
+                    "animals"
                 ],
+                "summary": "Get the species",
                 "responses": {
-                    "201": {
-                        "description": "created",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "bad request",
+                    "200": {
+                        "description": "ok",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Species"
+                            }
                         }
                     },
                     "401": {
@@ -184,31 +223,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/authentication/{id}": {
-            "put": {
-                "description": "Update the current user",
+        "/animals/{id}": {
+            "get": {
+                "description": "Get an animal by its id, the /cat route only finds cats",
                 "tags": [
-                    "autentication"
+                    "animals"
                 ],
-                "summary": "Update the current user",
-                "operationId": "update",
+                "summary": "Get an animal",
                 "parameters": [
                     {
-                        "description": "user's info",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "vitals to include the animal's vitals",
+                        "name": "include",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Animal"
                         }
                     },
                     "400": {
@@ -223,6 +264,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -230,23 +277,42 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/cats": {
-            "get": {
-                "description": "Get all cats",
+            },
+            "put": {
+                "description": "Update an animal, the /cat route only finds cats",
                 "tags": [
-                    "cats"
+                    "animals"
+                ],
+                "summary": "Update an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Animal data",
+                        "name": "animal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AnimalUpdatePayload"
+                        }
+                    }
                 ],
-                "summary": "Get all cats",
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/Cat"
-                            }
+                            "$ref": "#/definitions/Animal"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrResponse"
                         }
                     },
                     "401": {
@@ -255,6 +321,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "identifier already registered",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -263,29 +341,24 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create a cat",
+            "delete": {
+                "description": "Move an animal and its visits to the trash, only available to the staff",
                 "tags": [
-                    "cats"
+                    "animals"
                 ],
-                "summary": "Create a cat",
+                "summary": "Delete an animal",
                 "parameters": [
                     {
-                        "description": "Cat data",
-                        "name": "cat",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/CatCreatePayload"
-                        }
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "ok",
-                        "schema": {
-                            "$ref": "#/definitions/Cat"
-                        }
+                    "204": {
+                        "description": "no content"
                     },
                     "400": {
                         "description": "bad request",
@@ -299,6 +372,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -308,18 +393,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/cats/{catid}/visits": {
+        "/animals/{id}/photos": {
             "get": {
-                "description": "Get all visits for a specific cat",
+                "description": "Get the photos of an animal, the most recent first. Their files are served by the signed URLs.",
                 "tags": [
-                    "visits"
+                    "photos"
                 ],
-                "summary": "Get all visits",
+                "summary": "Get the photos of an animal",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cat ID",
-                        "name": "catid",
+                        "description": "Animal ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -330,7 +415,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/Visit"
+                                "$ref": "#/definitions/Photo"
                             }
                         }
                     },
@@ -346,6 +431,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -355,40 +446,35 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a visit for a specific cat",
+                "description": "Upload a JPEG, PNG or HEIC photo of an animal. Its metadata are removed and it is stored in a large, a medium and a thumbnail size. The first photo of an animal becomes its profile picture.",
                 "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "tags": [
-                    "visits"
+                    "photos"
                 ],
-                "summary": "Create a visit",
+                "summary": "Upload a photo of an animal",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cat ID",
-                        "name": "catid",
+                        "description": "Animal ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Visit info",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/Visit"
-                        }
+                        "type": "file",
+                        "description": "JPEG, PNG or HEIC photo",
+                        "name": "photo",
+                        "in": "formData",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "ok",
+                    "201": {
+                        "description": "created",
                         "schema": {
-                            "$ref": "#/definitions/Visit"
+                            "$ref": "#/definitions/Photo"
                         }
                     },
                     "400": {
@@ -403,6 +489,30 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the same photo is being stored",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "photo too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported photo format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -412,35 +522,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/cats/{catid}/visits/{id}": {
-            "get": {
-                "description": "Get a visit by ID and for a specific cat",
+        "/animals/{id}/photos/{photoid}": {
+            "delete": {
+                "description": "Permanently delete a photo of an animal, and its files unless another photo has the same file",
                 "tags": [
-                    "visits"
+                    "photos"
                 ],
-                "summary": "Get a visit",
+                "summary": "Delete a photo",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cat ID",
-                        "name": "catid",
+                        "description": "Animal ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Visit ID",
-                        "name": "id",
+                        "description": "Photo ID",
+                        "name": "photoid",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "ok",
-                        "schema": {
-                            "$ref": "#/definitions/Visit"
-                        }
+                    "204": {
+                        "description": "no content"
                     },
                     "400": {
                         "description": "bad request",
@@ -454,6 +561,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -461,50 +574,89 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a visit for a specific cat",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            }
+        },
+        "/animals/{id}/photos/{photoid}/profile": {
+            "post": {
+                "description": "Make a photo the profile picture of its animal",
                 "tags": [
-                    "visits"
+                    "photos"
                 ],
-                "summary": "Update a visit",
+                "summary": "Choose the profile picture of an animal",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cat ID",
-                        "name": "catid",
+                        "description": "Animal ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Visit ID",
-                        "name": "id",
+                        "description": "Photo ID",
+                        "name": "photoid",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Photo"
+                        }
                     },
-                    {
-                        "description": "Visit info",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "type": "string"
                         }
                     }
+                }
+            }
+        },
+        "/animals/{id}/treatments": {
+            "get": {
+                "description": "Get the treatments given or prescribed during all the visits of an animal, the most recent visit first",
+                "tags": [
+                    "treatments"
+                ],
+                "summary": "Get the treatment history of an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/Visit"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/VisitTreatment"
+                            }
                         }
                     },
                     "400": {
@@ -519,6 +671,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -528,27 +686,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/cats/{id}": {
+        "/animals/{id}/vitals": {
             "get": {
-                "description": "Get a cat by its id",
+                "description": "Get the measurements taken on an animal, the oldest first",
                 "tags": [
-                    "cats"
+                    "vitals"
                 ],
-                "summary": "Get a cat",
+                "summary": "Get the vitals of an animal",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cat ID",
+                        "description": "Animal ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the measurements taken since this date, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the measurements taken until this date, RFC 3339",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "ok",
                         "schema": {
-                            "$ref": "#/definitions/Cat"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Vital"
+                            }
                         }
                     },
                     "400": {
@@ -563,6 +736,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -571,36 +750,35 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update a cat",
+            "post": {
+                "description": "Record measurements taken on an animal, during one of its visits or on their own. Only available to the staff.",
                 "tags": [
-                    "cats"
+                    "vitals"
                 ],
-                "summary": "Update a cat",
+                "summary": "Record vitals",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Cat ID",
+                        "description": "Animal ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Cat data",
-                        "name": "cat",
+                        "description": "Vital data",
+                        "name": "vital",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/VitalCreatePayload"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "ok",
+                    "201": {
+                        "description": "created",
                         "schema": {
-                            "$ref": "#/definitions/Cat"
+                            "$ref": "#/definitions/Vital"
                         }
                     },
                     "400": {
@@ -615,6 +793,18 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "500": {
                         "description": "internal server error",
                         "schema": {
@@ -623,81 +813,7547 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "Cat": {
-            "type": "object",
-            "properties": {
-                "name": {
-                    "type": "string"
-                }
-            }
-        },
-        "CatCreatePayload": {
-            "type": "object",
-            "properties": {
-                "name": {
-                    "type": "string"
-                }
-            }
         },
-        "LoginPostPayload": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
-                    "description": "the user's email",
-                    "type": "string",
-                    "example": "admin@feldrise.com"
-                },
-                "password": {
-                    "description": "the user's password",
-                    "type": "string",
-                    "example": "password"
+        "/animals/{id}/vitals/series": {
+            "get": {
+                "description": "Get the measurements of an animal averaged by day, week or month for charts. The bucket is chosen from the period when not given. The weight loss alert looks at the last weights, whatever the period.",
+                "tags": [
+                    "vitals"
+                ],
+                "summary": "Get the vitals series of an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start of the series, RFC 3339, a year before to by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end of the series, RFC 3339, now by default",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month",
+                        "name": "bucket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/VitalsSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 }
             }
         },
-        "RegisterPostPayload": {
-            "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
-            "properties": {
-                "email": {
-                    "description": "the user's email",
-                    "type": "string",
-                    "example": "admin@feldrise.com"
-                },
-                "first_name": {
-                    "description": "the user's first name",
-                    "type": "string",
-                    "example": "Victor"
-                },
-                "last_name": {
-                    "description": "the user's last name",
-                    "type": "string",
-                    "example": "DENIS"
-                },
-                "password": {
-                    "description": "the user's password",
-                    "type": "string",
-                    "example": "password"
+        "/animals/{id}/vitals/{vitalid}": {
+            "get": {
+                "description": "Get a measurement taken on an animal",
+                "tags": [
+                    "vitals"
+                ],
+                "summary": "Get a vital",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Vital ID",
+                        "name": "vitalid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Vital"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 }
-            }
-        },
-        "Visit": {
-            "type": "object",
-            "properties": {
-                "cat": {
-                    "$ref": "#/definitions/Cat"
-                },
-                "date": {
-                    "type": "string"
+            },
+            "put": {
+                "description": "Correct a measurement taken on an animal, only available to the staff",
+                "tags": [
+                    "vitals"
+                ],
+                "summary": "Update a vital",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Vital ID",
+                        "name": "vitalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Vital data",
+                        "name": "vital",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/VitalUpdatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Vital"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently delete a measurement entered by mistake, only available to the staff",
+                "tags": [
+                    "vitals"
+                ],
+                "summary": "Delete a vital",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Vital ID",
+                        "name": "vitalid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/change-email": {
+            "post": {
+                "description": "Ask to change the current user's email. A verification link is sent to the new address and the change only happens once it is followed, ending every session.",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Change the email",
+                "operationId": "change-email",
+                "parameters": [
+                    {
+                        "description": "password and new email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ChangeEmailPostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "accepted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/change-password": {
+            "post": {
+                "description": "Change the current user's password. Every other session is ended.",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Change the password",
+                "operationId": "change-password",
+                "parameters": [
+                    {
+                        "description": "passwords",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ChangePasswordPostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Tokens"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/check-email": {
+            "get": {
+                "description": "Check if the email exists",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Check if the email exists",
+                "operationId": "check-if-email-exists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "email",
+                        "name": "email",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/forgot-password": {
+            "post": {
+                "description": "Send a single-use code by email to reset the password. The response is the same whether the email exists or not, a new code is only sent 2 minutes after the previous one.",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Ask for a password reset code",
+                "operationId": "forgot-password",
+                "parameters": [
+                    {
+                        "description": "user's email",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ForgotPasswordPostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/keys/rotate": {
+            "post": {
+                "description": "Generate a new signing key, the previous one keeps verifying tokens during the grace period",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Rotate the signing key",
+                "operationId": "rotate-keys",
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/SigningKey"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/login": {
+            "post": {
+                "description": "Login a user. When two-factor authentication is enabled, a MFARequired object is returned instead of the tokens.",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Login a user",
+                "operationId": "login",
+                "parameters": [
+                    {
+                        "description": "user's info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/LoginPostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/UserWithTokens"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "account suspended",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "too many requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/logout": {
+            "post": {
+                "description": "Revoke the access token used for the request and, when given, the session of the refresh token",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Logout the current user",
+                "operationId": "logout",
+                "parameters": [
+                    {
+                        "description": "refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/LogoutPostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/me": {
+            "get": {
+                "description": "Get the current user",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Get the current user",
+                "operationId": "me",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/mfa/activate": {
+            "post": {
+                "description": "Check a first code from the authenticator application and enable two-factor authentication. The recovery codes are only returned here.",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Enable two-factor authentication",
+                "operationId": "mfa-activate",
+                "parameters": [
+                    {
+                        "description": "code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/MFACodePostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/RecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/mfa/disable": {
+            "post": {
+                "description": "Disable two-factor authentication for the current user, unless its role requires it",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Disable two-factor authentication",
+                "operationId": "mfa-disable",
+                "parameters": [
+                    {
+                        "description": "password and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/MFADisablePostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "too many requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/mfa/enroll": {
+            "post": {
+                "description": "Generate a TOTP secret for the current user. Two-factor authentication is only enabled once a first code is checked with /authentication/mfa/activate.",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Start the two-factor authentication enrollment",
+                "operationId": "mfa-enroll",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/MFAEnrollment"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/mfa/recovery-codes": {
+            "post": {
+                "description": "Replace the current user's recovery codes, the previous ones stop working",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Regenerate the recovery codes",
+                "operationId": "mfa-recovery-codes",
+                "parameters": [
+                    {
+                        "description": "code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/MFACodePostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/RecoveryCodes"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "too many requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/mfa/verify": {
+            "post": {
+                "description": "Exchange the token returned by the login and a TOTP or recovery code for the user's tokens",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Complete a login with the second factor",
+                "operationId": "mfa-verify",
+                "parameters": [
+                    {
+                        "description": "token and code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/MFAVerifyPostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/UserWithTokens"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "account suspended",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "too many requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token and a new refresh token. A refresh token can only be used once, using it again ends the session.",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Refresh the tokens",
+                "operationId": "refresh",
+                "parameters": [
+                    {
+                        "description": "refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/RefreshPostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Tokens"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "account suspended",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/register": {
+            "post": {
+                "description": "Register a new user",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Register a new user",
+                "operationId": "register",
+                "parameters": [
+                    {
+                        "description": "user's info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/RegisterPostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/resend-verification": {
+            "post": {
+                "description": "Send a new verification link to the current user",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Resend the verification email",
+                "operationId": "resend-verification",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "too many requests",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/reset-password": {
+            "post": {
+                "description": "Reset the password with the code received by email",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Reset the password",
+                "operationId": "reset-password",
+                "parameters": [
+                    {
+                        "description": "reset info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/ResetPasswordPostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/roles": {
+            "get": {
+                "description": "Get the roles and their settings, only available to administrators",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Get the roles",
+                "operationId": "get-roles",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Role"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/roles/{id}": {
+            "put": {
+                "description": "Choose whether the users having the role must enable two-factor authentication, only available to administrators",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Update a role's settings",
+                "operationId": "update-role-settings",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Role ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/RoleSettingsPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Role"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/verify-email": {
+            "post": {
+                "description": "Confirm the user's email with the token sent by email",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Confirm the user's email",
+                "operationId": "verify-email",
+                "parameters": [
+                    {
+                        "description": "verification token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/VerifyEmailPostPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/authentication/{id}": {
+            "put": {
+                "description": "Update the current user. Only the staff can change their social security number.",
+                "tags": [
+                    "autentication"
+                ],
+                "summary": "Update the current user",
+                "operationId": "update",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "user's info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/UserUpdatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/calendar/feed": {
+            "post": {
+                "description": "Create the calendar feed of the current user, the URL is only returned here. The URL of the previous feed stops working.",
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Create a calendar feed",
+                "operationId": "create-calendar-feed",
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/CalendarFeed"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete the calendar feed of the current user, its URL stops working.",
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Delete the calendar feed",
+                "operationId": "delete-calendar-feed",
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/calendar/feeds/{token}.ics": {
+            "get": {
+                "description": "Get the visits of the user as an iCalendar file to subscribe to: the visits assigned to the veterinarians, and the visits of their animals for the clients. The feed has the visits of the last 30 days onwards, the cancelled ones included so the calendar applications remove them. No authentication is needed, the token identifies the user.",
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Get a calendar feed",
+                "operationId": "get-calendar-feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feed token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cat": {
+            "get": {
+                "description": "Get all animals, clients only get the animals they own. The /cat route only returns cats.",
+                "tags": [
+                    "animals"
+                ],
+                "summary": "Get all animals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "only the animals of this species",
+                        "name": "species",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Animal"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create an animal, clients own the animals they create while the staff can set their owner. The species is required, except on the /cat route.",
+                "tags": [
+                    "animals"
+                ],
+                "summary": "Create an animal",
+                "parameters": [
+                    {
+                        "description": "Animal data",
+                        "name": "animal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AnimalCreatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/Animal"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "identifier already registered",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cat/by-chip/{number}": {
+            "get": {
+                "description": "Identify an animal, with its owner, from its ISO 11784 microchip number. Only available to the staff.",
+                "tags": [
+                    "animals"
+                ],
+                "summary": "Get an animal by its microchip",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Microchip number, 15 digits",
+                        "name": "number",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Animal"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cat/{id}": {
+            "get": {
+                "description": "Get an animal by its id, the /cat route only finds cats",
+                "tags": [
+                    "animals"
+                ],
+                "summary": "Get an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "vitals to include the animal's vitals",
+                        "name": "include",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Animal"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an animal, the /cat route only finds cats",
+                "tags": [
+                    "animals"
+                ],
+                "summary": "Update an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Animal data",
+                        "name": "animal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AnimalUpdatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Animal"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "identifier already registered",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Move an animal and its visits to the trash, only available to the staff",
+                "tags": [
+                    "animals"
+                ],
+                "summary": "Delete an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cat/{id}/photos": {
+            "get": {
+                "description": "Get the photos of an animal, the most recent first. Their files are served by the signed URLs.",
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Get the photos of an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Photo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Upload a JPEG, PNG or HEIC photo of an animal. Its metadata are removed and it is stored in a large, a medium and a thumbnail size. The first photo of an animal becomes its profile picture.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Upload a photo of an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "JPEG, PNG or HEIC photo",
+                        "name": "photo",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/Photo"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the same photo is being stored",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "413": {
+                        "description": "photo too large",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "415": {
+                        "description": "unsupported photo format",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cat/{id}/photos/{photoid}": {
+            "delete": {
+                "description": "Permanently delete a photo of an animal, and its files unless another photo has the same file",
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Delete a photo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Photo ID",
+                        "name": "photoid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cat/{id}/photos/{photoid}/profile": {
+            "post": {
+                "description": "Make a photo the profile picture of its animal",
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Choose the profile picture of an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Photo ID",
+                        "name": "photoid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Photo"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cat/{id}/treatments": {
+            "get": {
+                "description": "Get the treatments given or prescribed during all the visits of an animal, the most recent visit first",
+                "tags": [
+                    "treatments"
+                ],
+                "summary": "Get the treatment history of an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/VisitTreatment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cat/{id}/vitals": {
+            "get": {
+                "description": "Get the measurements taken on an animal, the oldest first",
+                "tags": [
+                    "vitals"
+                ],
+                "summary": "Get the vitals of an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the measurements taken since this date, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the measurements taken until this date, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Vital"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Record measurements taken on an animal, during one of its visits or on their own. Only available to the staff.",
+                "tags": [
+                    "vitals"
+                ],
+                "summary": "Record vitals",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Vital data",
+                        "name": "vital",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/VitalCreatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/Vital"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cat/{id}/vitals/series": {
+            "get": {
+                "description": "Get the measurements of an animal averaged by day, week or month for charts. The bucket is chosen from the period when not given. The weight loss alert looks at the last weights, whatever the period.",
+                "tags": [
+                    "vitals"
+                ],
+                "summary": "Get the vitals series of an animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start of the series, RFC 3339, a year before to by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end of the series, RFC 3339, now by default",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month",
+                        "name": "bucket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/VitalsSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/cat/{id}/vitals/{vitalid}": {
+            "get": {
+                "description": "Get a measurement taken on an animal",
+                "tags": [
+                    "vitals"
+                ],
+                "summary": "Get a vital",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Vital ID",
+                        "name": "vitalid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Vital"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Correct a measurement taken on an animal, only available to the staff",
+                "tags": [
+                    "vitals"
+                ],
+                "summary": "Update a vital",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Vital ID",
+                        "name": "vitalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Vital data",
+                        "name": "vital",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/VitalUpdatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Vital"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently delete a measurement entered by mistake, only available to the staff",
+                "tags": [
+                    "vitals"
+                ],
+                "summary": "Delete a vital",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Vital ID",
+                        "name": "vitalid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/photos/{id}/{size}": {
+            "get": {
+                "description": "Serve a size of a photo. The URL comes signed in the urls of the photo, it works without authentication until it expires.",
+                "produces": [
+                    "image/jpeg"
+                ],
+                "tags": [
+                    "photos"
+                ],
+                "summary": "Get a photo file",
+                "operationId": "get-photo-file",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Photo ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "large, medium or thumbnail",
+                        "name": "size",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "expiration of the signature, as a Unix timestamp",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "signature of the URL",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "302": {
+                        "description": "redirection to the storage, when it serves the files"
+                    },
+                    "403": {
+                        "description": "invalid or expired signature",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/appointment-types": {
+            "get": {
+                "description": "Get the kinds of visits which can be booked, with their duration",
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Get the appointment types",
+                "operationId": "get-appointment-types",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/AppointmentType"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a kind of visit the clients can book. Only available to administrators.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Create an appointment type",
+                "operationId": "create-appointment-type",
+                "parameters": [
+                    {
+                        "description": "Appointment type info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AppointmentTypeCreatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/AppointmentType"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/appointment-types/{id}": {
+            "get": {
+                "description": "Get a kind of visit which can be booked",
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Get an appointment type",
+                "operationId": "get-appointment-type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Appointment type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/AppointmentType"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a kind of visit, the visits already booked are left unchanged. Only available to administrators.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Update an appointment type",
+                "operationId": "update-appointment-type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Appointment type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Appointment type info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AppointmentTypeUpdatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/AppointmentType"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stop offering a kind of visit, the visits already booked keep it. Only available to administrators.",
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Delete an appointment type",
+                "operationId": "delete-appointment-type",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Appointment type ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/appointments": {
+            "post": {
+                "description": "Book a visit of the animal on a free slot. The visits booked by clients are requested and wait for the clinic to confirm them, the ones booked by the staff are confirmed. When no veterinarian is given, the first one available is assigned. The animal must belong to the client.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Book an appointment",
+                "operationId": "book-appointment",
+                "parameters": [
+                    {
+                        "description": "Appointment info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/AppointmentCreatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/Visit"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "no veterinarian is available at this time",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/availability": {
+            "get": {
+                "description": "Get the slots an appointment of the type can be booked on, the earliest first. A slot is free when it is within the working hours of the veterinarian, outside their time off and doesn't overlap any of their visits. The slots start every 15 minutes.",
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Get the free slots",
+                "operationId": "get-availability",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Appointment type ID",
+                        "name": "appointment_type",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only the slots of this veterinarian",
+                        "name": "vet",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the slots starting after this date, RFC 3339, now by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the slots starting before this date, RFC 3339, a week after from by default and at most 31 days after it",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Slot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/vets": {
+            "get": {
+                "description": "Get the veterinarians appointments can be booked with, without their contact details",
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Get the veterinarians",
+                "operationId": "get-vets",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/User"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/vets/{vetid}/time-off": {
+            "get": {
+                "description": "Get the periods a veterinarian doesn't work, the earliest first. Only available to the staff.",
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Get the time off of a veterinarian",
+                "operationId": "get-time-offs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Veterinarian ID",
+                        "name": "vetid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the time off ending after this date, RFC 3339, now by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the time off starting before this date, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/TimeOff"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a period a veterinarian doesn't work, no appointment can be booked with them during it. The visits already booked are left unchanged. Only available to the veterinarian and to administrators.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Add time off",
+                "operationId": "create-time-off",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Veterinarian ID",
+                        "name": "vetid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Time off info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/TimeOffCreatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/TimeOff"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/vets/{vetid}/time-off/{id}": {
+            "delete": {
+                "description": "Delete a period a veterinarian doesn't work. Only available to the veterinarian and to administrators.",
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Delete time off",
+                "operationId": "delete-time-off",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Veterinarian ID",
+                        "name": "vetid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Time off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/schedule/vets/{vetid}/working-hours": {
+            "get": {
+                "description": "Get the periods a veterinarian works every week, in the time zone of the clinic. Only available to the staff.",
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Get the working hours of a veterinarian",
+                "operationId": "get-working-hours",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Veterinarian ID",
+                        "name": "vetid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/WorkingHours"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the periods a veterinarian works every week, in the time zone of the clinic. The visits already booked are left unchanged. Only available to the veterinarian and to administrators.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "schedule"
+                ],
+                "summary": "Set the working hours of a veterinarian",
+                "operationId": "update-working-hours",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Veterinarian ID",
+                        "name": "vetid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Working hours",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/WorkingHoursPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/WorkingHours"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/animals": {
+            "get": {
+                "description": "Get the animals in the trash, the most recently deleted first. Only available to administrators.",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Get the deleted animals",
+                "operationId": "get-trash-animals",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Animal"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/animals/{id}": {
+            "delete": {
+                "description": "Permanently delete an animal of the trash and all its visits, treatments, vitals and photos, only available to administrators",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge a deleted animal",
+                "operationId": "purge-trash-animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/animals/{id}/restore": {
+            "post": {
+                "description": "Take an animal and the visits, vitals and photos deleted with it out of the trash, only available to administrators",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted animal",
+                "operationId": "restore-trash-animal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Animal"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "identifier already registered, or the veterinarian has another visit at the time of one of its visits",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/users": {
+            "get": {
+                "description": "Get the users in the trash, the most recently deleted first. Only available to administrators.",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Get the deleted users",
+                "operationId": "get-trash-users",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/User"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/users/{id}": {
+            "delete": {
+                "description": "Permanently delete a user of the trash, its animals are kept without owner. Only available to administrators.",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge a deleted user",
+                "operationId": "purge-trash-user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/users/{id}/restore": {
+            "post": {
+                "description": "Take a user and its profile out of the trash, only available to administrators",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted user",
+                "operationId": "restore-trash-user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/visits": {
+            "get": {
+                "description": "Get the visits in the trash, the most recently deleted first. Only available to administrators.",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Get the deleted visits",
+                "operationId": "get-trash-visits",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Visit"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/visits/{id}": {
+            "delete": {
+                "description": "Permanently delete a visit of the trash and its treatments, only available to administrators",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge a deleted visit",
+                "operationId": "purge-trash-visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/visits/{id}/restore": {
+            "post": {
+                "description": "Take a visit out of the trash, its animal must not be in the trash. Only available to administrators.",
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore a deleted visit",
+                "operationId": "restore-trash-visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Visit"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "animal in the trash, or the veterinarian has another visit at this time",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/treatments": {
+            "get": {
+                "description": "Get the treatments and medications of the catalog, sorted by name. Only available to the staff.",
+                "tags": [
+                    "treatments"
+                ],
+                "summary": "Get the treatment catalog",
+                "operationId": "get-treatments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "part of the name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "medication, vaccine, antiparasitic, procedure or other",
+                        "name": "category",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Treatment"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a treatment or a medication to the catalog. Only available to the staff.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "treatments"
+                ],
+                "summary": "Add a treatment",
+                "operationId": "create-treatment",
+                "parameters": [
+                    {
+                        "description": "Treatment info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/TreatmentCreatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/Treatment"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "name already used",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/treatments/{id}": {
+            "get": {
+                "description": "Get a treatment of the catalog. Only available to the staff.",
+                "tags": [
+                    "treatments"
+                ],
+                "summary": "Get a treatment",
+                "operationId": "get-treatment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Treatment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Treatment"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a treatment of the catalog, the visits which recorded it show the change. Only available to the staff.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "treatments"
+                ],
+                "summary": "Update a treatment",
+                "operationId": "update-treatment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Treatment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Treatment info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/TreatmentUpdatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Treatment"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "name already used",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a treatment from the catalog, the visits which recorded it keep it. Only available to the staff.",
+                "tags": [
+                    "treatments"
+                ],
+                "summary": "Remove a treatment",
+                "operationId": "delete-treatment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Treatment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Search the users by email, first or last name, one page at a time. Only available to administrators.",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get the users",
+                "operationId": "get-users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "part of the email, first or last name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only the users having this role",
+                        "name": "role_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only the suspended, or not suspended, users",
+                        "name": "suspended",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "users per page, 20 by default and 100 at most",
+                        "name": "per_page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/UserList"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Get a user by its id, only available to administrators",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user",
+                "operationId": "get-user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Move a user to the trash and end its sessions, only available to administrators",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "operationId": "delete-user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/reactivate": {
+            "post": {
+                "description": "Allow a suspended user to log in again, only available to administrators",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Reactivate a user",
+                "operationId": "reactivate-user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "description": "Change the role of a user, only available to administrators",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Change the role of a user",
+                "operationId": "update-user-role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "new role",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/RoleUpdatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/suspend": {
+            "post": {
+                "description": "Prevent a user from logging in and end its sessions, only available to administrators",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Suspend a user",
+                "operationId": "suspend-user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/unlock": {
+            "post": {
+                "description": "Clear the failed login attempts of a user, only available to administrators",
+                "tags": [
+                    "users"
+                ],
+                "summary": "Unlock a user",
+                "operationId": "unlock-user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/visits": {
+            "get": {
+                "description": "Get the visits of all the animals overlapping a period, ordered by date, with their animal and its owner. The period is the current day in the clinic's time zone by default and lasts 31 days at most. The clients only get the visits of their animals.",
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Get the calendar of the visits",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "only the visits ending after this date, RFC 3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the visits starting before this date, RFC 3339",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only the visits assigned to this veterinarian",
+                        "name": "vet",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only the visits of these statuses, separated by commas",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Visit"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{animalid}/visits": {
+            "get": {
+                "description": "Get all visits for a specific animal",
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Get all visits",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Visit"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a visit for a specific animal, with the reason for consultation and optionally its veterinarian and SOAP notes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Create a visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Visit info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/VisitCreatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/Visit"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the veterinarian has another visit at this time",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{animalid}/visits/series": {
+            "post": {
+                "description": "Create the visits of a specific animal repeated by a recurrence like FREQ=WEEKLY;INTERVAL=2;COUNT=6, at the clock time of the first one in the clinic's time zone. Either all the visits are created or none when the veterinarian has another visit at one of their dates. They are then changed one by one or with the following ones of the series.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Create a series of visits",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Series info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/VisitSeriesCreatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/VisitSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the veterinarian has another visit at one of the dates",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{animalid}/visits/{id}": {
+            "get": {
+                "description": "Get a visit by ID and for a specific animal, with its treatments and the history of its status. Requested with the text/calendar media type, the visit is an iCalendar file to add to a calendar application.",
+                "produces": [
+                    "application/json",
+                    "text/calendar"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Get a visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Visit"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a visit for a specific animal. The status changes through the actions of the visit, and the completed, cancelled and missed visits can't be changed anymore. With the following scope, the reason, veterinarian, duration and date shift apply to the next open visits of its series too.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Update a visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "this (default) or following",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Visit info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/VisitUpdatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Visit"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the veterinarian has another visit at this time, or the visit is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Move a visit of a specific animal to the trash, only available to the staff",
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Delete a visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{animalid}/visits/{id}/cancel": {
+            "post": {
+                "description": "Cancel a visit with a reason. The clients can cancel the visits of their animals until they are checked in, the staff until they start. With the following scope, the next open visits of its series are cancelled too. The transition is recorded with who made it and why.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Cancel a visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "this (default) or following",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/VisitTransitionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Visit"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the action can't be taken on a visit of this status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{animalid}/visits/{id}/check-in": {
+            "post": {
+                "description": "Record the animal of a confirmed visit arrived at the clinic. Only available to the staff. The transition is recorded with who made it and why.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Check in a visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/VisitTransitionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Visit"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the action can't be taken on a visit of this status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{animalid}/visits/{id}/complete": {
+            "post": {
+                "description": "Complete a visit in progress, it can't be changed anymore. With follow_up_days, a follow-up with the same veterinarian is scheduled that many days later at the same time. Only available to the staff. The transition is recorded with who made it and why.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Complete a visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/VisitTransitionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Visit"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the action can't be taken on a visit of this status, or the veterinarian has another visit at the time of the follow-up",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{animalid}/visits/{id}/confirm": {
+            "post": {
+                "description": "Confirm a visit requested by a client. Only available to the staff. The transition is recorded with who made it and why.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Confirm a requested visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/VisitTransitionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Visit"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the action can't be taken on a visit of this status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{animalid}/visits/{id}/no-show": {
+            "post": {
+                "description": "Record the animal of a confirmed visit never came, once the visit should have started. Only available to the staff. The transition is recorded with who made it and why.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Record a no-show",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/VisitTransitionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Visit"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the action can't be taken on a visit of this status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{animalid}/visits/{id}/start": {
+            "post": {
+                "description": "Start a confirmed or checked in visit. Only available to the staff. The transition is recorded with who made it and why.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Start a visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/VisitTransitionPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/Visit"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the action can't be taken on a visit of this status",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{animalid}/visits/{id}/treatments": {
+            "post": {
+                "description": "Record a treatment of the catalog given or prescribed during a visit. Only available to the staff.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Record a treatment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Treatment info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/VisitTreatmentCreatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "created",
+                        "schema": {
+                            "$ref": "#/definitions/VisitTreatment"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the visit is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{animalid}/visits/{id}/treatments/{treatmentid}": {
+            "put": {
+                "description": "Update the dosage, route or prescription of a treatment recorded during a visit. Only available to the staff.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Update a treatment of a visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit treatment ID",
+                        "name": "treatmentid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Treatment info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/VisitTreatmentUpdatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "$ref": "#/definitions/VisitTreatment"
+                        }
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the visit is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Permanently delete a treatment recorded by mistake during a visit. Only available to the staff.",
+                "tags": [
+                    "visits"
+                ],
+                "summary": "Delete a treatment of a visit",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Animal ID",
+                        "name": "animalid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Visit treatment ID",
+                        "name": "treatmentid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "no content"
+                    },
+                    "400": {
+                        "description": "bad request",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "unauthorized",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "forbidden",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "not found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "409": {
+                        "description": "the visit is closed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "internal server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "Animal": {
+            "type": "object",
+            "properties": {
+                "age_months": {
+                    "description": "the months to add to age_years",
+                    "type": "integer"
+                },
+                "age_years": {
+                    "description": "the animal's age, computed from its date of birth",
+                    "type": "integer"
+                },
+                "attributes": {
+                    "description": "the attributes specific to the species",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "birth_date": {
+                    "description": "the animal's date of birth",
+                    "type": "string"
+                },
+                "breed": {
+                    "description": "the animal's breed",
+                    "type": "string"
+                },
+                "color": {
+                    "description": "the animal's coat color",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "the animal's creation date",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "when the animal was moved to the trash",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "the animal's resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "microchip": {
+                    "description": "the ISO 11784 identification code",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "neutered": {
+                    "description": "whether the animal is neutered",
+                    "type": "boolean"
+                },
+                "neutered_at": {
+                    "description": "the date of the neutering",
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "the user owning the animal, when requested",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/User"
+                        }
+                    ]
+                },
+                "owner_id": {
+                    "description": "the user owning the animal",
+                    "type": "integer"
+                },
+                "profile_photo": {
+                    "description": "the animal's profile picture",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Photo"
+                        }
+                    ]
+                },
+                "sex": {
+                    "description": "male, female or unknown",
+                    "type": "string"
+                },
+                "species": {
+                    "description": "the code of the animal's species",
+                    "type": "string"
+                },
+                "tattoo": {
+                    "description": "the tattoo identifier",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "the animal's last update date",
+                    "type": "string"
+                },
+                "vitals": {
+                    "description": "the animal's vitals, when requested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Vital"
+                    }
+                }
+            }
+        },
+        "AnimalCreatePayload": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "the attributes specific to the species",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "birth_date": {
+                    "type": "string",
+                    "example": "2020-04-12T00:00:00Z"
+                },
+                "breed": {
+                    "type": "string",
+                    "example": "Maine Coon"
+                },
+                "color": {
+                    "type": "string",
+                    "example": "brown tabby"
+                },
+                "microchip": {
+                    "type": "string",
+                    "example": "250269604123456"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "neutered": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "neutered_at": {
+                    "type": "string",
+                    "example": "2021-01-08T00:00:00Z"
+                },
+                "owner_id": {
+                    "description": "the user owning the animal, clients always own the animals they create",
+                    "type": "integer",
+                    "example": 3
+                },
+                "sex": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female",
+                        "unknown"
+                    ],
+                    "example": "female"
+                },
+                "species": {
+                    "description": "the code of the species, implied by the /cat routes",
+                    "type": "string",
+                    "example": "dog"
+                },
+                "tattoo": {
+                    "type": "string",
+                    "example": "ABC123"
+                }
+            }
+        },
+        "AnimalUpdatePayload": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "replaces all the attributes specific to the species",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "birth_date": {
+                    "type": "string",
+                    "example": "2020-04-12T00:00:00Z"
+                },
+                "breed": {
+                    "type": "string",
+                    "example": "Maine Coon"
+                },
+                "color": {
+                    "type": "string",
+                    "example": "brown tabby"
+                },
+                "microchip": {
+                    "type": "string",
+                    "example": "250269604123456"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Garfield"
+                },
+                "neutered": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "neutered_at": {
+                    "type": "string",
+                    "example": "2021-01-08T00:00:00Z"
+                },
+                "owner_id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "sex": {
+                    "type": "string",
+                    "enum": [
+                        "male",
+                        "female",
+                        "unknown"
+                    ],
+                    "example": "female"
+                },
+                "tattoo": {
+                    "type": "string",
+                    "example": "ABC123"
+                }
+            }
+        },
+        "AppointmentCreatePayload": {
+            "type": "object",
+            "required": [
+                "animal_id",
+                "appointment_type_id",
+                "start"
+            ],
+            "properties": {
+                "animal_id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "appointment_type_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "description": "the name of the appointment type by default",
+                    "type": "string",
+                    "example": "Vaccination"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2021-01-04T09:30:00+01:00"
+                },
+                "vet_id": {
+                    "description": "any available veterinarian when not given",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "AppointmentType": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "the type's creation date",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "when the type was removed",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "the type's resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "the type's last update date",
+                    "type": "string"
+                }
+            }
+        },
+        "AppointmentTypeCreatePayload": {
+            "type": "object",
+            "required": [
+                "duration",
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Annual booster and check-up"
+                },
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer",
+                    "example": 20
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Vaccination"
+                }
+            }
+        },
+        "AppointmentTypeUpdatePayload": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Annual booster and check-up"
+                },
+                "duration": {
+                    "description": "the visits already booked keep their duration",
+                    "type": "integer",
+                    "example": 20
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Vaccination"
+                }
+            }
+        },
+        "CalendarFeed": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "description": "the feed to subscribe to in a calendar application, only returned on creation",
+                    "type": "string"
+                }
+            }
+        },
+        "ChangeEmailPostPayload": {
+            "type": "object",
+            "required": [
+                "new_email",
+                "password"
+            ],
+            "properties": {
+                "new_email": {
+                    "description": "the email to switch to once verified",
+                    "type": "string",
+                    "example": "contact@feldrise.com"
+                },
+                "password": {
+                    "description": "the user's current password",
+                    "type": "string",
+                    "example": "password"
+                }
+            }
+        },
+        "ChangePasswordPostPayload": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "description": "the user's current password",
+                    "type": "string",
+                    "example": "password"
+                },
+                "new_password": {
+                    "description": "the user's new password",
+                    "type": "string",
+                    "example": "MyNewPassword"
+                }
+            }
+        },
+        "ErrResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "application-specific error code",
+                    "type": "integer"
+                },
+                "error": {
+                    "description": "application-level error message, for debugging",
+                    "type": "string"
+                },
+                "fields": {
+                    "description": "request fields causing the error",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "description": "user-level status message",
+                    "type": "string"
+                }
+            }
+        },
+        "ForgotPasswordPostPayload": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "description": "the user's email",
+                    "type": "string",
+                    "example": "admin@feldrise.com"
+                }
+            }
+        },
+        "JSONWebKey": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "description": "RS256 or EdDSA",
+                    "type": "string"
+                },
+                "crv": {
+                    "description": "OKP curve, always Ed25519",
+                    "type": "string"
+                },
+                "e": {
+                    "description": "RSA public exponent",
+                    "type": "string"
+                },
+                "kid": {
+                    "description": "the id found in the tokens' header",
+                    "type": "string"
+                },
+                "kty": {
+                    "description": "RSA or OKP",
+                    "type": "string"
+                },
+                "n": {
+                    "description": "RSA modulus",
+                    "type": "string"
+                },
+                "use": {
+                    "description": "always sig",
+                    "type": "string"
+                },
+                "x": {
+                    "description": "OKP public key",
+                    "type": "string"
+                }
+            }
+        },
+        "JSONWebKeySet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/JSONWebKey"
+                    }
+                }
+            }
+        },
+        "Links": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "LoginPostPayload": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "description": "the user's email",
+                    "type": "string",
+                    "example": "admin@feldrise.com"
+                },
+                "password": {
+                    "description": "the user's password",
+                    "type": "string",
+                    "example": "password"
+                }
+            }
+        },
+        "LogoutPostPayload": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "description": "the refresh token of the session to end",
+                    "type": "string",
+                    "example": "kCZB4p3dfJqV6q"
+                }
+            }
+        },
+        "MFACodePostPayload": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "description": "the code from the authenticator application",
+                    "type": "string",
+                    "example": "123456"
+                }
+            }
+        },
+        "MFADisablePostPayload": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "description": "the code from the authenticator application",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "password": {
+                    "description": "the user's password",
+                    "type": "string",
+                    "example": "password"
+                }
+            }
+        },
+        "MFAEnrollment": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "description": "the base32 secret, for manual entry",
+                    "type": "string"
+                },
+                "uri": {
+                    "description": "the otpauth:// URI, to display as a QR code",
+                    "type": "string"
+                }
+            }
+        },
+        "MFAVerifyPostPayload": {
+            "type": "object",
+            "required": [
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "description": "the code from the authenticator application",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "mfa_token": {
+                    "description": "the token returned by the login",
+                    "type": "string",
+                    "example": "eyJhbGciOiJFZERTQSJ9"
+                },
+                "recovery_code": {
+                    "description": "a recovery code, in place of code",
+                    "type": "string",
+                    "example": "abcd-efgh"
+                }
+            }
+        },
+        "Photo": {
+            "type": "object",
+            "properties": {
+                "animal_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "the photo's upload date",
+                    "type": "string"
+                },
+                "height": {
+                    "description": "the height of the large size, in pixels",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "the photo's resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "profile": {
+                    "description": "whether the photo is the animal's profile picture",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "description": "the photo's last update date",
+                    "type": "string"
+                },
+                "urls": {
+                    "description": "the signed URLs of the large (2048px), medium (800px) and thumbnail (200px square) sizes, valid for a day",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "width": {
+                    "description": "the width of the large size, in pixels",
+                    "type": "integer"
+                }
+            }
+        },
+        "RecoveryCodes": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "description": "single-use codes replacing the second factor, only shown once",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "RefreshPostPayload": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "description": "the refresh token",
+                    "type": "string",
+                    "example": "kCZB4p3dfJqV6q"
+                }
+            }
+        },
+        "RegisterPostPayload": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "description": "the user's email",
+                    "type": "string",
+                    "example": "admin@feldrise.com"
+                },
+                "first_name": {
+                    "description": "the user's first name",
+                    "type": "string",
+                    "example": "Victor"
+                },
+                "last_name": {
+                    "description": "the user's last name",
+                    "type": "string",
+                    "example": "DENIS"
+                },
+                "password": {
+                    "description": "the user's password",
+                    "type": "string",
+                    "example": "password"
+                }
+            }
+        },
+        "ResetPasswordPostPayload": {
+            "type": "object",
+            "required": [
+                "code",
+                "email",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "description": "the verification code",
+                    "type": "string",
+                    "example": "123456"
+                },
+                "email": {
+                    "description": "the user's email",
+                    "type": "string",
+                    "example": "admin@feldrise.com"
+                },
+                "password": {
+                    "description": "the user's new password",
+                    "type": "string",
+                    "example": "MyNewPassword"
+                }
+            }
+        },
+        "Role": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "the role's creation date",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "the role's description",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "the role's resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "the role's name",
+                    "type": "string"
+                },
+                "require_mfa": {
+                    "description": "whether the users having the role must enable two-factor authentication",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "description": "the role's last update date",
+                    "type": "string"
+                }
+            }
+        },
+        "RoleSettingsPayload": {
+            "type": "object",
+            "required": [
+                "require_mfa"
+            ],
+            "properties": {
+                "require_mfa": {
+                    "description": "whether the users having the role must enable two-factor authentication",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "RoleUpdatePayload": {
+            "type": "object",
+            "required": [
+                "role_id"
+            ],
+            "properties": {
+                "role_id": {
+                    "description": "the new role of the user",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "SOAPNotes": {
+            "type": "object",
+            "properties": {
+                "assessment": {
+                    "description": "the diagnosis",
+                    "type": "string"
+                },
+                "objective": {
+                    "description": "the examination findings",
+                    "type": "string"
+                },
+                "plan": {
+                    "description": "the treatment and the follow-up",
+                    "type": "string"
+                },
+                "subjective": {
+                    "description": "the history and the owner's observations",
+                    "type": "string"
+                }
+            }
+        },
+        "SOAPNotesPayload": {
+            "type": "object",
+            "properties": {
+                "assessment": {
+                    "type": "string",
+                    "example": "Suspected renal failure"
+                },
+                "objective": {
+                    "type": "string",
+                    "example": "Dehydration, 39.4 °C"
+                },
+                "plan": {
+                    "type": "string",
+                    "example": "Blood test, fluids, control in 7 days"
+                },
+                "subjective": {
+                    "type": "string",
+                    "example": "Eats less since a week"
+                }
+            }
+        },
+        "SigningKey": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "description": "the algorithm of the new signing key",
+                    "type": "string"
+                },
+                "kid": {
+                    "description": "the id of the new signing key",
+                    "type": "string"
+                }
+            }
+        },
+        "Slot": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "vet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "Species": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "the optional attributes of the species' animals, with the type of their value",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "description": "the code used to refer to the species",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "the species' creation date",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "the species' name",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "the species' last update date",
+                    "type": "string"
+                }
+            }
+        },
+        "TimeOff": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "the time off's creation date",
+                    "type": "string"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "the time off's resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "the time off's last update date",
+                    "type": "string"
+                },
+                "vet_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "TimeOffCreatePayload": {
+            "type": "object",
+            "required": [
+                "end",
+                "start"
+            ],
+            "properties": {
+                "end": {
+                    "type": "string",
+                    "example": "2021-08-16T00:00:00+02:00"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Holidays"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2021-08-02T00:00:00+02:00"
+                }
+            }
+        },
+        "Tokens": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "the access token's expiration timestamp",
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "description": "the single-use token to get new tokens",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "the access token, to send in the Authorization header",
+                    "type": "string"
+                }
+            }
+        },
+        "Treatment": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "medication",
+                        "vaccine",
+                        "antiparasitic",
+                        "procedure",
+                        "other"
+                    ]
+                },
+                "created_at": {
+                    "description": "the treatment's creation date",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "when the treatment was removed from the catalog",
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "the treatment's resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "the treatment's last update date",
+                    "type": "string"
+                }
+            }
+        },
+        "TreatmentCreatePayload": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "category": {
+                    "description": "medication by default",
+                    "type": "string",
+                    "enum": [
+                        "medication",
+                        "vaccine",
+                        "antiparasitic",
+                        "procedure",
+                        "other"
+                    ],
+                    "example": "medication"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Anti-inflammatory, oral suspension"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Meloxicam 1.5 mg/ml"
+                }
+            }
+        },
+        "TreatmentUpdatePayload": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string",
+                    "enum": [
+                        "medication",
+                        "vaccine",
+                        "antiparasitic",
+                        "procedure",
+                        "other"
+                    ],
+                    "example": "medication"
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Anti-inflammatory, oral suspension"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Meloxicam 1.5 mg/ml"
+                }
+            }
+        },
+        "User": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "the user's creation date",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "when the user was moved to the trash",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "the user's email",
+                    "type": "string"
+                },
+                "email_verified": {
+                    "description": "whether the user confirmed its email",
+                    "type": "boolean"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "the user's resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "mfa_enabled": {
+                    "description": "whether the user enabled two-factor authentication",
+                    "type": "boolean"
+                },
+                "profile": {
+                    "description": "the user's profile",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/UserProfile"
+                        }
+                    ]
+                },
+                "role_id": {
+                    "description": "the user's role",
+                    "type": "integer"
+                },
+                "suspended": {
+                    "description": "whether an administrator suspended the account",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "description": "the user's last update date",
+                    "type": "string"
+                }
+            }
+        },
+        "UserList": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "description": "the current page, starting at 1",
+                    "type": "integer"
+                },
+                "per_page": {
+                    "description": "the number of users per page",
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "the number of users matching the filters",
+                    "type": "integer"
+                },
+                "users": {
+                    "description": "the users of the page",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/User"
+                    }
+                }
+            }
+        },
+        "UserProfile": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "the profile's creation date",
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "social_security_number": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "the profile's last update date",
+                    "type": "string"
+                }
+            }
+        },
+        "UserUpdatePayload": {
+            "type": "object",
+            "properties": {
+                "first_name": {
+                    "type": "string",
+                    "example": "Victor"
+                },
+                "last_name": {
+                    "type": "string",
+                    "example": "DENIS"
+                },
+                "social_security_number": {
+                    "type": "string",
+                    "example": "1 85 05 78 006 084 36"
+                }
+            }
+        },
+        "UserWithTokens": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "the user's creation date",
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "when the user was moved to the trash",
+                    "type": "string"
+                },
+                "email": {
+                    "description": "the user's email",
+                    "type": "string"
+                },
+                "email_verified": {
+                    "description": "whether the user confirmed its email",
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "description": "the access token's expiration timestamp",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "the user's resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "mfa_enabled": {
+                    "description": "whether the user enabled two-factor authentication",
+                    "type": "boolean"
+                },
+                "profile": {
+                    "description": "the user's profile",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/UserProfile"
+                        }
+                    ]
+                },
+                "refresh_token": {
+                    "description": "the single-use token to get new tokens",
+                    "type": "string"
+                },
+                "role_id": {
+                    "description": "the user's role",
+                    "type": "integer"
+                },
+                "suspended": {
+                    "description": "whether an administrator suspended the account",
+                    "type": "boolean"
+                },
+                "token": {
+                    "description": "the access token, to send in the Authorization header",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "the user's last update date",
+                    "type": "string"
+                }
+            }
+        },
+        "VerifyEmailPostPayload": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "description": "the token received by email",
+                    "type": "string",
+                    "example": "Jc2k8YbVxQ"
+                }
+            }
+        },
+        "Visit": {
+            "type": "object",
+            "properties": {
+                "animal": {
+                    "$ref": "#/definitions/Animal"
+                },
+                "animal_id": {
+                    "type": "integer"
+                },
+                "appointment_type_id": {
+                    "description": "the type the visit was booked as, if any",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "the visit's creation date",
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "description": "when the visit was moved to the trash",
+                    "type": "string"
+                },
+                "duration": {
+                    "description": "in minutes",
+                    "type": "integer"
+                },
+                "follow_up_of_id": {
+                    "description": "the visit this one follows up, if any",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "the visit's resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "notes": {
+                    "description": "the clinical notes of the visit",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/SOAPNotes"
+                        }
+                    ]
+                },
+                "reason": {
+                    "description": "the reason for consultation",
+                    "type": "string"
+                },
+                "series_id": {
+                    "description": "the series the visit was created in, if any",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "the visit's progress",
+                    "type": "string",
+                    "enum": [
+                        "requested",
+                        "confirmed",
+                        "checked_in",
+                        "in_progress",
+                        "completed",
+                        "cancelled",
+                        "no_show"
+                    ]
+                },
+                "transitions": {
+                    "description": "the changes of status, the oldest first, when requested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/VisitTransition"
+                    }
+                },
+                "treatments": {
+                    "description": "the treatments given or prescribed, when requested",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/VisitTreatment"
+                    }
+                },
+                "updated_at": {
+                    "description": "the visit's last update date",
+                    "type": "string"
+                },
+                "vet_id": {
+                    "description": "the veterinarian assigned to the visit",
+                    "type": "integer"
+                }
+            }
+        },
+        "VisitCreatePayload": {
+            "type": "object",
+            "required": [
+                "date",
+                "reason"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2021-01-01T00:00:00Z"
+                },
+                "duration": {
+                    "description": "in minutes, 30 by default",
+                    "type": "integer",
+                    "example": 30
+                },
+                "notes": {
+                    "$ref": "#/definitions/SOAPNotesPayload"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Annual vaccination"
+                },
+                "status": {
+                    "description": "confirmed by default",
+                    "type": "string",
+                    "enum": [
+                        "requested",
+                        "confirmed"
+                    ],
+                    "example": "confirmed"
+                },
+                "vet_id": {
+                    "description": "a user with the veterinaire role",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "VisitSeries": {
+            "type": "object",
+            "properties": {
+                "animal_id": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "the series' creation date",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "the series' resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "recurrence": {
+                    "description": "the RRULE the visits were created from",
+                    "type": "string"
+                },
+                "visits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Visit"
+                    }
+                }
+            }
+        },
+        "VisitSeriesCreatePayload": {
+            "type": "object",
+            "required": [
+                "date",
+                "reason",
+                "recurrence"
+            ],
+            "properties": {
+                "date": {
+                    "description": "the first visit",
+                    "type": "string",
+                    "example": "2021-01-04T09:00:00+01:00"
+                },
+                "duration": {
+                    "description": "in minutes, 30 by default",
+                    "type": "integer",
+                    "example": 30
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Glycemia check"
+                },
+                "recurrence": {
+                    "description": "FREQ (DAILY, WEEKLY, MONTHLY or YEARLY), INTERVAL, and COUNT or UNTIL",
+                    "type": "string",
+                    "example": "FREQ=WEEKLY;INTERVAL=2;COUNT=6"
+                },
+                "vet_id": {
+                    "description": "a user with the veterinaire role",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "VisitTransition": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "when the status changed",
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "who changed the status, null once the user is deleted",
+                    "type": "integer"
+                }
+            }
+        },
+        "VisitTransitionPayload": {
+            "type": "object",
+            "properties": {
+                "follow_up_days": {
+                    "description": "to complete a visit, schedules a follow-up this many days later",
+                    "type": "integer",
+                    "example": 14
+                },
+                "reason": {
+                    "description": "required to cancel a visit",
+                    "type": "string",
+                    "example": "The owner is ill"
+                }
+            }
+        },
+        "VisitTreatment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "the record's creation date",
+                    "type": "string"
+                },
+                "dosage": {
+                    "type": "number"
+                },
+                "duration_days": {
+                    "description": "how long a prescription lasts, in days",
+                    "type": "integer"
+                },
+                "frequency": {
+                    "description": "like once or every 12 hours",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "administered",
+                        "prescribed"
+                    ]
+                },
+                "links": {
+                    "description": "the record's resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "route": {
+                    "description": "oral, topical, subcutaneous, intramuscular, intravenous, ophthalmic, otic, inhaled, rectal or other",
+                    "type": "string"
+                },
+                "treatment": {
+                    "description": "the treatment of the catalog",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Treatment"
+                        }
+                    ]
+                },
+                "treatment_id": {
+                    "type": "integer"
+                },
+                "unit": {
+                    "description": "the unit of the dosage, like mg, ml or tablet",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "description": "the record's last update date",
+                    "type": "string"
+                },
+                "visit_date": {
+                    "description": "the date of the visit, in the history of an animal",
+                    "type": "string"
+                },
+                "visit_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "VisitTreatmentCreatePayload": {
+            "type": "object",
+            "required": [
+                "dosage",
+                "route",
+                "treatment_id",
+                "unit"
+            ],
+            "properties": {
+                "dosage": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "duration_days": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "frequency": {
+                    "type": "string",
+                    "example": "once a day"
+                },
+                "kind": {
+                    "description": "administered by default",
+                    "type": "string",
+                    "enum": [
+                        "administered",
+                        "prescribed"
+                    ],
+                    "example": "prescribed"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "With food"
+                },
+                "route": {
+                    "type": "string",
+                    "example": "oral"
+                },
+                "treatment_id": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "ml"
+                }
+            }
+        },
+        "VisitTreatmentUpdatePayload": {
+            "type": "object",
+            "properties": {
+                "dosage": {
+                    "type": "number",
+                    "example": 0.5
+                },
+                "duration_days": {
+                    "description": "0 to remove the duration",
+                    "type": "integer",
+                    "example": 5
+                },
+                "frequency": {
+                    "type": "string",
+                    "example": "once a day"
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "administered",
+                        "prescribed"
+                    ],
+                    "example": "prescribed"
+                },
+                "notes": {
+                    "type": "string",
+                    "example": "With food"
+                },
+                "route": {
+                    "type": "string",
+                    "example": "oral"
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "ml"
+                }
+            }
+        },
+        "VisitUpdatePayload": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2021-01-01T00:00:00Z"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "notes": {
+                    "description": "only the notes given are changed",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/SOAPNotesPayload"
+                        }
+                    ]
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Annual vaccination"
+                },
+                "vet_id": {
+                    "description": "a user with the veterinaire role, 0 to unassign the visit",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "Vital": {
+            "type": "object",
+            "properties": {
+                "animal_id": {
+                    "type": "integer"
+                },
+                "body_condition_score": {
+                    "description": "from 1 (emaciated) to 9 (obese)",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "description": "the measurement's creation date",
+                    "type": "string"
+                },
+                "heart_rate": {
+                    "description": "in beats per minute",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "@id",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "the measurement's resources",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/Links"
+                        }
+                    ]
+                },
+                "recorded_at": {
+                    "description": "when the measurement was taken",
+                    "type": "string"
+                },
+                "respiratory_rate": {
+                    "description": "in breaths per minute",
+                    "type": "integer"
+                },
+                "temperature": {
+                    "description": "in degrees Celsius",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "description": "the measurement's last update date",
+                    "type": "string"
+                },
+                "visit_id": {
+                    "description": "the visit the measurement was taken at, if any",
+                    "type": "integer"
+                },
+                "weight": {
+                    "description": "in kilograms",
+                    "type": "number"
+                }
+            }
+        },
+        "VitalCreatePayload": {
+            "type": "object",
+            "properties": {
+                "body_condition_score": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "heart_rate": {
+                    "type": "integer",
+                    "example": 160
+                },
+                "recorded_at": {
+                    "description": "the date of the visit or now by default",
+                    "type": "string",
+                    "example": "2021-01-01T10:30:00Z"
+                },
+                "respiratory_rate": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "temperature": {
+                    "type": "number",
+                    "example": 38.5
+                },
+                "visit_id": {
+                    "description": "the visit the measurement was taken at",
+                    "type": "integer",
+                    "example": 12
+                },
+                "weight": {
+                    "type": "number",
+                    "example": 4.2
+                }
+            }
+        },
+        "VitalUpdatePayload": {
+            "type": "object",
+            "properties": {
+                "body_condition_score": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "heart_rate": {
+                    "type": "integer",
+                    "example": 160
+                },
+                "recorded_at": {
+                    "type": "string",
+                    "example": "2021-01-01T10:30:00Z"
+                },
+                "respiratory_rate": {
+                    "type": "integer",
+                    "example": 24
+                },
+                "temperature": {
+                    "type": "number",
+                    "example": 38.5
+                },
+                "weight": {
+                    "type": "number",
+                    "example": 4.2
+                }
+            }
+        },
+        "VitalsPoint": {
+            "type": "object",
+            "properties": {
+                "body_condition_score": {
+                    "description": "the average of the bucket, null without measurement",
+                    "type": "number"
+                },
+                "count": {
+                    "description": "the measurements in the bucket",
+                    "type": "integer"
+                },
+                "date": {
+                    "description": "the start of the bucket",
+                    "type": "string"
+                },
+                "heart_rate": {
+                    "description": "the average of the bucket, null without measurement",
+                    "type": "number"
+                },
+                "respiratory_rate": {
+                    "description": "the average of the bucket, null without measurement",
+                    "type": "number"
+                },
+                "temperature": {
+                    "description": "the average of the bucket, null without measurement",
+                    "type": "number"
+                },
+                "weight": {
+                    "description": "the average of the bucket, null without measurement",
+                    "type": "number"
+                }
+            }
+        },
+        "VitalsSeries": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "description": "the duration each point averages",
+                    "type": "string",
+                    "enum": [
+                        "day",
+                        "week",
+                        "month"
+                    ]
+                },
+                "from": {
+                    "type": "string"
+                },
+                "points": {
+                    "description": "the buckets having measurements, the oldest first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/VitalsPoint"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "weight_loss": {
+                    "description": "set when the animal lost weight rapidly",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/WeightLossAlert"
+                        }
+                    ]
+                }
+            }
+        },
+        "WeightLossAlert": {
+            "type": "object",
+            "properties": {
+                "from_date": {
+                    "description": "when the highest weight was measured",
+                    "type": "string"
+                },
+                "from_weight": {
+                    "description": "the highest weight of the period, in kilograms",
+                    "type": "number"
+                },
+                "loss_percent": {
+                    "description": "the weight lost between both measurements",
+                    "type": "number"
+                },
+                "period_days": {
+                    "description": "the period the loss is looked for on",
+                    "type": "integer"
+                },
+                "threshold": {
+                    "description": "the loss percentage raising the alert",
+                    "type": "number"
+                },
+                "to_date": {
+                    "description": "when the last weight was measured",
+                    "type": "string"
+                },
+                "to_weight": {
+                    "description": "the last weight, in kilograms",
+                    "type": "number"
+                }
+            }
+        },
+        "WorkingHours": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "description": "in the clinic's time zone",
+                    "type": "string",
+                    "example": "12:30"
+                },
+                "start": {
+                    "description": "in the clinic's time zone",
+                    "type": "string",
+                    "example": "09:00"
+                },
+                "weekday": {
+                    "description": "from 0 for Sunday to 6 for Saturday",
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "WorkingHoursPayload": {
+            "type": "object",
+            "required": [
+                "hours"
+            ],
+            "properties": {
+                "hours": {
+                    "description": "replaces all the working hours of the veterinarian",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/WorkingHours"
+                    }
                 }
             }
         }
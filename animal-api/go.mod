@@ -3,7 +3,6 @@ module feldrise.com/animal-api
 go 1.21.0
 
 require (
-	github.com/go-chi/chi v1.5.5
 	github.com/go-chi/chi/v5 v5.1.0
 	github.com/go-chi/render v1.0.3
 	github.com/goccy/go-json v0.10.4
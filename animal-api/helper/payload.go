@@ -0,0 +1,40 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// DecodeAllowed decodes a JSON object into payload, provided it only holds
+// allowed keys. Otherwise nothing is decoded and the offending keys are
+// returned.
+func DecodeAllowed(body io.Reader, allowed []string, payload interface{}) ([]string, error) {
+	content, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes map[string]json.RawMessage
+	if err := json.Unmarshal(content, &changes); err != nil {
+		return nil, err
+	}
+
+	forbidden := []string{}
+	for key := range changes {
+		if !Contains(allowed, key) {
+			forbidden = append(forbidden, key)
+		}
+	}
+
+	if len(forbidden) > 0 {
+		sort.Strings(forbidden)
+		return forbidden, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+
+	return nil, decoder.Decode(payload)
+}
@@ -0,0 +1,74 @@
+package helper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeAllowed(t *testing.T) {
+	type payload struct {
+		Name  *string `json:"name"`
+		Color *string `json:"color"`
+	}
+
+	allowed := []string{"name", "color"}
+
+	tests := []struct {
+		name          string
+		body          string
+		allowed       []string
+		wantForbidden []string
+		wantName      string
+		wantErr       bool
+	}{
+		{name: "allowed keys", body: `{"name":"Garfield","color":"orange"}`, allowed: allowed, wantName: "Garfield"},
+		{name: "empty object", body: `{}`, allowed: allowed},
+		{name: "one forbidden key", body: `{"name":"Garfield","owner_id":3}`, allowed: allowed, wantForbidden: []string{"owner_id"}},
+		{name: "sorted forbidden keys", body: `{"tattoo":"ABC","microchip":"1","name":"Garfield"}`, allowed: allowed, wantForbidden: []string{"microchip", "tattoo"}},
+		{name: "nothing allowed", body: `{"name":"Garfield"}`, allowed: []string{}, wantForbidden: []string{"name"}},
+		{name: "allowed but unknown key", body: `{"name":"Garfield","size":"L"}`, allowed: append([]string{"size"}, allowed...), wantErr: true},
+		{name: "not an object", body: `["name"]`, allowed: allowed, wantErr: true},
+		{name: "invalid JSON", body: `{"name":`, allowed: allowed, wantErr: true},
+		{name: "invalid type", body: `{"name":3}`, allowed: allowed, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got payload
+
+			forbidden, err := DecodeAllowed(strings.NewReader(test.body), test.allowed, &got)
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("DecodeAllowed() = %v, want an error", forbidden)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("DecodeAllowed() error = %v", err)
+			}
+
+			if len(forbidden) > 0 || len(test.wantForbidden) > 0 {
+				if !reflect.DeepEqual(forbidden, test.wantForbidden) {
+					t.Errorf("DecodeAllowed() = %v, want %v", forbidden, test.wantForbidden)
+				}
+
+				if got.Name != nil {
+					t.Errorf("DecodeAllowed() decoded %q with forbidden keys", *got.Name)
+				}
+				return
+			}
+
+			name := ""
+			if got.Name != nil {
+				name = *got.Name
+			}
+
+			if name != test.wantName {
+				t.Errorf("DecodeAllowed() name = %q, want %q", name, test.wantName)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package helper
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"strings"
+)
+
+// RandomCode returns a random numeric code of the given length, suited to be
+// typed by a human
+func RandomCode(length int) (string, error) {
+	var builder strings.Builder
+	ten := big.NewInt(10)
+
+	for i := 0; i < length; i++ {
+		digit, err := rand.Int(rand.Reader, ten)
+		if err != nil {
+			return "", err
+		}
+
+		builder.WriteString(digit.String())
+	}
+
+	return builder.String(), nil
+}
+
+// RandomToken returns a random url-safe token built from size random bytes
+func RandomToken(size int) (string, error) {
+	bytes := make([]byte, size)
+
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// HashToken returns the hash stored in database in place of a code or token
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(hash[:])
+}
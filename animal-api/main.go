@@ -1,17 +1,28 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"net/http"
 
+	// The time zone of the clinic must load even without tzdata installed
+	_ "time/tzdata"
+
 	_ "feldrise.com/animal-api/docs"
 
 	"feldrise.com/animal-api/config"
+	"feldrise.com/animal-api/pkg/animal"
 	"feldrise.com/animal-api/pkg/authentication"
-	"feldrise.com/animal-api/pkg/cat"
+	"feldrise.com/animal-api/pkg/calendar"
+	"feldrise.com/animal-api/pkg/model"
+	"feldrise.com/animal-api/pkg/photo"
+	"feldrise.com/animal-api/pkg/schedule"
+	"feldrise.com/animal-api/pkg/trash"
+	"feldrise.com/animal-api/pkg/treatment"
+	"feldrise.com/animal-api/pkg/user"
 	"feldrise.com/animal-api/pkg/visit"
-	"github.com/go-chi/chi/middleware"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/rs/cors"
 
@@ -21,6 +32,12 @@ import (
 func Routes(configuration *config.Config) *chi.Mux {
 	router := chi.NewRouter()
 
+	// The client address is used to throttle logins, it can only be read
+	// from the headers when they come from our own proxy
+	if configuration.Constants.BehindProxy {
+		router.Use(middleware.RealIP)
+	}
+
 	router.Use(
 		render.SetContentType(render.ContentTypeJSON),
 		middleware.Recoverer,
@@ -35,10 +52,21 @@ func Routes(configuration *config.Config) *chi.Mux {
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE"},
 	}).Handler)
 
+	router.Get("/.well-known/jwks.json", authentication.New(configuration).JWKS)
+
 	router.Route("/api/v1", func(r chi.Router) {
 		r.Mount("/authentication", authentication.New(configuration).Routes())
-		r.Mount("/cat", cat.New(configuration).Routes())
-		r.Mount("/{catid}/visits", visit.New(configuration).Routes())
+		r.Mount("/users", user.New(configuration).Routes())
+		r.Mount("/trash", trash.New(configuration).Routes())
+		r.Mount("/animals", animal.New(configuration).Routes())
+		// Kept for the clients written when the clinic only treated cats
+		r.Mount("/cat", animal.NewForSpecies(configuration, model.SpeciesCat).Routes())
+		r.Mount("/visits", visit.New(configuration).CalendarRoutes())
+		r.Mount("/{animalid}/visits", visit.New(configuration).Routes())
+		r.Mount("/photos", photo.New(configuration).Routes())
+		r.Mount("/treatments", treatment.New(configuration).Routes())
+		r.Mount("/schedule", schedule.New(configuration).Routes())
+		r.Mount("/calendar", calendar.New(configuration).Routes())
 	})
 
 	return router
@@ -51,6 +79,14 @@ func Routes(configuration *config.Config) *chi.Mux {
 // @host
 // @BasePath /api/v1
 func main() {
+	rotateKeys := flag.Bool("rotate-keys", false, "generate a new token signing key and exit")
+	flag.Parse()
+
+	if *rotateKeys {
+		runRotateKeys()
+		return
+	}
+
 	// We initialize the projet
 	configuration, err := config.New()
 	if err != nil {
@@ -76,3 +112,24 @@ func main() {
 	log.Printf("connect to http://localhost:%s/swagger/index.html for documentation", configuration.Constants.Port)
 	log.Fatal(http.ListenAndServe(":"+configuration.Constants.Port, router))
 }
+
+// runRotateKeys rotates the signing key without starting the server, the
+// running instances pick the new key up on their own
+func runRotateKeys() {
+	constants, err := config.NewConstants()
+	if err != nil {
+		log.Panicln("Configuration error: ", err)
+	}
+
+	keys, err := config.OpenKeyStore(constants)
+	if err != nil {
+		log.Panicln("Key store error: ", err)
+	}
+
+	key, err := keys.Rotate()
+	if err != nil {
+		log.Panicln("Key rotation error: ", err)
+	}
+
+	log.Printf("New signing key %s (%s)", key.ID, key.Algorithm)
+}
@@ -0,0 +1,512 @@
+package animal
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/helper"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// Get godoc
+// @Summary Get an animal
+// @Description Get an animal by its id, the /cat route only finds cats
+// @Tags animals
+// @Param id path int true "Animal ID"
+// @Param include query string false "vitals to include the animal's vitals"
+// @Success 200 {object} Animal "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id} [get]
+// @Router /cat/{id} [get]
+func (config *Config) Get(w http.ResponseWriter, r *http.Request) {
+	fields := &dbmodel.AnimalsFieldsToInclude{
+		Vitals: r.URL.Query().Get("include") == "vitals",
+	}
+
+	dbAnimal := config.findAnimal(w, r, fields)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	render.JSON(w, r, dbAnimal.ToModel())
+}
+
+// GetAll godoc
+// @Summary Get all animals
+// @Description Get all animals, clients only get the animals they own. The /cat route only returns cats.
+// @Tags animals
+// @Param species query string false "only the animals of this species"
+// @Success 200 {array} Animal "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals [get]
+// @Router /cat [get]
+func (config *Config) GetAll(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	filter := dbmodel.AnimalsFilterForUser(loggedUser)
+
+	species := config.species
+	if species == "" {
+		species = r.URL.Query().Get("species")
+	}
+
+	if species != "" {
+		dbSpecies := config.findSpecies(w, r, species)
+
+		if dbSpecies == nil {
+			return
+		}
+
+		filter.SpeciesID = &dbSpecies.ID
+	}
+
+	dbAnimals, err := config.AnimalsRepository.FindAll(filter, nil)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	animals := make([]model.Animal, 0, len(dbAnimals))
+
+	for _, dbAnimal := range dbAnimals {
+		animals = append(animals, *dbAnimal.ToModel())
+	}
+
+	render.JSON(w, r, animals)
+}
+
+// Create godoc
+// @Summary Create an animal
+// @Description Create an animal, clients own the animals they create while the staff can set their owner. The species is required, except on the /cat route.
+// @Tags animals
+// @Param animal body AnimalCreatePayload true "Animal data"
+// @Success 201 {object} Animal "created"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 409 {string} string "identifier already registered"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals [post]
+// @Router /cat [post]
+func (config *Config) Create(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
+	data := &model.AnimalCreatePayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	species := config.species
+
+	if data.Species != nil && species != "" && *data.Species != species {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("only %s can be created on this route", species)))
+		return
+	}
+
+	if species == "" {
+		if data.Species == nil {
+			render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("missing species property")))
+			return
+		}
+
+		species = *data.Species
+	}
+
+	dbSpecies := config.findSpecies(w, r, species)
+
+	if dbSpecies == nil {
+		return
+	}
+
+	if err := dbSpecies.ToModel().ValidateAttributes(data.Attributes); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	// Clients own the animals they create, the staff creates them on behalf
+	// of their owner
+	ownerID := data.OwnerID
+
+	if loggedUser.RoleID == model.RoleClient {
+		if forbidden := data.StaffFields(); len(forbidden) > 0 {
+			render.Render(w, r, errors.ErrForbiddenFields(forbidden))
+			return
+		}
+
+		if ownerID != nil && *ownerID != loggedUser.ID {
+			render.Render(w, r, errors.ErrForbidden("cannot create an animal for another user"))
+			return
+		}
+
+		ownerID = &loggedUser.ID
+	} else if ownerID != nil && !config.checkOwner(w, r, *ownerID) {
+		return
+	}
+
+	dbAnimal := &dbmodel.Animal{
+		Name:       data.Name,
+		SpeciesID:  dbSpecies.ID,
+		Attributes: data.Attributes,
+		OwnerID:    ownerID,
+		Breed:      data.Breed,
+		BirthDate:  data.BirthDate,
+		Sex:        model.SexUnknown,
+		NeuteredAt: data.NeuteredAt,
+		Neutered:   data.NeuteredAt != nil || (data.Neutered != nil && *data.Neutered),
+		Color:      data.Color,
+		Microchip:  data.Microchip,
+		Tattoo:     data.Tattoo,
+	}
+
+	if data.Sex != nil {
+		dbAnimal.Sex = *data.Sex
+	}
+
+	if !config.checkIdentifiers(w, r, dbAnimal) {
+		return
+	}
+
+	dbAnimal, err := config.AnimalsRepository.Create(dbAnimal)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	dbAnimal.Species = *dbSpecies
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, dbAnimal.ToModel())
+}
+
+// Update godoc
+// @Summary Update an animal
+// @Description Update an animal, the /cat route only finds cats
+// @Tags animals
+// @Param id path int true "Animal ID"
+// @Param animal body AnimalUpdatePayload true "Animal data"
+// @Success 200 {object} Animal "ok"
+// @Failure 400 {object} ErrResponse "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "identifier already registered"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id} [put]
+// @Router /cat/{id} [put]
+func (config *Config) Update(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
+	dbAnimal := config.findAnimal(w, r, nil)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	data := &model.AnimalUpdatePayload{}
+
+	forbidden, err := helper.DecodeAllowed(r.Body, model.AnimalUpdateFields[loggedUser.RoleID], data)
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if len(forbidden) > 0 {
+		render.Render(w, r, errors.ErrForbiddenFields(forbidden))
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if data.Name != nil {
+		dbAnimal.Name = *data.Name
+	}
+
+	if data.Attributes != nil {
+		if err := dbAnimal.Species.ToModel().ValidateAttributes(data.Attributes); err != nil {
+			render.Render(w, r, errors.ErrInvalidRequest(err))
+			return
+		}
+
+		dbAnimal.Attributes = data.Attributes
+	}
+
+	if data.OwnerID != nil {
+		if !config.checkOwner(w, r, *data.OwnerID) {
+			return
+		}
+
+		dbAnimal.OwnerID = data.OwnerID
+	}
+
+	if data.Breed != nil {
+		dbAnimal.Breed = data.Breed
+	}
+
+	if data.BirthDate != nil {
+		dbAnimal.BirthDate = data.BirthDate
+	}
+
+	if data.Sex != nil {
+		dbAnimal.Sex = *data.Sex
+	}
+
+	if data.Neutered != nil {
+		dbAnimal.Neutered = *data.Neutered
+
+		if !dbAnimal.Neutered {
+			dbAnimal.NeuteredAt = nil
+		}
+	}
+
+	if data.NeuteredAt != nil {
+		dbAnimal.Neutered = true
+		dbAnimal.NeuteredAt = data.NeuteredAt
+	}
+
+	if dbAnimal.BirthDate != nil && dbAnimal.NeuteredAt != nil && dbAnimal.NeuteredAt.Before(*dbAnimal.BirthDate) {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("neutered_at is before birth_date")))
+		return
+	}
+
+	if data.Color != nil {
+		dbAnimal.Color = data.Color
+	}
+
+	if data.Microchip != nil {
+		dbAnimal.Microchip = data.Microchip
+	}
+
+	if data.Tattoo != nil {
+		dbAnimal.Tattoo = data.Tattoo
+	}
+
+	if !config.checkIdentifiers(w, r, dbAnimal) {
+		return
+	}
+
+	dbAnimal, err = config.AnimalsRepository.Update(dbAnimal)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, dbAnimal.ToModel())
+}
+
+// Delete godoc
+// @Summary Delete an animal
+// @Description Move an animal and its visits to the trash, only available to the staff
+// @Tags animals
+// @Param id path int true "Animal ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id} [delete]
+// @Router /cat/{id} [delete]
+func (config *Config) Delete(w http.ResponseWriter, r *http.Request) {
+	dbAnimal := config.findAnimal(w, r, nil)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	if err := config.AnimalsRepository.Delete(dbAnimal); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// GetByMicrochip godoc
+// @Summary Get an animal by its microchip
+// @Description Identify an animal, with its owner, from its ISO 11784 microchip number. Only available to the staff.
+// @Tags animals
+// @Param number path string true "Microchip number, 15 digits"
+// @Success 200 {object} Animal "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/by-chip/{number} [get]
+// @Router /cat/by-chip/{number} [get]
+func (config *Config) GetByMicrochip(w http.ResponseWriter, r *http.Request) {
+	number, err := model.NormalizeMicrochip(chi.URLParam(r, "number"))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	dbAnimal, err := config.AnimalsRepository.FindByMicrochip(number, &dbmodel.AnimalsFieldsToInclude{
+		Owner: true,
+	})
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if dbAnimal == nil || (config.species != "" && dbAnimal.Species.Code != config.species) {
+		render.Render(w, r, errors.ErrNotFound())
+		return
+	}
+
+	render.JSON(w, r, dbAnimal.ToModel())
+}
+
+// GetSpecies godoc
+// @Summary Get the species
+// @Description Get the species treated by the clinic, with the optional attributes of their animals
+// @Tags animals
+// @Success 200 {array} Species "ok"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/species [get]
+func (config *Config) GetSpecies(w http.ResponseWriter, r *http.Request) {
+	dbSpecies, err := config.SpeciesRepository.FindAll()
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	species := make([]model.Species, 0, len(dbSpecies))
+
+	for _, s := range dbSpecies {
+		species = append(species, *s.ToModel())
+	}
+
+	render.JSON(w, r, species)
+}
+
+// Private
+
+// findAnimal returns the animal of the id URL parameter when the logged user
+// can access it, the error is rendered when nil is returned
+func (config *Config) findAnimal(w http.ResponseWriter, r *http.Request, fields *dbmodel.AnimalsFieldsToInclude) *dbmodel.Animal {
+	loggedUser := authentication.ForContext(r.Context())
+
+	id := chi.URLParam(r, "id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return nil
+	}
+
+	dbAnimal, err := config.AnimalsRepository.FindByID(uint(idUint), dbmodel.AnimalsFilterForUser(loggedUser), fields)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbAnimal == nil || (config.species != "" && dbAnimal.Species.Code != config.species) {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbAnimal
+}
+
+// findSpecies renders an error and returns nil when the species doesn't exist
+func (config *Config) findSpecies(w http.ResponseWriter, r *http.Request, code string) *dbmodel.Species {
+	dbSpecies, err := config.SpeciesRepository.FindByCode(code)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbSpecies == nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("unknown species %q", code)))
+		return nil
+	}
+
+	return dbSpecies
+}
+
+// checkIdentifiers renders an error and returns false when the microchip or
+// the tattoo of the animal is already used by another one
+func (config *Config) checkIdentifiers(w http.ResponseWriter, r *http.Request, animal *dbmodel.Animal) bool {
+	if animal.Microchip != nil {
+		other, err := config.AnimalsRepository.FindByMicrochip(*animal.Microchip, nil)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return false
+		}
+
+		if other != nil && other.ID != animal.ID {
+			render.Render(w, r, errors.ErrConflict("microchip already registered"))
+			return false
+		}
+	}
+
+	if animal.Tattoo != nil {
+		other, err := config.AnimalsRepository.FindByTattoo(*animal.Tattoo)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return false
+		}
+
+		if other != nil && other.ID != animal.ID {
+			render.Render(w, r, errors.ErrConflict("tattoo already registered"))
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkOwner renders an error and returns false when the owner doesn't exist
+func (config *Config) checkOwner(w http.ResponseWriter, r *http.Request, ownerID uint) bool {
+	owner, err := config.UserRepository.FindByID(ownerID, false)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return false
+	}
+
+	if owner == nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("unknown owner_id")))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,316 @@
+package animal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"strconv"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/imaging"
+	"feldrise.com/animal-api/pkg/model"
+	"feldrise.com/animal-api/pkg/photo"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// The dimensions of the sizes of the photos, in pixels
+const (
+	largePhotoSize     = 2048
+	mediumPhotoSize    = 800
+	thumbnailPhotoSize = 200
+)
+
+// GetPhotos godoc
+// @Summary Get the photos of an animal
+// @Description Get the photos of an animal, the most recent first. Their files are served by the signed URLs.
+// @Tags photos
+// @Param id path int true "Animal ID"
+// @Success 200 {array} Photo "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id}/photos [get]
+// @Router /cat/{id}/photos [get]
+func (config *Config) GetPhotos(w http.ResponseWriter, r *http.Request) {
+	dbAnimal := config.findAnimal(w, r, nil)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	dbPhotos, err := config.PhotosRepository.FindAll(dbAnimal.ID, false)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	photos := make([]model.Photo, 0, len(dbPhotos))
+
+	for _, dbPhoto := range dbPhotos {
+		photos = append(photos, *dbPhoto.ToModel())
+	}
+
+	render.JSON(w, r, photos)
+}
+
+// UploadPhoto godoc
+// @Summary Upload a photo of an animal
+// @Description Upload a JPEG, PNG or HEIC photo of an animal. Its metadata are removed and it is stored in a large, a medium and a thumbnail size. The first photo of an animal becomes its profile picture.
+// @Tags photos
+// @Accept multipart/form-data
+// @Param id path int true "Animal ID"
+// @Param photo formData file true "JPEG, PNG or HEIC photo"
+// @Success 201 {object} Photo "created"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the same photo is being stored"
+// @Failure 413 {string} string "photo too large"
+// @Failure 415 {string} string "unsupported photo format"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id}/photos [post]
+// @Router /cat/{id}/photos [post]
+func (config *Config) UploadPhoto(w http.ResponseWriter, r *http.Request) {
+	dbAnimal := config.findAnimal(w, r, nil)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	maxSize := config.Constants.MaxPhotoSize
+	tooLarge := fmt.Sprintf("photo too large, %d MB at most", maxSize>>20)
+
+	// Leave some room for the rest of the multipart body
+	if r.ContentLength > maxSize+1<<20 {
+		render.Render(w, r, errors.ErrRequestTooLarge(tooLarge))
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+1<<20)
+
+	// The files of the form are all written to temporary files rather than
+	// kept in memory, the server removes them after the request
+	if err := r.ParseMultipartForm(0); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	file, header, err := r.FormFile("photo")
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+	defer file.Close()
+
+	if header.Size > maxSize {
+		render.Render(w, r, errors.ErrRequestTooLarge(tooLarge))
+		return
+	}
+
+	// Photos of the same file share their sizes, the key is the hash of the
+	// file as uploaded
+	hash := sha256.New()
+
+	if _, err = io.Copy(hash, file); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	key := hex.EncodeToString(hash.Sum(nil))
+
+	// The format is found from the first bytes, the content type given by the
+	// client can't be trusted
+	start := make([]byte, imaging.FormatLength)
+	n, err := file.ReadAt(start, 0)
+
+	if err != nil && err != io.EOF {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	var content io.ReadSeeker = io.NewSectionReader(file, 0, header.Size)
+
+	switch imaging.Format(start[:n]) {
+	case imaging.FormatJPEG, imaging.FormatPNG:
+	case imaging.FormatHEIC:
+		converted, err := imaging.ConvertHEIC(config.Constants.HEICConverter, content)
+
+		if err == imaging.ErrHEICUnsupported {
+			render.Render(w, r, errors.ErrUnsupportedMediaType(err.Error()))
+			return
+		}
+
+		if err != nil {
+			render.Render(w, r, errors.ErrInvalidRequest(err))
+			return
+		}
+
+		content = bytes.NewReader(converted)
+	default:
+		render.Render(w, r, errors.ErrUnsupportedMediaType(imaging.ErrUnsupportedFormat.Error()))
+		return
+	}
+
+	img, err := imaging.Decode(content)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid photo: %w", err)))
+		return
+	}
+
+	large := imaging.Fit(img, largePhotoSize)
+
+	dbPhoto := &dbmodel.Photo{
+		AnimalID: dbAnimal.ID,
+		Key:      key,
+		Width:    large.Bounds().Dx(),
+		Height:   large.Bounds().Dy(),
+	}
+
+	sizes := []struct {
+		Name  string
+		Image func() image.Image
+	}{
+		{model.PhotoSizeThumbnail, func() image.Image { return imaging.Square(img, thumbnailPhotoSize) }},
+		{model.PhotoSizeMedium, func() image.Image { return imaging.Fit(img, mediumPhotoSize) }},
+		// The large size is stored last, so the sizes are all there when it is
+		{model.PhotoSizeLarge, func() image.Image { return large }},
+	}
+
+	err = photo.Create(config.Config, dbPhoto, func() error {
+		for _, size := range sizes {
+			content, err := imaging.EncodeJPEG(size.Image())
+
+			if err == nil {
+				err = config.Storage.Put(dbPhoto.StorageKey(size.Name), bytes.NewReader(content), int64(len(content)), "image/jpeg")
+			}
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err == photo.ErrKeyLocked {
+		render.Render(w, r, errors.ErrConflict(err.Error()))
+		return
+	}
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if dbAnimal.ProfilePhoto == nil {
+		if err := config.PhotosRepository.SetProfile(dbPhoto); err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, dbPhoto.ToModel())
+}
+
+// SetProfilePhoto godoc
+// @Summary Choose the profile picture of an animal
+// @Description Make a photo the profile picture of its animal
+// @Tags photos
+// @Param id path int true "Animal ID"
+// @Param photoid path int true "Photo ID"
+// @Success 200 {object} Photo "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id}/photos/{photoid}/profile [post]
+// @Router /cat/{id}/photos/{photoid}/profile [post]
+func (config *Config) SetProfilePhoto(w http.ResponseWriter, r *http.Request) {
+	dbPhoto := config.findPhoto(w, r)
+
+	if dbPhoto == nil {
+		return
+	}
+
+	if err := config.PhotosRepository.SetProfile(dbPhoto); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, dbPhoto.ToModel())
+}
+
+// DeletePhoto godoc
+// @Summary Delete a photo
+// @Description Permanently delete a photo of an animal, and its files unless another photo has the same file
+// @Tags photos
+// @Param id path int true "Animal ID"
+// @Param photoid path int true "Photo ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id}/photos/{photoid} [delete]
+// @Router /cat/{id}/photos/{photoid} [delete]
+func (config *Config) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	dbPhoto := config.findPhoto(w, r)
+
+	if dbPhoto == nil {
+		return
+	}
+
+	if err := config.PhotosRepository.Delete(dbPhoto); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	photo.DeleteFiles(config.Config, dbPhoto)
+
+	render.NoContent(w, r)
+}
+
+// Private
+
+// findPhoto returns the photo of the photoid URL parameter when the logged
+// user can access its animal, the error is rendered when nil is returned
+func (config *Config) findPhoto(w http.ResponseWriter, r *http.Request) *dbmodel.Photo {
+	dbAnimal := config.findAnimal(w, r, nil)
+
+	if dbAnimal == nil {
+		return nil
+	}
+
+	id := chi.URLParam(r, "photoid")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return nil
+	}
+
+	dbPhoto, err := config.PhotosRepository.FindByID(uint(idUint))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbPhoto == nil || dbPhoto.AnimalID != dbAnimal.ID {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbPhoto
+}
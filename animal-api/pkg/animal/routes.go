@@ -0,0 +1,49 @@
+package animal
+
+import (
+	"feldrise.com/animal-api/config"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+)
+
+func New(configuration *config.Config) *Config {
+	return &Config{Config: configuration}
+}
+
+// NewForSpecies returns the routes restricted to the animals of a species,
+// like /cat which predates the other species
+func NewForSpecies(configuration *config.Config, species string) *Config {
+	return &Config{Config: configuration, species: species}
+}
+
+func (config *Config) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	if config.species == "" {
+		router.With(authentication.RequireRole(model.AllRoles...)).Get("/species", config.GetSpecies)
+	}
+
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/", config.GetAll)
+	router.With(authentication.RequireRole(model.AllRoles...)).Post("/", config.Create)
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/{id}", config.Get)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Get("/by-chip/{number}", config.GetByMicrochip)
+	router.With(authentication.RequireRole(model.AllRoles...)).Put("/{id}", config.Update)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Delete("/{id}", config.Delete)
+
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/{id}/vitals", config.GetVitals)
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/{id}/vitals/series", config.GetVitalsSeries)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Post("/{id}/vitals", config.CreateVital)
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/{id}/vitals/{vitalid}", config.GetVital)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Put("/{id}/vitals/{vitalid}", config.UpdateVital)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Delete("/{id}/vitals/{vitalid}", config.DeleteVital)
+
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/{id}/treatments", config.GetTreatments)
+
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/{id}/photos", config.GetPhotos)
+	router.With(authentication.RequireRole(model.AllRoles...)).Post("/{id}/photos", config.UploadPhoto)
+	router.With(authentication.RequireRole(model.AllRoles...)).Post("/{id}/photos/{photoid}/profile", config.SetProfilePhoto)
+	router.With(authentication.RequireRole(model.AllRoles...)).Delete("/{id}/photos/{photoid}", config.DeletePhoto)
+
+	return router
+}
@@ -0,0 +1,44 @@
+package animal
+
+import (
+	"net/http"
+
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/render"
+)
+
+// GetTreatments godoc
+// @Summary Get the treatment history of an animal
+// @Description Get the treatments given or prescribed during all the visits of an animal, the most recent visit first
+// @Tags treatments
+// @Param id path int true "Animal ID"
+// @Success 200 {array} VisitTreatment "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id}/treatments [get]
+// @Router /cat/{id}/treatments [get]
+func (config *Config) GetTreatments(w http.ResponseWriter, r *http.Request) {
+	dbAnimal := config.findAnimal(w, r, nil)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	dbTreatments, err := config.VisitTreatmentsRepository.FindByAnimal(dbAnimal.ID)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	treatments := make([]model.VisitTreatment, 0, len(dbTreatments))
+
+	for _, dbTreatment := range dbTreatments {
+		treatments = append(treatments, *dbTreatment.ToModel())
+	}
+
+	render.JSON(w, r, treatments)
+}
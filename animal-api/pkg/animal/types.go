@@ -0,0 +1,10 @@
+package animal
+
+import "feldrise.com/animal-api/config"
+
+type Config struct {
+	*config.Config
+
+	// When set, only the animals of this species are handled
+	species string
+}
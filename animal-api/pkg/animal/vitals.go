@@ -0,0 +1,416 @@
+package animal
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/helper"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// The period a series covers when no start is given
+const defaultSeriesPeriod = 365 * 24 * time.Hour
+
+// GetVitals godoc
+// @Summary Get the vitals of an animal
+// @Description Get the measurements taken on an animal, the oldest first
+// @Tags vitals
+// @Param id path int true "Animal ID"
+// @Param from query string false "only the measurements taken since this date, RFC 3339"
+// @Param to query string false "only the measurements taken until this date, RFC 3339"
+// @Success 200 {array} Vital "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id}/vitals [get]
+// @Router /cat/{id}/vitals [get]
+func (config *Config) GetVitals(w http.ResponseWriter, r *http.Request) {
+	dbAnimal := config.findAnimal(w, r, nil)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	filter := &dbmodel.VitalsFilter{AnimalID: dbAnimal.ID}
+
+	var err error
+
+	if filter.From, err = queryTime(r.URL.Query().Get("from")); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if filter.To, err = queryTime(r.URL.Query().Get("to")); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	dbVitals, err := config.VitalsRepository.FindAll(filter)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	vitals := make([]model.Vital, 0, len(dbVitals))
+
+	for _, dbVital := range dbVitals {
+		vitals = append(vitals, *dbVital.ToModel())
+	}
+
+	render.JSON(w, r, vitals)
+}
+
+// GetVitalsSeries godoc
+// @Summary Get the vitals series of an animal
+// @Description Get the measurements of an animal averaged by day, week or month for charts. The bucket is chosen from the period when not given. The weight loss alert looks at the last weights, whatever the period.
+// @Tags vitals
+// @Param id path int true "Animal ID"
+// @Param from query string false "start of the series, RFC 3339, a year before to by default"
+// @Param to query string false "end of the series, RFC 3339, now by default"
+// @Param bucket query string false "day, week or month"
+// @Success 200 {object} VitalsSeries "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id}/vitals/series [get]
+// @Router /cat/{id}/vitals/series [get]
+func (config *Config) GetVitalsSeries(w http.ResponseWriter, r *http.Request) {
+	dbAnimal := config.findAnimal(w, r, nil)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	query := r.URL.Query()
+
+	to, err := queryTime(query.Get("to"))
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if to == nil {
+		now := time.Now()
+		to = &now
+	}
+
+	from, err := queryTime(query.Get("from"))
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if from == nil {
+		start := to.Add(-defaultSeriesPeriod)
+		from = &start
+	}
+
+	if from.After(*to) {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("from is after to")))
+		return
+	}
+
+	bucket := query.Get("bucket")
+
+	switch bucket {
+	case "":
+		bucket = model.SeriesBucket(*from, *to)
+	case model.BucketDay, model.BucketWeek, model.BucketMonth:
+	default:
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid bucket, day, week or month expected")))
+		return
+	}
+
+	points, err := config.VitalsRepository.Series(&dbmodel.VitalsFilter{
+		AnimalID: dbAnimal.ID,
+		From:     from,
+		To:       to,
+	}, bucket)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	dbWeights, err := config.VitalsRepository.FindLastWeights(dbAnimal.ID, config.Constants.WeightLossPeriod)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	weights := make([]model.WeightMeasure, 0, len(dbWeights))
+
+	for _, dbWeight := range dbWeights {
+		weights = append(weights, model.WeightMeasure{Weight: *dbWeight.Weight, Date: dbWeight.RecordedAt})
+	}
+
+	render.JSON(w, r, &model.VitalsSeries{
+		Bucket:     bucket,
+		From:       *from,
+		To:         *to,
+		Points:     points,
+		WeightLoss: model.DetectWeightLoss(weights, config.Constants.WeightLossThreshold, config.Constants.WeightLossPeriod),
+	})
+}
+
+// GetVital godoc
+// @Summary Get a vital
+// @Description Get a measurement taken on an animal
+// @Tags vitals
+// @Param id path int true "Animal ID"
+// @Param vitalid path int true "Vital ID"
+// @Success 200 {object} Vital "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id}/vitals/{vitalid} [get]
+// @Router /cat/{id}/vitals/{vitalid} [get]
+func (config *Config) GetVital(w http.ResponseWriter, r *http.Request) {
+	dbVital := config.findVital(w, r)
+
+	if dbVital == nil {
+		return
+	}
+
+	render.JSON(w, r, dbVital.ToModel())
+}
+
+// CreateVital godoc
+// @Summary Record vitals
+// @Description Record measurements taken on an animal, during one of its visits or on their own. Only available to the staff.
+// @Tags vitals
+// @Param id path int true "Animal ID"
+// @Param vital body VitalCreatePayload true "Vital data"
+// @Success 201 {object} Vital "created"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id}/vitals [post]
+// @Router /cat/{id}/vitals [post]
+func (config *Config) CreateVital(w http.ResponseWriter, r *http.Request) {
+	dbAnimal := config.findAnimal(w, r, nil)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	data := &model.VitalCreatePayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	recordedAt := time.Now()
+
+	if data.VisitID != nil {
+		dbVisit, err := config.VisitsRepository.FindByID(*data.VisitID, nil)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		if dbVisit == nil || dbVisit.AnimalID != dbAnimal.ID {
+			render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("unknown visit_id")))
+			return
+		}
+
+		recordedAt = dbVisit.Date
+	}
+
+	if data.RecordedAt != nil {
+		recordedAt = *data.RecordedAt
+	}
+
+	dbVital, err := config.VitalsRepository.Create(&dbmodel.Vital{
+		AnimalID:           dbAnimal.ID,
+		VisitID:            data.VisitID,
+		RecordedAt:         recordedAt,
+		Weight:             data.Weight,
+		Temperature:        data.Temperature,
+		HeartRate:          data.HeartRate,
+		RespiratoryRate:    data.RespiratoryRate,
+		BodyConditionScore: data.BodyConditionScore,
+	})
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, dbVital.ToModel())
+}
+
+// UpdateVital godoc
+// @Summary Update a vital
+// @Description Correct a measurement taken on an animal, only available to the staff
+// @Tags vitals
+// @Param id path int true "Animal ID"
+// @Param vitalid path int true "Vital ID"
+// @Param vital body VitalUpdatePayload true "Vital data"
+// @Success 200 {object} Vital "ok"
+// @Failure 400 {object} ErrResponse "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id}/vitals/{vitalid} [put]
+// @Router /cat/{id}/vitals/{vitalid} [put]
+func (config *Config) UpdateVital(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
+	dbVital := config.findVital(w, r)
+
+	if dbVital == nil {
+		return
+	}
+
+	data := &model.VitalUpdatePayload{}
+
+	forbidden, err := helper.DecodeAllowed(r.Body, model.VitalUpdateFields[loggedUser.RoleID], data)
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if len(forbidden) > 0 {
+		render.Render(w, r, errors.ErrForbiddenFields(forbidden))
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if data.RecordedAt != nil {
+		dbVital.RecordedAt = *data.RecordedAt
+	}
+
+	if data.Weight != nil {
+		dbVital.Weight = data.Weight
+	}
+
+	if data.Temperature != nil {
+		dbVital.Temperature = data.Temperature
+	}
+
+	if data.HeartRate != nil {
+		dbVital.HeartRate = data.HeartRate
+	}
+
+	if data.RespiratoryRate != nil {
+		dbVital.RespiratoryRate = data.RespiratoryRate
+	}
+
+	if data.BodyConditionScore != nil {
+		dbVital.BodyConditionScore = data.BodyConditionScore
+	}
+
+	dbVital, err = config.VitalsRepository.Update(dbVital)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, dbVital.ToModel())
+}
+
+// DeleteVital godoc
+// @Summary Delete a vital
+// @Description Permanently delete a measurement entered by mistake, only available to the staff
+// @Tags vitals
+// @Param id path int true "Animal ID"
+// @Param vitalid path int true "Vital ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /animals/{id}/vitals/{vitalid} [delete]
+// @Router /cat/{id}/vitals/{vitalid} [delete]
+func (config *Config) DeleteVital(w http.ResponseWriter, r *http.Request) {
+	dbVital := config.findVital(w, r)
+
+	if dbVital == nil {
+		return
+	}
+
+	if err := config.VitalsRepository.Delete(dbVital); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// Private
+
+// findVital returns the measurement of the vitalid URL parameter when the
+// logged user can access its animal, the error is rendered when nil is
+// returned
+func (config *Config) findVital(w http.ResponseWriter, r *http.Request) *dbmodel.Vital {
+	dbAnimal := config.findAnimal(w, r, nil)
+
+	if dbAnimal == nil {
+		return nil
+	}
+
+	id := chi.URLParam(r, "vitalid")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return nil
+	}
+
+	dbVital, err := config.VitalsRepository.FindByID(uint(idUint), dbAnimal.ID)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbVital == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbVital
+}
+
+func queryTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &date, nil
+}
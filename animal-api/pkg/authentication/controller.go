@@ -2,14 +2,20 @@ package authentication
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"feldrise.com/animal-api/database/dbmodel"
 	"feldrise.com/animal-api/helper"
 	"feldrise.com/animal-api/pkg/errors"
 	"feldrise.com/animal-api/pkg/model"
-	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -54,12 +60,19 @@ func (config *Config) Register(w http.ResponseWriter, r *http.Request) {
 	user := &dbmodel.User{
 		Email:        *data.Email,
 		PasswordHash: hashedPassword,
+		RoleID:       model.RoleClient,
 		UserProfile: &dbmodel.UserProfile{
 			FirstName: data.FirstName,
 			LastName:  data.LastName,
 		},
 	}
 
+	emailToken, err := setEmailToken(user)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
 	user, err = config.UserRepository.Create(user)
 
 	if err != nil {
@@ -67,6 +80,12 @@ func (config *Config) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The user exists now, so a failed mail doesn't fail the registration,
+	// the verification email can be sent again once logged in
+	if err = config.Mailer.Send(emailVerificationMail(user.Email, config.emailVerificationLink(emailToken))); err != nil {
+		log.Printf("Error sending the verification email to user %d: %s", user.ID, err)
+	}
+
 	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, user.ToModel(true, true))
 
@@ -74,13 +93,15 @@ func (config *Config) Register(w http.ResponseWriter, r *http.Request) {
 
 // Login godoc
 // @Summary Login a user
-// @Description Login a user
+// @Description Login a user. When two-factor authentication is enabled, a MFARequired object is returned instead of the tokens.
 // @ID login
 // @Tags autentication
 // @Param request body LoginPostPayload true "user's info"
-// @Success 200 {string} string "ok"
+// @Success 200 {object} UserWithTokens "ok"
 // @Failure 400 {string} string "bad request"
 // @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "account suspended"
+// @Failure 429 {string} string "too many requests"
 // @Failure 500 {string} string "internal server error"
 // @Router /authentication/login [post]
 func (config *Config) Login(w http.ResponseWriter, r *http.Request) {
@@ -91,6 +112,21 @@ func (config *Config) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	accountKey := AccountThrottleKey(*data.Email)
+	ipKey := ipThrottleKey(r)
+
+	wait, err := config.throttleWait(accountKey, ipKey)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if wait > 0 {
+		renderThrottled(w, r, wait)
+		return
+	}
+
 	user, err := config.UserRepository.FindByEmail(*data.Email, true)
 
 	if err != nil {
@@ -98,62 +134,215 @@ func (config *Config) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if user == nil {
+	passwordHash := dummyPasswordHash
+	if user != nil {
+		passwordHash = user.PasswordHash
+	}
+
+	if err = checkPassword(passwordHash, *data.Password); err != nil || user == nil {
+		if err = config.recordLoginFailure(accountKey, accountThrottle); err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		if err = config.recordLoginFailure(ipKey, ipThrottle); err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
 		render.Render(w, r, errors.ErrUnauthorized("invalid email or password"))
 		return
 	}
 
-	err = checkPassword(user.PasswordHash, *data.Password)
+	if err = config.LoginThrottlesRepository.Reset(accountKey); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
 
+	// Only told once the password is known, so it doesn't reveal accounts
+	if user.SuspendedAt != nil {
+		render.Render(w, r, errors.ErrForbidden("account suspended"))
+		return
+	}
+
+	// The login is completed by /authentication/mfa/verify
+	if user.TOTPEnabledAt != nil {
+		mfaToken, _, err := GenerateMFAToken(config.Keys, user.ID, user.RoleID)
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		render.JSON(w, r, &model.MFARequired{
+			MFARequired: true,
+			MFAToken:    mfaToken,
+		})
+		return
+	}
+
+	tokens, err := config.issueTokens(user, "")
 	if err != nil {
-		render.Render(w, r, errors.ErrUnauthorized("invalid email or password"))
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, &model.UserWithTokens{
+		User:   *user.ToModel(true, true),
+		Tokens: *tokens,
+	})
+}
+
+// Refresh godoc
+// @Summary Refresh the tokens
+// @Description Exchange a refresh token for a new access token and a new refresh token. A refresh token can only be used once, using it again ends the session.
+// @ID refresh
+// @Tags autentication
+// @Param request body RefreshPostPayload true "refresh token"
+// @Success 200 {object} Tokens "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "account suspended"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/refresh [post]
+func (config *Config) Refresh(w http.ResponseWriter, r *http.Request) {
+	data := &model.RefreshPostPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
 		return
 	}
 
-	tokenString, err := GenerateToken(config.Constants.JWTSecret, user.ID, user.RoleID)
+	refreshToken, err := config.RefreshTokensRepository.FindByHash(helper.HashToken(*data.RefreshToken))
+
 	if err != nil {
 		render.Render(w, r, errors.ErrServerError(err))
 		return
 	}
 
-	type UserWithToken struct {
-		model.User
-		Token string `json:"token"`
+	if refreshToken == nil || refreshToken.RevokedAt != nil || time.Now().After(refreshToken.ExpiresAt) {
+		render.Render(w, r, errors.ErrUnauthorized("invalid refresh token"))
+		return
+	}
+
+	// A token used twice has most likely been stolen, we end the whole
+	// session so neither the thief nor the user can keep using it
+	if refreshToken.UsedAt != nil {
+		config.revokeFamily(w, r, refreshToken.FamilyID)
+		return
 	}
 
-	render.JSON(w, r, &UserWithToken{
-		User:  *user.ToModel(true, true),
-		Token: tokenString,
-	})
+	user, err := config.UserRepository.FindByID(refreshToken.UserID, true)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if user == nil {
+		render.Render(w, r, errors.ErrUnauthorized("invalid refresh token"))
+		return
+	}
+
+	if user.SuspendedAt != nil {
+		render.Render(w, r, errors.ErrForbidden("account suspended"))
+		return
+	}
+
+	tokens, next, err := config.generateTokens(user, refreshToken.FamilyID)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	rotated, err := config.RefreshTokensRepository.Rotate(refreshToken, next)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if !rotated {
+		config.revokeFamily(w, r, refreshToken.FamilyID)
+		return
+	}
+
+	render.JSON(w, r, tokens)
 }
 
-// Update godoc
-// @Summary Update the current user
-// @Description Update the current user
-// @ID update
+// Logout godoc
+// @Summary Logout the current user
+// @Description Revoke the access token used for the request and, when given, the session of the refresh token
+// @ID logout
 // @Tags autentication
-// @Param request body map[string]interface{} true "user's info"
+// @Param request body LogoutPostPayload false "refresh token"
 // @Success 200 {string} string "ok"
 // @Failure 400 {string} string "bad request"
 // @Failure 401 {string} string "unauthorized"
 // @Failure 500 {string} string "internal server error"
-// @Router /authentication/{id} [put]
-func (config *Config) Update(w http.ResponseWriter, r *http.Request) {
+// @Router /authentication/logout [post]
+func (config *Config) Logout(w http.ResponseWriter, r *http.Request) {
 	loggedUser := ForContext(r.Context())
+	claims := ClaimsForContext(r.Context())
 
-	if loggedUser == nil {
+	if loggedUser == nil || claims == nil {
 		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
 		return
 	}
 
-	var data map[string]interface{}
+	data := &model.LogoutPostPayload{}
 
-	err := json.NewDecoder(r.Body).Decode(&data)
-	if err != nil {
+	// The body is optional
+	if err := json.NewDecoder(r.Body).Decode(data); err != nil && err != io.EOF {
 		render.Render(w, r, errors.ErrInvalidRequest(err))
 		return
 	}
 
+	if data.RefreshToken != nil {
+		refreshToken, err := config.RefreshTokensRepository.FindByHash(helper.HashToken(*data.RefreshToken))
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		if refreshToken != nil && refreshToken.UserID == loggedUser.ID {
+			if err = config.RefreshTokensRepository.RevokeFamily(refreshToken.FamilyID); err != nil {
+				render.Render(w, r, errors.ErrServerError(err))
+				return
+			}
+		}
+	}
+
+	if err := config.RevokedTokensRepository.Revoke(claims.Id, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, "logged out")
+}
+
+// Update godoc
+// @Summary Update the current user
+// @Description Update the current user. Only the staff can change their social security number.
+// @ID update
+// @Tags autentication
+// @Param id path int true "User ID"
+// @Param request body UserUpdatePayload true "user's info"
+// @Success 200 {object} User "ok"
+// @Failure 400 {object} ErrResponse "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/{id} [put]
+func (config *Config) Update(w http.ResponseWriter, r *http.Request) {
+	loggedUser := ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
 	id := chi.URLParam(r, "id")
 
 	// Convert id to uint
@@ -165,11 +354,43 @@ func (config *Config) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if loggedUser.ID != uint(idUint) {
-		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		render.Render(w, r, errors.ErrForbidden("forbidden"))
 		return
 	}
 
-	helper.ApplyChanges(data, loggedUser)
+	data := &model.UserUpdatePayload{}
+
+	forbidden, err := helper.DecodeAllowed(r.Body, model.UserUpdateFields[loggedUser.RoleID], data)
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if len(forbidden) > 0 {
+		render.Render(w, r, errors.ErrForbiddenFields(forbidden))
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if loggedUser.UserProfile == nil {
+		loggedUser.UserProfile = &dbmodel.UserProfile{}
+	}
+
+	if data.FirstName != nil {
+		loggedUser.UserProfile.FirstName = data.FirstName
+	}
+
+	if data.LastName != nil {
+		loggedUser.UserProfile.LastName = data.LastName
+	}
+
+	if data.SocialSecurityNumber != nil {
+		loggedUser.UserProfile.SocialSecurityNumber = data.SocialSecurityNumber
+	}
 
 	user, err := config.UserRepository.Update(loggedUser)
 
@@ -237,8 +458,549 @@ func (config *Config) CheckIfEmailExists(w http.ResponseWriter, r *http.Request)
 	render.JSON(w, r, "not exists")
 }
 
+// ForgotPassword godoc
+// @Summary Ask for a password reset code
+// @Description Send a single-use code by email to reset the password. The response is the same whether the email exists or not, a new code is only sent 2 minutes after the previous one.
+// @ID forgot-password
+// @Tags autentication
+// @Param request body ForgotPasswordPostPayload true "user's email"
+// @Success 200 {string} string "ok"
+// @Failure 400 {string} string "bad request"
+// @Router /authentication/forgot-password [post]
+func (config *Config) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	data := &model.ForgotPasswordPostPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	// The code is sent in the background, so the response doesn't tell
+	// whether the email exists by its time or by an error
+	select {
+	case config.passwordResets <- struct{}{}:
+		go func() {
+			defer func() { <-config.passwordResets }()
+			config.sendPasswordReset(*data.Email)
+		}()
+	default:
+		log.Printf("Too many password resets in progress, the code isn't sent")
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, "code sent")
+}
+
+// ResetPassword godoc
+// @Summary Reset the password
+// @Description Reset the password with the code received by email
+// @ID reset-password
+// @Tags autentication
+// @Param request body ResetPasswordPostPayload true "reset info"
+// @Success 200 {string} string "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/reset-password [post]
+func (config *Config) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	data := &model.ResetPasswordPostPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	user, err := config.UserRepository.FindByEmail(*data.Email, false)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if user == nil || user.PasswordResetToken == nil || user.PasswordResetExpiresAt == nil || time.Now().After(*user.PasswordResetExpiresAt) {
+		render.Render(w, r, errors.ErrUnauthorized("invalid or expired code"))
+		return
+	}
+
+	// The attempt is counted before the code is checked, so parallel wrong
+	// guesses can't go over the limit
+	counted, err := config.UserRepository.CountPasswordResetAttempt(user.ID, passwordResetMaxAttempts)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if !counted {
+		render.Render(w, r, errors.ErrUnauthorized("invalid or expired code"))
+		return
+	}
+
+	// The code is consumed as it is checked, so it is only used once
+	used, err := config.UserRepository.UsePasswordResetToken(user.ID, helper.HashToken(*data.Code))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if !used {
+		render.Render(w, r, errors.ErrUnauthorized("invalid or expired code"))
+		return
+	}
+
+	hashedPassword, err := hashPassword(*data.Password)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	user.PasswordHash = hashedPassword
+	user.PasswordResetToken = nil
+	user.PasswordResetExpiresAt = nil
+	user.PasswordResetAttempts = 0
+
+	if err = config.invalidateSessions(user); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if _, err = config.UserRepository.Update(user); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, "password updated")
+}
+
+// VerifyEmail godoc
+// @Summary Confirm the user's email
+// @Description Confirm the user's email with the token sent by email
+// @ID verify-email
+// @Tags autentication
+// @Param request body VerifyEmailPostPayload true "verification token"
+// @Success 200 {object} User "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/verify-email [post]
+func (config *Config) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	data := &model.VerifyEmailPostPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	user, err := config.UserRepository.FindByEmailToken(helper.HashToken(*data.Token))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if user == nil || user.EmailTokenSentAt == nil || time.Since(*user.EmailTokenSentAt) > emailTokenLifetime {
+		render.Render(w, r, errors.ErrUnauthorized("invalid or expired token"))
+		return
+	}
+
+	if user.PendingEmail != nil {
+		// The email may have been taken since the change was asked
+		existingUser, err := config.UserRepository.FindByEmail(*user.PendingEmail, false)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		if existingUser != nil {
+			render.Render(w, r, errors.ErrUnauthorized("email already exists"))
+			return
+		}
+
+		user.Email = *user.PendingEmail
+		user.PendingEmail = nil
+
+		if err = config.invalidateSessions(user); err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+	}
+
+	now := time.Now()
+	user.EmailVerifedAt = &now
+	user.EmailToken = nil
+	user.EmailTokenSentAt = nil
+
+	user, err = config.UserRepository.Update(user)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, user.ToModel(true, true))
+}
+
+// ResendVerification godoc
+// @Summary Resend the verification email
+// @Description Send a new verification link to the current user
+// @ID resend-verification
+// @Tags autentication
+// @Success 200 {string} string "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 429 {string} string "too many requests"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/resend-verification [post]
+func (config *Config) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	loggedUser := ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
+	if loggedUser.EmailVerifedAt != nil && loggedUser.PendingEmail == nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("email already verified")))
+		return
+	}
+
+	if loggedUser.EmailTokenSentAt != nil {
+		wait := emailTokenCooldown - time.Since(*loggedUser.EmailTokenSentAt)
+
+		if wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+			render.Render(w, r, errors.ErrTooManyRequests("verification email sent recently"))
+			return
+		}
+	}
+
+	emailToken, err := setEmailToken(loggedUser)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if _, err = config.UserRepository.Update(loggedUser); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	email := loggedUser.Email
+	if loggedUser.PendingEmail != nil {
+		email = *loggedUser.PendingEmail
+	}
+
+	if err = config.Mailer.Send(emailVerificationMail(email, config.emailVerificationLink(emailToken))); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+	render.JSON(w, r, "verification email sent")
+}
+
+// ChangePassword godoc
+// @Summary Change the password
+// @Description Change the current user's password. Every other session is ended.
+// @ID change-password
+// @Tags autentication
+// @Param request body ChangePasswordPostPayload true "passwords"
+// @Success 200 {object} Tokens "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/change-password [post]
+func (config *Config) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	loggedUser := ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
+	data := &model.ChangePasswordPostPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if err := checkPassword(loggedUser.PasswordHash, *data.CurrentPassword); err != nil {
+		render.Render(w, r, errors.ErrUnauthorized("invalid password"))
+		return
+	}
+
+	hashedPassword, err := hashPassword(*data.NewPassword)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	loggedUser.PasswordHash = hashedPassword
+
+	if err = config.invalidateSessions(loggedUser); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if _, err = config.UserRepository.Update(loggedUser); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	// The caller gets a new session
+	tokens, err := config.issueTokens(loggedUser, "")
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, tokens)
+}
+
+// ChangeEmail godoc
+// @Summary Change the email
+// @Description Ask to change the current user's email. A verification link is sent to the new address and the change only happens once it is followed, ending every session.
+// @ID change-email
+// @Tags autentication
+// @Param request body ChangeEmailPostPayload true "password and new email"
+// @Success 202 {string} string "accepted"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/change-email [post]
+func (config *Config) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	loggedUser := ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
+	data := &model.ChangeEmailPostPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if err := checkPassword(loggedUser.PasswordHash, *data.Password); err != nil {
+		render.Render(w, r, errors.ErrUnauthorized("invalid password"))
+		return
+	}
+
+	if *data.NewEmail == loggedUser.Email {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("new_email is the current email")))
+		return
+	}
+
+	existingUser, err := config.UserRepository.FindByEmail(*data.NewEmail, false)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if existingUser != nil {
+		render.Render(w, r, errors.ErrUnauthorized("email already exists"))
+		return
+	}
+
+	emailToken, err := setEmailToken(loggedUser)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	loggedUser.PendingEmail = data.NewEmail
+
+	if _, err = config.UserRepository.Update(loggedUser); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if err = config.Mailer.Send(emailVerificationMail(*data.NewEmail, config.emailVerificationLink(emailToken))); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if err = config.Mailer.Send(emailChangeNoticeMail(loggedUser.Email, *data.NewEmail)); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Status(r, http.StatusAccepted)
+	render.JSON(w, r, "verification email sent")
+}
+
+// JWKS godoc
+// @Summary Get the token verification keys
+// @Description Get the public keys verifying the access tokens, as a JSON Web Key Set
+// @ID jwks
+// @Tags autentication
+// @Success 200 {object} JSONWebKeySet "ok"
+// @Router /.well-known/jwks.json [get]
+func (config *Config) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	render.JSON(w, r, config.Keys.JWKS())
+}
+
+// RotateKeys godoc
+// @Summary Rotate the signing key
+// @Description Generate a new signing key, the previous one keeps verifying tokens during the grace period
+// @ID rotate-keys
+// @Tags autentication
+// @Success 201 {object} SigningKey "created"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/keys/rotate [post]
+func (config *Config) RotateKeys(w http.ResponseWriter, r *http.Request) {
+	key, err := config.Keys.Rotate()
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, &model.SigningKey{
+		KeyID:     key.ID,
+		Algorithm: key.Algorithm,
+	})
+}
+
 // Private
 
+// invalidateSessions ends every session of the user, the user still has
+// to be saved
+func (config *Config) invalidateSessions(user *dbmodel.User) error {
+	now := time.Now()
+	user.SessionsRevokedAt = &now
+
+	return config.RefreshTokensRepository.RevokeAllForUser(user.ID)
+}
+
+// issueTokens starts a new session for the user, or continues the given one
+func (config *Config) issueTokens(user *dbmodel.User, familyID string) (*model.Tokens, error) {
+	tokens, refreshToken, err := config.generateTokens(user, familyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = config.RefreshTokensRepository.Create(refreshToken); err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// generateTokens returns new tokens for the user and the refresh token to
+// store, a new session is started when familyID is empty
+func (config *Config) generateTokens(user *dbmodel.User, familyID string) (*model.Tokens, *dbmodel.RefreshToken, error) {
+	var err error
+
+	if familyID == "" {
+		familyID, err = helper.RandomToken(16)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	accessToken, claims, err := GenerateToken(config.Keys, user.ID, user.RoleID, familyID, config.Constants.AccessTokenLifetime)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshTokenString, err := helper.RandomToken(32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken := &dbmodel.RefreshToken{
+		TokenHash: helper.HashToken(refreshTokenString),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(config.Constants.RefreshTokenLifetime),
+		UserID:    user.ID,
+	}
+
+	return &model.Tokens{
+		Token:        accessToken,
+		ExpiresAt:    claims.ExpiresAt,
+		RefreshToken: refreshTokenString,
+	}, refreshToken, nil
+}
+
+func (config *Config) revokeFamily(w http.ResponseWriter, r *http.Request, familyID string) {
+	if err := config.RefreshTokensRepository.RevokeFamily(familyID); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Render(w, r, errors.ErrUnauthorized("refresh token already used"))
+}
+
+// setEmailToken generates a new verification token for the user and
+// returns it, only its hash is kept on the user
+func setEmailToken(user *dbmodel.User) (string, error) {
+	token, err := helper.RandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	tokenHash := helper.HashToken(token)
+	now := time.Now()
+
+	user.EmailToken = &tokenHash
+	user.EmailTokenSentAt = &now
+
+	return token, nil
+}
+
+// sendPasswordReset sends a new password reset code to the user of the email,
+// if any and unless one was sent recently. It runs out of the request, so the
+// errors are only logged.
+func (config *Config) sendPasswordReset(email string) {
+	user, err := config.UserRepository.FindByEmail(email, false)
+
+	if err != nil {
+		log.Printf("Error finding the user for a password reset: %s", err)
+		return
+	}
+
+	if user == nil {
+		return
+	}
+
+	code, err := helper.RandomCode(passwordResetCodeLength)
+	if err != nil {
+		log.Printf("Error generating a password reset code: %s", err)
+		return
+	}
+
+	set, err := config.UserRepository.SetPasswordResetToken(user.ID, helper.HashToken(code), passwordResetCodeLifetime, passwordResetCooldown)
+
+	if err != nil {
+		log.Printf("Error saving the password reset code of user %d: %s", user.ID, err)
+		return
+	}
+
+	if !set {
+		return
+	}
+
+	if err = config.Mailer.Send(passwordResetMail(user.Email, code)); err != nil {
+		log.Printf("Error sending the password reset code to user %d: %s", user.ID, err)
+	}
+}
+
+func (config *Config) emailVerificationLink(token string) string {
+	return strings.TrimSuffix(config.Constants.ApplicationURL, "/") + "/verify-email?token=" + url.QueryEscape(token)
+}
+
 func hashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), 14)
 	if err != nil {
@@ -4,41 +4,97 @@ import (
 	"strings"
 	"time"
 
+	"feldrise.com/animal-api/helper"
+	"feldrise.com/animal-api/pkg/keystore"
 	"github.com/golang-jwt/jwt"
 )
 
-func GenerateToken(secret string, id uint, role uint) (string, error) {
-	secretKey := []byte(secret)
-	token := jwt.New(jwt.SigningMethodHS256)
+// Type of the partial token returned by the login when a second factor is
+// needed, it is only accepted by /authentication/mfa/verify
+const TokenTypeMFA = "mfa"
 
-	claims := token.Claims.(jwt.MapClaims)
-	claims["id"] = id
-	claims["role"] = role
-	claims["exp"] = time.Now().Add(time.Hour * 24 * 365).Unix()
+const mfaTokenLifetime = 5 * time.Minute
 
-	tokenString, err := token.SignedString(secretKey)
+type Claims struct {
+	jwt.StandardClaims
+
+	UserID uint   `json:"id"`
+	RoleID uint   `json:"role"`
+	Type   string `json:"typ,omitempty"` // empty for access tokens
+	// The family of the refresh tokens of the session, empty for partial
+	// tokens
+	SessionID string `json:"sid,omitempty"`
+}
+
+func GenerateToken(keys *keystore.KeyStore, id uint, role uint, sessionID string, lifetime time.Duration) (string, *Claims, error) {
+	return generateToken(keys, id, role, "", sessionID, lifetime)
+}
+
+func GenerateMFAToken(keys *keystore.KeyStore, id uint, role uint) (string, *Claims, error) {
+	return generateToken(keys, id, role, TokenTypeMFA, "", mfaTokenLifetime)
+}
+
+func generateToken(keys *keystore.KeyStore, id uint, role uint, tokenType string, sessionID string, lifetime time.Duration) (string, *Claims, error) {
+	key := keys.SigningKey()
+
+	jti, err := helper.RandomToken(16)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	claims := &Claims{
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(lifetime).Unix(),
+		},
+		UserID:    id,
+		RoleID:    role,
+		Type:      tokenType,
+		SessionID: sessionID,
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+
+	tokenString, err := token.SignedString(key.PrivateKey)
 
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	return tokenString, nil
+	return tokenString, claims, nil
 }
 
-func ParseToken(secret string, tokenString string) (uint, uint, error) {
-	secretKey := []byte(secret)
+func ParseToken(keys *keystore.KeyStore, tokenString string) (*Claims, error) {
 	tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return secretKey, nil
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key := keys.VerificationKey(kid)
+
+		if key == nil {
+			return nil, jwt.NewValidationError("unknown key", jwt.ValidationErrorUnverifiable)
+		}
+
+		// The algorithm is the one of the key, never the one chosen by the
+		// token, otherwise a public key could be used as an HMAC secret
+		if token.Method.Alg() != key.Algorithm {
+			return nil, jwt.NewValidationError("unexpected signing method", jwt.ValidationErrorSignatureInvalid)
+		}
+
+		return key.PublicKey(), nil
 	})
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		id := claims["id"].(float64)
-		roleID := claims["role"].(float64)
+	if err != nil {
+		return nil, err
+	}
 
-		return uint(id), uint(roleID), nil
-	} else {
-		return 0, 0, err
+	if !token.Valid || claims.Id == "" {
+		return nil, jwt.NewValidationError("invalid token", jwt.ValidationErrorClaimsInvalid)
 	}
+
+	return claims, nil
 }
@@ -0,0 +1,50 @@
+package authentication
+
+import (
+	"fmt"
+
+	"feldrise.com/animal-api/pkg/mailer"
+)
+
+func passwordResetMail(email string, code string) *mailer.Message {
+	return &mailer.Message{
+		To:      email,
+		Subject: "Your password reset code",
+		Body: fmt.Sprintf(`Hello,
+
+Someone asked to reset the password of your account. Use the following code to choose a new password:
+
+%s
+
+This code expires in %d minutes and can only be used once. If you did not ask for it, you can ignore this email.
+`, code, int(passwordResetCodeLifetime.Minutes())),
+	}
+}
+
+func emailVerificationMail(email string, link string) *mailer.Message {
+	return &mailer.Message{
+		To:      email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf(`Hello,
+
+Please confirm your email address by opening the following link:
+
+%s
+
+This link expires in %d hours.
+`, link, int(emailTokenLifetime.Hours())),
+	}
+}
+
+func emailChangeNoticeMail(email string, newEmail string) *mailer.Message {
+	return &mailer.Message{
+		To:      email,
+		Subject: "Your email address is about to change",
+		Body: fmt.Sprintf(`Hello,
+
+Someone asked to change the email address of your account to %s. The change will be effective once the new address is confirmed.
+
+If you did not ask for it, please reset your password right away.
+`, newEmail),
+	}
+}
@@ -0,0 +1,514 @@
+package authentication
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/helper"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// EnrollMFA godoc
+// @Summary Start the two-factor authentication enrollment
+// @Description Generate a TOTP secret for the current user. Two-factor authentication is only enabled once a first code is checked with /authentication/mfa/activate.
+// @ID mfa-enroll
+// @Tags autentication
+// @Success 200 {object} MFAEnrollment "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/mfa/enroll [post]
+func (config *Config) EnrollMFA(w http.ResponseWriter, r *http.Request) {
+	loggedUser := ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
+	if loggedUser.TOTPEnabledAt != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("two-factor authentication already enabled")))
+		return
+	}
+
+	secret, err := generateTOTPSecret()
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	loggedUser.TOTPSecret = &secret
+	loggedUser.TOTPLastStep = 0
+
+	if _, err = config.UserRepository.Update(loggedUser); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, &model.MFAEnrollment{
+		Secret: secret,
+		URI:    totpURI(loggedUser.Email, secret),
+	})
+}
+
+// ActivateMFA godoc
+// @Summary Enable two-factor authentication
+// @Description Check a first code from the authenticator application and enable two-factor authentication. The recovery codes are only returned here.
+// @ID mfa-activate
+// @Tags autentication
+// @Param request body MFACodePostPayload true "code"
+// @Success 200 {object} RecoveryCodes "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/mfa/activate [post]
+func (config *Config) ActivateMFA(w http.ResponseWriter, r *http.Request) {
+	loggedUser := ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
+	data := &model.MFACodePostPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if loggedUser.TOTPEnabledAt != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("two-factor authentication already enabled")))
+		return
+	}
+
+	if loggedUser.TOTPSecret == nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("two-factor authentication enrollment not started")))
+		return
+	}
+
+	step, valid := validateTOTP(*loggedUser.TOTPSecret, *data.Code, time.Now(), loggedUser.TOTPLastStep)
+	if !valid {
+		render.Render(w, r, errors.ErrUnauthorized("invalid code"))
+		return
+	}
+
+	codes, err := config.replaceRecoveryCodes(loggedUser)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	now := time.Now()
+	loggedUser.TOTPEnabledAt = &now
+	loggedUser.TOTPLastStep = step
+
+	if _, err = config.UserRepository.Update(loggedUser); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, &model.RecoveryCodes{Codes: codes})
+}
+
+// VerifyMFA godoc
+// @Summary Complete a login with the second factor
+// @Description Exchange the token returned by the login and a TOTP or recovery code for the user's tokens
+// @ID mfa-verify
+// @Tags autentication
+// @Param request body MFAVerifyPostPayload true "token and code"
+// @Success 200 {object} UserWithTokens "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "account suspended"
+// @Failure 429 {string} string "too many requests"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/mfa/verify [post]
+func (config *Config) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	data := &model.MFAVerifyPostPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	claims, err := ParseToken(config.Keys, *data.MFAToken)
+
+	if err != nil || claims.Type != TokenTypeMFA {
+		render.Render(w, r, errors.ErrUnauthorized("invalid mfa token"))
+		return
+	}
+
+	revoked, err := config.RevokedTokensRepository.IsRevoked(claims.Id)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if revoked {
+		render.Render(w, r, errors.ErrUnauthorized("invalid mfa token"))
+		return
+	}
+
+	user, err := config.UserRepository.FindByID(claims.UserID, true)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if user == nil || user.TOTPEnabledAt == nil {
+		render.Render(w, r, errors.ErrUnauthorized("invalid mfa token"))
+		return
+	}
+
+	if user.SuspendedAt != nil {
+		render.Render(w, r, errors.ErrForbidden("account suspended"))
+		return
+	}
+
+	// The codes are short, guesses are throttled like passwords
+	accountKey := AccountThrottleKey(user.Email)
+
+	wait, err := config.throttleWait(accountKey)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if wait > 0 {
+		renderThrottled(w, r, wait)
+		return
+	}
+
+	var valid bool
+	if data.Code != nil {
+		valid, err = config.checkSecondFactor(user, *data.Code)
+	} else {
+		valid, err = config.RecoveryCodesRepository.Use(user.ID, helper.HashToken(normalizeRecoveryCode(*data.RecoveryCode)))
+	}
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if !valid {
+		if err = config.recordLoginFailure(accountKey, accountThrottle); err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		render.Render(w, r, errors.ErrUnauthorized("invalid code"))
+		return
+	}
+
+	if err = config.LoginThrottlesRepository.Reset(accountKey); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	// The partial token can only be exchanged once
+	if err = config.RevokedTokensRepository.Revoke(claims.Id, time.Unix(claims.ExpiresAt, 0)); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if _, err = config.UserRepository.Update(user); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	tokens, err := config.issueTokens(user, "")
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, &model.UserWithTokens{
+		User:   *user.ToModel(true, true),
+		Tokens: *tokens,
+	})
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary Regenerate the recovery codes
+// @Description Replace the current user's recovery codes, the previous ones stop working
+// @ID mfa-recovery-codes
+// @Tags autentication
+// @Param request body MFACodePostPayload true "code"
+// @Success 200 {object} RecoveryCodes "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 429 {string} string "too many requests"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/mfa/recovery-codes [post]
+func (config *Config) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	loggedUser := ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
+	data := &model.MFACodePostPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if loggedUser.TOTPEnabledAt == nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("two-factor authentication not enabled")))
+		return
+	}
+
+	// A stolen session must not allow guessing the codes faster than a login
+	accountKey := AccountThrottleKey(loggedUser.Email)
+
+	if !config.checkThrottle(w, r, accountKey) {
+		return
+	}
+
+	valid, err := config.checkSecondFactor(loggedUser, *data.Code)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if !valid {
+		config.renderAttemptFailure(w, r, accountKey, "invalid code")
+		return
+	}
+
+	if err = config.LoginThrottlesRepository.Reset(accountKey); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	codes, err := config.replaceRecoveryCodes(loggedUser)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if _, err = config.UserRepository.Update(loggedUser); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, &model.RecoveryCodes{Codes: codes})
+}
+
+// DisableMFA godoc
+// @Summary Disable two-factor authentication
+// @Description Disable two-factor authentication for the current user, unless its role requires it
+// @ID mfa-disable
+// @Tags autentication
+// @Param request body MFADisablePostPayload true "password and code"
+// @Success 200 {object} User "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 429 {string} string "too many requests"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/mfa/disable [post]
+func (config *Config) DisableMFA(w http.ResponseWriter, r *http.Request) {
+	loggedUser := ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
+	data := &model.MFADisablePostPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if loggedUser.TOTPEnabledAt == nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("two-factor authentication not enabled")))
+		return
+	}
+
+	accountKey := AccountThrottleKey(loggedUser.Email)
+
+	if !config.checkThrottle(w, r, accountKey) {
+		return
+	}
+
+	if err := checkPassword(loggedUser.PasswordHash, *data.Password); err != nil {
+		config.renderAttemptFailure(w, r, accountKey, "invalid password")
+		return
+	}
+
+	valid, err := config.checkSecondFactor(loggedUser, *data.Code)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if !valid {
+		config.renderAttemptFailure(w, r, accountKey, "invalid code")
+		return
+	}
+
+	if err = config.LoginThrottlesRepository.Reset(accountKey); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	role, err := config.RolesRepository.FindByID(loggedUser.RoleID)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if role != nil && role.RequireMFA {
+		render.Render(w, r, errors.ErrForbidden("two-factor authentication is required for your role"))
+		return
+	}
+
+	if err = config.RecoveryCodesRepository.DeleteAllForUser(loggedUser.ID); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	loggedUser.TOTPSecret = nil
+	loggedUser.TOTPEnabledAt = nil
+	loggedUser.TOTPLastStep = 0
+
+	user, err := config.UserRepository.Update(loggedUser)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, user.ToModel(true, true))
+}
+
+// GetRoles godoc
+// @Summary Get the roles
+// @Description Get the roles and their settings, only available to administrators
+// @ID get-roles
+// @Tags autentication
+// @Success 200 {array} Role "ok"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/roles [get]
+func (config *Config) GetRoles(w http.ResponseWriter, r *http.Request) {
+	dbRoles, err := config.RolesRepository.FindAll()
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	roles := make([]model.Role, 0, len(dbRoles))
+
+	for _, dbRole := range dbRoles {
+		roles = append(roles, *dbRole.ToModel())
+	}
+
+	render.JSON(w, r, roles)
+}
+
+// UpdateRoleSettings godoc
+// @Summary Update a role's settings
+// @Description Choose whether the users having the role must enable two-factor authentication, only available to administrators
+// @ID update-role-settings
+// @Tags autentication
+// @Param id path int true "Role ID"
+// @Param request body RoleSettingsPayload true "settings"
+// @Success 200 {object} Role "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /authentication/roles/{id} [put]
+func (config *Config) UpdateRoleSettings(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	data := &model.RoleSettingsPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	role, err := config.RolesRepository.FindByID(uint(idUint))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if role == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return
+	}
+
+	role.RequireMFA = *data.RequireMFA
+
+	role, err = config.RolesRepository.Update(role)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, role.ToModel())
+}
+
+// Private
+
+// checkSecondFactor accepts a TOTP code or a recovery code. The accepted
+// TOTP step is set on the user, who still has to be saved.
+func (config *Config) checkSecondFactor(user *dbmodel.User, code string) (bool, error) {
+	if user.TOTPSecret == nil {
+		return false, nil
+	}
+
+	if step, valid := validateTOTP(*user.TOTPSecret, code, time.Now(), user.TOTPLastStep); valid {
+		user.TOTPLastStep = step
+		return true, nil
+	}
+
+	return config.RecoveryCodesRepository.Use(user.ID, helper.HashToken(normalizeRecoveryCode(code)))
+}
+
+func (config *Config) replaceRecoveryCodes(user *dbmodel.User) ([]string, error) {
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	codeHashes := make([]string, 0, len(codes))
+	for _, code := range codes {
+		codeHashes = append(codeHashes, helper.HashToken(normalizeRecoveryCode(code)))
+	}
+
+	if err := config.RecoveryCodesRepository.Replace(user.ID, codeHashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
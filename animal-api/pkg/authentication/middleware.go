@@ -6,9 +6,14 @@ import (
 
 	"feldrise.com/animal-api/config"
 	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/render"
 )
 
 var UserCtxKey = contextKey{"user"}
+var ClaimsCtxKey = contextKey{"claims"}
+var MFAEnrollmentCtxKey = contextKey{"mfa-enrollment"}
 
 type contextKey struct {
 	name string
@@ -26,22 +31,79 @@ func Middelware(c *config.Config) func(http.Handler) http.Handler {
 
 			// We get the user from the token
 			tokenString := header
-			userID, _, err := ParseToken(c.Constants.JWTSecret, tokenString)
+			claims, err := ParseToken(c.Keys, tokenString)
 
-			if err != nil {
+			// Partial tokens can't be used as access tokens
+			if err != nil || claims.Type != "" {
 				http.Error(w, "Invalid token", http.StatusUnauthorized)
 				return
 			}
 
-			user, err := c.UserRepository.FindByID(userID, true)
+			revoked, err := c.RevokedTokensRepository.IsRevoked(claims.Id)
+
+			if err != nil {
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+
+			if revoked {
+				http.Error(w, "Revoked token", http.StatusUnauthorized)
+				return
+			}
+
+			user, err := c.UserRepository.FindByID(claims.UserID, true)
 
 			if err != nil {
 				next.ServeHTTP(w, r)
 				return
 			}
 
+			if user != nil && user.SessionsRevokedAt != nil && claims.IssuedAt <= user.SessionsRevokedAt.Unix() {
+				// The times are in seconds, so a token of the second of the
+				// revocation is only kept when its session started after it
+				active := false
+
+				if claims.IssuedAt == user.SessionsRevokedAt.Unix() && claims.SessionID != "" {
+					active, err = c.RefreshTokensRepository.IsFamilyActive(claims.SessionID)
+
+					if err != nil {
+						http.Error(w, "Internal server error", http.StatusInternalServerError)
+						return
+					}
+				}
+
+				if !active {
+					http.Error(w, "Revoked token", http.StatusUnauthorized)
+					return
+				}
+			}
+
+			if user != nil && user.SuspendedAt != nil {
+				http.Error(w, "Suspended account", http.StatusForbidden)
+				return
+			}
+
+			// The role in the token must be the current one, otherwise a
+			// demoted user would keep its previous rights
+			if user != nil && user.RoleID != claims.RoleID {
+				http.Error(w, "Outdated token", http.StatusUnauthorized)
+				return
+			}
+
 			// We add the user to the context
 			ctx := context.WithValue(r.Context(), UserCtxKey, user)
+			ctx = context.WithValue(ctx, ClaimsCtxKey, claims)
+
+			if user != nil && user.TOTPEnabledAt == nil {
+				role, err := c.RolesRepository.FindByID(user.RoleID)
+
+				if err != nil {
+					http.Error(w, "Internal server error", http.StatusInternalServerError)
+					return
+				}
+
+				ctx = context.WithValue(ctx, MFAEnrollmentCtxKey, role != nil && role.RequireMFA)
+			}
 
 			r = r.WithContext(ctx)
 			next.ServeHTTP(w, r)
@@ -49,12 +111,64 @@ func Middelware(c *config.Config) func(http.Handler) http.Handler {
 	}
 }
 
+// RequireLogin lets through any logged user, including the ones who still
+// have to enable two-factor authentication
+func RequireLogin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ForContext(r.Context()) == nil {
+			render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireRole only lets through the logged users having one of the roles.
+// Users whose role requires two-factor authentication must have enabled it.
+func RequireRole(roles ...uint) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := ForContext(r.Context())
+
+			if user == nil {
+				render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+				return
+			}
+
+			if enrollmentRequired, _ := r.Context().Value(MFAEnrollmentCtxKey).(bool); enrollmentRequired {
+				render.Render(w, r, errors.ErrForbidden("two-factor authentication required"))
+				return
+			}
+
+			for _, role := range roles {
+				if user.RoleID == role {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			render.Render(w, r, errors.ErrForbidden("forbidden"))
+		})
+	}
+}
+
 func AdminMiddleware(next http.Handler) http.Handler {
+	return RequireRole(model.RoleAdmin)(next)
+}
+
+// RequireVerifiedEmail rejects users who did not confirm their email yet
+func RequireVerifiedEmail(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		user := ForContext(r.Context())
 
 		if user == nil {
-			http.Error(w, "Forbidden", http.StatusForbidden)
+			render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+			return
+		}
+
+		if user.EmailVerifedAt == nil {
+			render.Render(w, r, errors.ErrForbidden("email not verified"))
 			return
 		}
 
@@ -66,3 +180,8 @@ func ForContext(ctx context.Context) *dbmodel.User {
 	raw, _ := ctx.Value(UserCtxKey).(*dbmodel.User)
 	return raw
 }
+
+func ClaimsForContext(ctx context.Context) *Claims {
+	raw, _ := ctx.Value(ClaimsCtxKey).(*Claims)
+	return raw
+}
@@ -2,21 +2,49 @@ package authentication
 
 import (
 	"feldrise.com/animal-api/config"
-	"github.com/go-chi/chi"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
 )
 
 func New(configuration *config.Config) *Config {
-	return &Config{configuration}
+	return &Config{
+		Config:         configuration,
+		passwordResets: make(chan struct{}, maxPasswordResetsInProgress),
+	}
 }
 
 func (config *Config) Routes() *chi.Mux {
 	router := chi.NewRouter()
 
+	// Public
 	router.Post("/register", config.Register)
 	router.Post("/login", config.Login)
-	router.Put("/{id}", config.Update)
+	router.Post("/refresh", config.Refresh)
+	router.Post("/forgot-password", config.ForgotPassword)
+	router.Post("/reset-password", config.ResetPassword)
+	router.Post("/verify-email", config.VerifyEmail)
+	router.Post("/mfa/verify", config.VerifyMFA)
 	router.Get("/check-email", config.CheckIfEmailExists)
-	router.Get("/me", config.Me)
+
+	// Logged users, including the ones who still have to enable two-factor
+	// authentication
+	router.With(RequireLogin).Get("/me", config.Me)
+	router.With(RequireLogin).Post("/resend-verification", config.ResendVerification)
+	router.With(RequireLogin).Post("/logout", config.Logout)
+	router.With(RequireLogin).Post("/change-password", config.ChangePassword)
+	router.With(RequireLogin).Post("/change-email", config.ChangeEmail)
+	router.With(RequireLogin).Post("/mfa/enroll", config.EnrollMFA)
+	router.With(RequireLogin).Post("/mfa/activate", config.ActivateMFA)
+	router.With(RequireLogin).Post("/mfa/recovery-codes", config.RegenerateRecoveryCodes)
+	router.With(RequireLogin).Post("/mfa/disable", config.DisableMFA)
+
+	// Logged users
+	router.With(RequireRole(model.AllRoles...)).Put("/{id}", config.Update)
+
+	// Administrators
+	router.With(RequireRole(model.RoleAdmin)).Post("/keys/rotate", config.RotateKeys)
+	router.With(RequireRole(model.RoleAdmin)).Get("/roles", config.GetRoles)
+	router.With(RequireRole(model.RoleAdmin)).Put("/roles/{id}", config.UpdateRoleSettings)
 
 	return router
 }
@@ -0,0 +1,136 @@
+package authentication
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"feldrise.com/animal-api/pkg/errors"
+	"github.com/go-chi/render"
+)
+
+// Compared with the password when the email is unknown, so the response
+// takes as long as for an existing account
+const dummyPasswordHash = "$2a$14$oOPUL6Ju6EeMdIqSb8kcDujHtGvqrIF2jdXwKGflfBy1T0dR/QXbm"
+
+type throttlePolicy struct {
+	freeAttempts int           // failures allowed before any delay
+	baseDelay    time.Duration // delay after the first counted failure, doubled on each new one
+	maxDelay     time.Duration // the delay stops growing there, the key is locked out
+	window       time.Duration // failures older than that are forgotten
+}
+
+var (
+	accountThrottle = throttlePolicy{
+		freeAttempts: 3,
+		baseDelay:    time.Second,
+		maxDelay:     15 * time.Minute,
+		window:       time.Hour,
+	}
+	// Several users can share an address, so it gets more attempts
+	ipThrottle = throttlePolicy{
+		freeAttempts: 20,
+		baseDelay:    time.Second,
+		maxDelay:     15 * time.Minute,
+		window:       time.Hour,
+	}
+)
+
+func (policy throttlePolicy) delay(failures int) time.Duration {
+	exponent := failures - policy.freeAttempts - 1
+	if exponent < 0 {
+		return 0
+	}
+
+	delay := float64(policy.baseDelay) * math.Pow(2, float64(exponent))
+	if delay > float64(policy.maxDelay) {
+		return policy.maxDelay
+	}
+
+	return time.Duration(delay)
+}
+
+// AccountThrottleKey returns the key counting the failed logins of an email.
+// It doesn't depend on the account existing, so unknown emails are throttled
+// the same way
+func AccountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// throttleWait returns how long to wait before the keys can be tried again
+func (config *Config) throttleWait(keys ...string) (time.Duration, error) {
+	throttles, err := config.LoginThrottlesRepository.FindByKeys(keys...)
+	if err != nil {
+		return 0, err
+	}
+
+	var wait time.Duration
+	for _, throttle := range throttles {
+		if throttle.LockedUntil != nil {
+			if remaining := time.Until(*throttle.LockedUntil); remaining > wait {
+				wait = remaining
+			}
+		}
+	}
+
+	return wait, nil
+}
+
+func (config *Config) recordLoginFailure(key string, policy throttlePolicy) error {
+	throttle, err := config.LoginThrottlesRepository.RecordFailure(key, policy.window)
+	if err != nil {
+		return err
+	}
+
+	if delay := policy.delay(throttle.Failures); delay > 0 {
+		return config.LoginThrottlesRepository.Lock(key, time.Now().Add(delay))
+	}
+
+	return nil
+}
+
+// checkThrottle renders an error and returns false when the key must wait
+// before being tried again
+func (config *Config) checkThrottle(w http.ResponseWriter, r *http.Request, key string) bool {
+	wait, err := config.throttleWait(key)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return false
+	}
+
+	if wait > 0 {
+		renderThrottled(w, r, wait)
+		return false
+	}
+
+	return true
+}
+
+// renderAttemptFailure counts the failed attempt of the account key and
+// renders the message
+func (config *Config) renderAttemptFailure(w http.ResponseWriter, r *http.Request, key string, message string) {
+	if err := config.recordLoginFailure(key, accountThrottle); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Render(w, r, errors.ErrUnauthorized(message))
+}
+
+func renderThrottled(w http.ResponseWriter, r *http.Request, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	render.Render(w, r, errors.ErrTooManyRequests("too many failed attempts, try again later"))
+}
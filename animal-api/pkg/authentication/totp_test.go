@@ -0,0 +1,72 @@
+package authentication
+
+import (
+	"testing"
+	"time"
+)
+
+// The SHA1 test vectors of RFC 6238 Appendix B, with 6 digits instead of 8
+const rfc6238Secret = "12345678901234567890"
+
+func TestTOTPCode(t *testing.T) {
+	tests := []struct {
+		time int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, test := range tests {
+		if got := totpCode([]byte(rfc6238Secret), test.time/totpPeriod); got != test.want {
+			t.Errorf("totpCode(%d) = %s, want %s", test.time, got, test.want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := base32NoPadding.EncodeToString([]byte(rfc6238Secret))
+
+	// 1111111111 is the step 37037037, 1111111109 the step 37037036
+	at := time.Unix(1111111111, 0)
+	current := at.Unix() / totpPeriod
+
+	tests := []struct {
+		name     string
+		code     string
+		at       time.Time
+		lastStep int64
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", code: "050471", at: at, wantStep: current, wantOK: true},
+		{name: "surrounded by spaces", code: " 050471 ", at: at, wantStep: current, wantOK: true},
+		{name: "previous step", code: "081804", at: at, wantStep: current - 1, wantOK: true},
+		{name: "next step", code: "050471", at: at.Add(-totpPeriod * time.Second), wantStep: current, wantOK: true},
+		{name: "past the skew", code: "081804", at: at.Add(2 * totpPeriod * time.Second), wantOK: false},
+		{name: "before the skew", code: "050471", at: at.Add(-2 * totpPeriod * time.Second), wantOK: false},
+		{name: "replayed step", code: "050471", at: at, lastStep: current, wantOK: false},
+		{name: "step before the last one", code: "081804", at: at, lastStep: current - 1, wantOK: false},
+		{name: "step after the last one", code: "050471", at: at, lastStep: current - 1, wantStep: current, wantOK: true},
+		{name: "wrong code", code: "123456", at: at, wantOK: false},
+		{name: "empty code", code: "", at: at, wantOK: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			step, ok := validateTOTP(secret, test.code, test.at, test.lastStep)
+
+			if ok != test.wantOK || step != test.wantStep {
+				t.Errorf("validateTOTP() = %d, %v, want %d, %v", step, ok, test.wantStep, test.wantOK)
+			}
+		})
+	}
+
+	if _, ok := validateTOTP("not base32!", "050471", at, 0); ok {
+		t.Errorf("validateTOTP() with an invalid secret = true, want false")
+	}
+}
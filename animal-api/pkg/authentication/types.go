@@ -1,7 +1,29 @@
 package authentication
 
-import "feldrise.com/animal-api/config"
+import (
+	"time"
+
+	"feldrise.com/animal-api/config"
+)
+
+const (
+	// Password reset codes are short so they can be typed, which is why they
+	// expire quickly and only allow a few attempts
+	passwordResetCodeLength   = 6
+	passwordResetCodeLifetime = 15 * time.Minute
+	passwordResetMaxAttempts  = 5
+	passwordResetCooldown     = 2 * time.Minute
+
+	// The password reset codes are sent in the background, at most this
+	// many at once
+	maxPasswordResetsInProgress = 10
+
+	emailTokenLifetime = 48 * time.Hour
+	emailTokenCooldown = 2 * time.Minute
+)
 
 type Config struct {
 	*config.Config
+
+	passwordResets chan struct{} // the password resets in progress
 }
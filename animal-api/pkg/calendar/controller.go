@@ -0,0 +1,121 @@
+package calendar
+
+import (
+	"net/http"
+	"time"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/helper"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// The feeds keep the past visits this long
+const feedHistory = 30 * 24 * time.Hour
+
+// GetFeed godoc
+// @Summary Get a calendar feed
+// @Description Get the visits of the user as an iCalendar file to subscribe to: the visits assigned to the veterinarians, and the visits of their animals for the clients. The feed has the visits of the last 30 days onwards, the cancelled ones included so the calendar applications remove them. No authentication is needed, the token identifies the user.
+// @ID get-calendar-feed
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token"
+// @Success 200 {string} string "ok"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /calendar/feeds/{token}.ics [get]
+func (config *Config) GetFeed(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	dbUser, err := config.UserRepository.FindByCalendarToken(helper.HashToken(token))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if dbUser == nil || dbUser.SuspendedAt != nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return
+	}
+
+	from := time.Now().Add(-feedHistory)
+	filter := &dbmodel.VisitsFilter{From: &from}
+
+	if dbUser.RoleID == model.RoleClient {
+		filter.OwnerID = &dbUser.ID
+	} else {
+		filter.VetID = &dbUser.ID
+	}
+
+	dbVisits, err := config.VisitsRepository.FindAll(filter, &dbmodel.VisitsFieldsToInclude{
+		Animal: true,
+	})
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	Write(w, config.Config, "Visits", dbVisits)
+}
+
+// CreateFeed godoc
+// @Summary Create a calendar feed
+// @Description Create the calendar feed of the current user, the URL is only returned here. The URL of the previous feed stops working.
+// @ID create-calendar-feed
+// @Tags calendar
+// @Success 201 {object} CalendarFeed "created"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /calendar/feed [post]
+func (config *Config) CreateFeed(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	token, err := helper.RandomToken(32)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	tokenHash := helper.HashToken(token)
+	loggedUser.CalendarToken = &tokenHash
+
+	if _, err := config.UserRepository.Update(loggedUser); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, &model.CalendarFeed{
+		URL: model.CalendarFeedURL(token),
+	})
+}
+
+// DeleteFeed godoc
+// @Summary Delete the calendar feed
+// @Description Delete the calendar feed of the current user, its URL stops working.
+// @ID delete-calendar-feed
+// @Tags calendar
+// @Success 204 "no content"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /calendar/feed [delete]
+func (config *Config) DeleteFeed(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	loggedUser.CalendarToken = nil
+
+	if _, err := config.UserRepository.Update(loggedUser); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
@@ -0,0 +1,93 @@
+package calendar
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"feldrise.com/animal-api/config"
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/ical"
+	"feldrise.com/animal-api/pkg/model"
+)
+
+const prodID = "-//Feldrise//Animal API//EN"
+
+// Accepts returns whether the client asked for an iCalendar file
+func Accepts(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/calendar")
+}
+
+// Write renders the visits as an iCalendar file, in the time zone of the
+// clinic. The animals of the visits must be preloaded.
+func Write(w http.ResponseWriter, configuration *config.Config, name string, visits []*dbmodel.Visit) {
+	calendar := &ical.Calendar{
+		ProdID:   prodID,
+		Name:     name,
+		Location: configuration.Location,
+		Events:   make([]ical.Event, 0, len(visits)),
+	}
+
+	domain := uidDomain(configuration)
+
+	for _, visit := range visits {
+		calendar.Events = append(calendar.Events, visitEvent(visit, domain))
+	}
+
+	w.Header().Set("Content-Type", ical.ContentType)
+
+	// The headers are already sent, the error can only be logged
+	if err := calendar.Encode(w); err != nil {
+		log.Printf("Error writing the calendar: %s", err)
+	}
+}
+
+// Private
+
+// visitEvent returns the event of the visit, its UID never changes so the
+// calendar applications update it when the visit is rescheduled or cancelled
+func visitEvent(visit *dbmodel.Visit, domain string) ical.Event {
+	summary := visit.Reason
+
+	if visit.Animal.ID != 0 {
+		summary = visit.Animal.Name
+		if visit.Reason != "" {
+			summary += " - " + visit.Reason
+		}
+	}
+
+	status := ical.StatusConfirmed
+
+	switch visit.Status {
+	case model.VisitStatusRequested:
+		status = ical.StatusTentative
+	case model.VisitStatusCancelled:
+		status = ical.StatusCancelled
+	}
+
+	return ical.Event{
+		UID:          fmt.Sprintf("visit-%d@%s", visit.ID, domain),
+		Sequence:     visit.Sequence,
+		Created:      visit.CreatedAt,
+		LastModified: visit.UpdatedAt,
+		Start:        visit.Date,
+		End:          visit.Date.Add(time.Duration(visit.Duration) * time.Minute),
+		Summary:      summary,
+		Status:       status,
+	}
+}
+
+// uidDomain returns the host of the API, which makes the UIDs of the events
+// globally unique
+func uidDomain(configuration *config.Config) string {
+	baseURL, err := url.Parse(configuration.Constants.BaseURL)
+
+	if err != nil || baseURL.Hostname() == "" {
+		return "animal-api"
+	}
+
+	return baseURL.Hostname()
+}
@@ -0,0 +1,25 @@
+package calendar
+
+import (
+	"feldrise.com/animal-api/config"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+)
+
+func New(configuration *config.Config) *Config {
+	return &Config{configuration}
+}
+
+func (config *Config) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	// Public, the token of the feed identifies the user
+	router.Get("/feeds/{token}.ics", config.GetFeed)
+
+	// Logged users
+	router.With(authentication.RequireRole(model.AllRoles...)).Post("/feed", config.CreateFeed)
+	router.With(authentication.RequireRole(model.AllRoles...)).Delete("/feed", config.DeleteFeed)
+
+	return router
+}
@@ -0,0 +1,7 @@
+package calendar
+
+import "feldrise.com/animal-api/config"
+
+type Config struct {
+	*config.Config
+}
@@ -2,6 +2,7 @@ package errors
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/render"
 )
@@ -10,9 +11,10 @@ type ErrResponse struct {
 	Err            error `json:"-"` // low-level runtime error
 	HTTPStatusCode int   `json:"-"` // http response status code
 
-	StatusText string `json:"status"`          // user-level status message
-	AppCode    int64  `json:"code,omitempty"`  // application-specific error code
-	ErrorText  string `json:"error,omitempty"` // application-level error message, for debugging
+	StatusText string   `json:"status"`           // user-level status message
+	AppCode    int64    `json:"code,omitempty"`   // application-specific error code
+	ErrorText  string   `json:"error,omitempty"`  // application-level error message, for debugging
+	Fields     []string `json:"fields,omitempty"` // request fields causing the error
 } // @name ErrResponse
 
 func (e *ErrResponse) Render(w http.ResponseWriter, r *http.Request) error {
@@ -30,6 +32,15 @@ func ErrInvalidRequest(err error) render.Renderer {
 	}
 }
 
+func ErrForbiddenFields(fields []string) render.Renderer {
+	return &ErrResponse{
+		HTTPStatusCode: 400,
+		StatusText:     "invalid request",
+		ErrorText:      "fields not allowed: " + strings.Join(fields, ", "),
+		Fields:         fields,
+	}
+}
+
 func ErrServerError(err error) render.Renderer {
 	return &ErrResponse{
 		Err:            err,
@@ -54,3 +65,43 @@ func ErrUnauthorized(message string) render.Renderer {
 		ErrorText:      message,
 	}
 }
+
+func ErrForbidden(message string) render.Renderer {
+	return &ErrResponse{
+		HTTPStatusCode: 403,
+		StatusText:     message,
+		ErrorText:      message,
+	}
+}
+
+func ErrTooManyRequests(message string) render.Renderer {
+	return &ErrResponse{
+		HTTPStatusCode: 429,
+		StatusText:     message,
+		ErrorText:      message,
+	}
+}
+
+func ErrConflict(message string) render.Renderer {
+	return &ErrResponse{
+		HTTPStatusCode: 409,
+		StatusText:     message,
+		ErrorText:      message,
+	}
+}
+
+func ErrRequestTooLarge(message string) render.Renderer {
+	return &ErrResponse{
+		HTTPStatusCode: 413,
+		StatusText:     message,
+		ErrorText:      message,
+	}
+}
+
+func ErrUnsupportedMediaType(message string) render.Renderer {
+	return &ErrResponse{
+		HTTPStatusCode: 415,
+		StatusText:     message,
+		ErrorText:      message,
+	}
+}
@@ -0,0 +1,244 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of the iCalendar files
+const ContentType = "text/calendar; charset=utf-8"
+
+// The statuses of an event
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+const (
+	dateTimeFormat = "20060102T150405"
+
+	// The lines longer than this are folded, in octets
+	maxLineLength = 75
+)
+
+// Calendar is an iCalendar object (RFC 5545), its events are written in the
+// time zone of Location with the matching VTIMEZONE
+type Calendar struct {
+	ProdID   string
+	Name     string
+	Location *time.Location
+	Events   []Event
+}
+
+// Event is a VEVENT, the calendar applications recognize the updates of an
+// event by its UID and take the one with the highest Sequence
+type Event struct {
+	UID          string
+	Sequence     int
+	Created      time.Time
+	LastModified time.Time
+	Start        time.Time
+	End          time.Time
+	Summary      string
+	Description  string
+	Status       string
+}
+
+// Encode writes the calendar with CRLF line endings and folded lines
+func (c *Calendar) Encode(w io.Writer) error {
+	e := &encoder{writer: bufio.NewWriter(w)}
+
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + c.ProdID)
+	e.line("CALSCALE:GREGORIAN")
+
+	if c.Name != "" {
+		e.line("X-WR-CALNAME:" + escape(c.Name))
+	}
+
+	if !c.utc() {
+		e.line("X-WR-TIMEZONE:" + c.Location.String())
+		c.encodeTimezone(e)
+	}
+
+	for _, event := range c.Events {
+		e.line("BEGIN:VEVENT")
+		e.line("UID:" + escape(event.UID))
+		e.line(fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		e.line("DTSTAMP:" + utcDateTime(event.LastModified))
+		e.line("CREATED:" + utcDateTime(event.Created))
+		e.line("LAST-MODIFIED:" + utcDateTime(event.LastModified))
+		e.line("DTSTART" + c.dateTime(event.Start))
+		e.line("DTEND" + c.dateTime(event.End))
+		e.line("SUMMARY:" + escape(event.Summary))
+
+		if event.Description != "" {
+			e.line("DESCRIPTION:" + escape(event.Description))
+		}
+
+		if event.Status != "" {
+			e.line("STATUS:" + event.Status)
+		}
+
+		e.line("END:VEVENT")
+	}
+
+	e.line("END:VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+
+	return e.writer.Flush()
+}
+
+// Private
+
+func (c *Calendar) utc() bool {
+	return c.Location == nil || c.Location == time.UTC || c.Location.String() == "UTC"
+}
+
+// dateTime returns the parameters and the value of a date property
+func (c *Calendar) dateTime(date time.Time) string {
+	if c.utc() {
+		return ":" + utcDateTime(date)
+	}
+
+	return ";TZID=" + c.Location.String() + ":" + date.In(c.Location).Format(dateTimeFormat)
+}
+
+// encodeTimezone writes the VTIMEZONE of the location, with the observance in
+// effect when the first event starts and the changes of offset until the
+// last one ends
+func (c *Calendar) encodeTimezone(e *encoder) {
+	if len(c.Events) == 0 {
+		return
+	}
+
+	from, to := c.Events[0].Start, c.Events[0].End
+
+	for _, event := range c.Events {
+		if event.Start.Before(from) {
+			from = event.Start
+		}
+
+		if event.End.After(to) {
+			to = event.End
+		}
+	}
+
+	e.line("BEGIN:VTIMEZONE")
+	e.line("TZID:" + c.Location.String())
+
+	current := from.In(c.Location)
+	start, end := current.ZoneBounds()
+	_, offset := current.Zone()
+
+	// The zone may have started before the dates time can represent
+	offsetFrom := offset
+	onset := time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if !start.IsZero() && start.Year() > 1970 {
+		_, offsetFrom = start.Add(-time.Second).In(c.Location).Zone()
+		onset = start.In(time.FixedZone("", offsetFrom))
+	}
+
+	encodeObservance(e, current, offsetFrom, onset)
+
+	for !end.IsZero() && end.Before(to) {
+		previousOffset := offset
+		current = end.In(c.Location)
+		_, offset = current.Zone()
+
+		// The onset is given in the local time before the change
+		encodeObservance(e, current, previousOffset, end.In(time.FixedZone("", previousOffset)))
+
+		_, end = current.ZoneBounds()
+	}
+
+	e.line("END:VTIMEZONE")
+}
+
+func encodeObservance(e *encoder, date time.Time, offsetFrom int, onset time.Time) {
+	name, offset := date.Zone()
+
+	kind := "STANDARD"
+	if date.IsDST() {
+		kind = "DAYLIGHT"
+	}
+
+	e.line("BEGIN:" + kind)
+	e.line("DTSTART:" + onset.Format(dateTimeFormat))
+	e.line("TZOFFSETFROM:" + formatOffset(offsetFrom))
+	e.line("TZOFFSETTO:" + formatOffset(offset))
+
+	if name != "" {
+		e.line("TZNAME:" + escape(name))
+	}
+
+	e.line("END:" + kind)
+}
+
+func formatOffset(offset int) string {
+	sign := '+'
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+
+	return fmt.Sprintf("%c%02d%02d", sign, offset/3600, offset%3600/60)
+}
+
+func utcDateTime(date time.Time) string {
+	return date.UTC().Format(dateTimeFormat) + "Z"
+}
+
+// escape escapes the special characters of a TEXT value
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(text)
+}
+
+type encoder struct {
+	writer *bufio.Writer
+	err    error
+}
+
+// line writes a content line, folded without splitting the UTF-8 characters
+func (e *encoder) line(line string) {
+	if e.err != nil {
+		return
+	}
+
+	limit := maxLineLength
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		if _, e.err = e.writer.WriteString(line[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+
+		line = line[cut:]
+
+		// The leading space of the continuation lines counts
+		limit = maxLineLength - 1
+	}
+
+	_, e.err = e.writer.WriteString(line + "\r\n")
+}
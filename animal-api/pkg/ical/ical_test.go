@@ -0,0 +1,284 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLineFolding(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+	}{
+		{"short", "SUMMARY:Vaccination", []string{"SUMMARY:Vaccination"}},
+		{"exactly the limit", strings.Repeat("a", 75), []string{strings.Repeat("a", 75)}},
+		{"one octet over", strings.Repeat("a", 76), []string{strings.Repeat("a", 75), " a"}},
+		{
+			name: "several continuations",
+			line: strings.Repeat("a", 75+74+10),
+			want: []string{strings.Repeat("a", 75), " " + strings.Repeat("a", 74), " " + strings.Repeat("a", 10)},
+		},
+		{
+			name: "a character is not split",
+			line: strings.Repeat("a", 74) + "éé",
+			want: []string{strings.Repeat("a", 74), " éé"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			e := &encoder{writer: bufio.NewWriter(&buffer)}
+
+			e.line(test.line)
+			if err := e.writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			want := strings.Join(test.want, "\r\n") + "\r\n"
+			if got := buffer.String(); got != want {
+				t.Errorf("line() = %q, want %q", got, want)
+			}
+
+			// Unfolding gives the line back
+			if got := strings.ReplaceAll(strings.TrimSuffix(buffer.String(), "\r\n"), "\r\n ", ""); got != test.line {
+				t.Errorf("unfolded line = %q, want %q", got, test.line)
+			}
+		})
+	}
+}
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Vaccination", "Vaccination"},
+		{"Rex; Max, Felix", `Rex\; Max\, Felix`},
+		{`C:\notes`, `C:\\notes`},
+		{"first\r\nsecond\nthird\rfourth", `first\nsecond\nthird\nfourth`},
+	}
+
+	for _, test := range tests {
+		if got := escape(test.text); got != test.want {
+			t.Errorf("escape(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestEncodeTimezone(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := func(start time.Time) Event {
+		return Event{UID: "1@example.com", Start: start, End: start.Add(30 * time.Minute), Summary: "Check"}
+	}
+
+	tests := []struct {
+		name   string
+		events []Event
+		want   []string
+	}{
+		{
+			name:   "standard time only",
+			events: []Event{event(time.Date(2026, 1, 5, 9, 0, 0, 0, paris))},
+			want: []string{
+				"BEGIN:VTIMEZONE",
+				"TZID:Europe/Paris",
+				"BEGIN:STANDARD",
+				"DTSTART:20251026T030000",
+				"TZOFFSETFROM:+0200",
+				"TZOFFSETTO:+0100",
+				"TZNAME:CET",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+			},
+		},
+		{
+			name: "across the change to daylight saving time",
+			events: []Event{
+				event(time.Date(2026, 4, 6, 9, 0, 0, 0, paris)),
+				event(time.Date(2026, 3, 23, 9, 0, 0, 0, paris)),
+			},
+			want: []string{
+				"BEGIN:VTIMEZONE",
+				"TZID:Europe/Paris",
+				"BEGIN:STANDARD",
+				"DTSTART:20251026T030000",
+				"TZOFFSETFROM:+0200",
+				"TZOFFSETTO:+0100",
+				"TZNAME:CET",
+				"END:STANDARD",
+				"BEGIN:DAYLIGHT",
+				"DTSTART:20260329T020000",
+				"TZOFFSETFROM:+0100",
+				"TZOFFSETTO:+0200",
+				"TZNAME:CEST",
+				"END:DAYLIGHT",
+				"END:VTIMEZONE",
+			},
+		},
+		{
+			name:   "daylight saving time until the change back",
+			events: []Event{event(time.Date(2026, 10, 24, 9, 0, 0, 0, paris)), event(time.Date(2026, 10, 26, 9, 0, 0, 0, paris))},
+			want: []string{
+				"BEGIN:VTIMEZONE",
+				"TZID:Europe/Paris",
+				"BEGIN:DAYLIGHT",
+				"DTSTART:20260329T020000",
+				"TZOFFSETFROM:+0100",
+				"TZOFFSETTO:+0200",
+				"TZNAME:CEST",
+				"END:DAYLIGHT",
+				"BEGIN:STANDARD",
+				"DTSTART:20261025T030000",
+				"TZOFFSETFROM:+0200",
+				"TZOFFSETTO:+0100",
+				"TZNAME:CET",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+			},
+		},
+		{
+			name:   "a zone without changes",
+			events: []Event{event(time.Date(2026, 1, 5, 9, 0, 0, 0, time.FixedZone("Clinic", 3600)))},
+			want: []string{
+				"BEGIN:VTIMEZONE",
+				"TZID:Clinic",
+				"BEGIN:STANDARD",
+				"DTSTART:19700101T000000",
+				"TZOFFSETFROM:+0100",
+				"TZOFFSETTO:+0100",
+				"TZNAME:Clinic",
+				"END:STANDARD",
+				"END:VTIMEZONE",
+			},
+		},
+		{
+			name:   "no events",
+			events: nil,
+			want:   nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			location := paris
+			if len(test.events) > 0 {
+				location = test.events[0].Start.Location()
+			}
+
+			var buffer bytes.Buffer
+			e := &encoder{writer: bufio.NewWriter(&buffer)}
+
+			calendar := &Calendar{Location: location, Events: test.events}
+			calendar.encodeTimezone(e)
+
+			if err := e.writer.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			if buffer.Len() > 0 {
+				got = strings.Split(strings.TrimSuffix(buffer.String(), "\r\n"), "\r\n")
+			}
+
+			if strings.Join(got, "\n") != strings.Join(test.want, "\n") {
+				t.Errorf("encodeTimezone() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	start := time.Date(2026, 1, 5, 9, 0, 0, 0, paris)
+
+	event := Event{
+		UID:          "visit-1@example.com",
+		Sequence:     2,
+		Created:      created,
+		LastModified: created.Add(time.Hour),
+		Start:        start,
+		End:          start.Add(30 * time.Minute),
+		Summary:      "Rex, vaccination",
+		Status:       StatusConfirmed,
+	}
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		want     []string
+		absent   []string
+	}{
+		{
+			name:     "in the location",
+			location: paris,
+			want: []string{
+				"X-WR-TIMEZONE:Europe/Paris",
+				"DTSTART;TZID=Europe/Paris:20260105T090000",
+				"DTEND;TZID=Europe/Paris:20260105T093000",
+			},
+		},
+		{
+			name:     "in UTC",
+			location: time.UTC,
+			want: []string{
+				"DTSTART:20260105T080000Z",
+				"DTEND:20260105T083000Z",
+			},
+			absent: []string{"X-WR-TIMEZONE", "BEGIN:VTIMEZONE"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+
+			calendar := &Calendar{ProdID: "-//Clinic//Visits//EN", Name: "Visits", Location: test.location, Events: []Event{event}}
+			if err := calendar.Encode(&buffer); err != nil {
+				t.Fatal(err)
+			}
+
+			got := buffer.String()
+			lines := strings.Split(strings.TrimSuffix(got, "\r\n"), "\r\n")
+
+			if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" {
+				t.Errorf("Encode() isn't a VCALENDAR:\n%s", got)
+			}
+
+			want := append([]string{
+				"UID:visit-1@example.com",
+				"SEQUENCE:2",
+				"DTSTAMP:20260102T110000Z",
+				"CREATED:20260102T100000Z",
+				"LAST-MODIFIED:20260102T110000Z",
+				`SUMMARY:Rex\, vaccination`,
+				"STATUS:CONFIRMED",
+			}, test.want...)
+
+			for _, line := range want {
+				if !strings.Contains(got, "\r\n"+line+"\r\n") {
+					t.Errorf("Encode() is missing the line %q:\n%s", line, got)
+				}
+			}
+
+			for _, text := range test.absent {
+				if strings.Contains(got, text) {
+					t.Errorf("Encode() contains %q:\n%s", text, got)
+				}
+			}
+		})
+	}
+}
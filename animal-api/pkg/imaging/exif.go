@@ -0,0 +1,148 @@
+package imaging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io"
+)
+
+const orientationTag = 0x0112
+
+// jpegOrientation reads the EXIF orientation of a JPEG file, from 1 to 8.
+// Phones store the picture as the sensor captured it and only record the
+// rotation to apply, it is lost once the metadata are removed.
+func jpegOrientation(file io.Reader) int {
+	reader := bufio.NewReader(file)
+
+	// Skip the SOI marker then go through the segments until the image data
+	if _, err := reader.Discard(2); err != nil {
+		return 1
+	}
+
+	header := make([]byte, 4)
+
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil || header[0] != 0xFF {
+			return 1
+		}
+
+		marker := header[1]
+		length := int(binary.BigEndian.Uint16(header[2:]))
+
+		// Start of scan, the metadata are before it
+		if marker == 0xDA || length < 2 {
+			return 1
+		}
+
+		// Only the EXIF segment is read, the others are skipped
+		if marker != 0xE1 {
+			if _, err := reader.Discard(length - 2); err != nil {
+				return 1
+			}
+
+			continue
+		}
+
+		segment := make([]byte, length-2)
+
+		if _, err := io.ReadFull(reader, segment); err != nil {
+			return 1
+		}
+
+		if bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+	}
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+
+	entries := int(order.Uint16(tiff[ifd:]))
+
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+
+		if order.Uint16(tiff[entry:]) == orientationTag {
+			orientation := int(order.Uint16(tiff[entry+8:]))
+
+			if orientation < 1 || orientation > 8 {
+				return 1
+			}
+
+			return orientation
+		}
+	}
+
+	return 1
+}
+
+// orient rotates and flips the image as described by its EXIF orientation
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	if orientation <= 1 || orientation > 8 {
+		return src
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// Orientations 5 to 8 swap the width and the height
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+
+			switch orientation {
+			case 2: // flipped horizontally
+				dx, dy = width-1-x, y
+			case 3: // rotated 180°
+				dx, dy = width-1-x, height-1-y
+			case 4: // flipped vertically
+				dx, dy = x, height-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // rotated 90° clockwise
+				dx, dy = height-1-y, x
+			case 7: // transversed
+				dx, dy = height-1-y, width-1-x
+			case 8: // rotated 90° counterclockwise
+				dx, dy = y, width-1-x
+			}
+
+			from := src.PixOffset(bounds.Min.X+x, bounds.Min.Y+y)
+			to := dst.PixOffset(dx, dy)
+			copy(dst.Pix[to:to+4], src.Pix[from:from+4])
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,89 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const heicConversionTimeout = 30 * time.Second
+
+var ErrHEICUnsupported = errors.New("HEIC images are not supported by this server, JPEG or PNG expected")
+
+// The brands of the ISO base media files holding HEIF images
+var heicBrands = []string{"heic", "heix", "hevc", "hevx", "heim", "heis", "mif1", "msf1"}
+
+func isHEIC(data []byte) bool {
+	if len(data) < 12 || string(data[4:8]) != "ftyp" {
+		return false
+	}
+
+	brand := string(data[8:12])
+
+	for _, heicBrand := range heicBrands {
+		if brand == heicBrand {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ConvertHEIC converts a HEIC image to JPEG with an external command, called
+// with the input and output paths, like heif-convert from libheif. The
+// command applies the HEIC rotation itself.
+func ConvertHEIC(converter string, file io.Reader) ([]byte, error) {
+	command := strings.Fields(converter)
+
+	if len(command) == 0 {
+		return nil, ErrHEICUnsupported
+	}
+
+	directory, err := os.MkdirTemp("", "heic")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(directory)
+
+	input := filepath.Join(directory, "input.heic")
+	output := filepath.Join(directory, "output.jpg")
+
+	if err := writeFile(input, file); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), heicConversionTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, command[0], append(command[1:], input, output)...)
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("HEIC conversion failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return os.ReadFile(output)
+}
+
+func writeFile(name string, content io.Reader) error {
+	file, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(file, content); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
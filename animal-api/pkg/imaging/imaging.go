@@ -0,0 +1,211 @@
+package imaging
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+const (
+	FormatJPEG = "jpeg"
+	FormatPNG  = "png"
+	FormatHEIC = "heic"
+
+	// The number of first bytes Format needs
+	FormatLength = 12
+
+	// Decoding a picture takes 4 bytes per pixel, this keeps it under 160 MB
+	maxPixels = 40_000_000
+
+	jpegQuality = 85
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported image format, JPEG, PNG or HEIC expected")
+	ErrTooManyPixels     = errors.New("image dimensions too large")
+)
+
+// Format returns the format of the image from its first bytes, whatever the
+// file name or the content type given by the client
+func Format(data []byte) string {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8, 0xFF}):
+		return FormatJPEG
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return FormatPNG
+	case isHEIC(data):
+		return FormatHEIC
+	}
+
+	return ""
+}
+
+// Decode decodes a JPEG or PNG image and applies its EXIF orientation, the
+// metadata themselves are dropped. The file is read several times from its
+// start, but never held in memory.
+func Decode(file io.ReadSeeker) (image.Image, error) {
+	start := make([]byte, FormatLength)
+
+	n, err := io.ReadFull(file, start)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+
+	format := Format(start[:n])
+
+	if format != FormatJPEG && format != FormatPNG {
+		return nil, ErrUnsupportedFormat
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	config, _, err := image.DecodeConfig(bufio.NewReader(file))
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var img image.Image
+
+	if format == FormatJPEG {
+		img, err = jpeg.Decode(bufio.NewReader(file))
+	} else {
+		img, err = png.Decode(bufio.NewReader(file))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if format == FormatJPEG {
+		if _, err = file.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		img = orient(toRGBA(img), jpegOrientation(file))
+	}
+
+	return img, nil
+}
+
+// Fit scales the image down so it fits in a size x size square, smaller
+// images are kept as they are
+func Fit(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if width <= size && height <= size {
+		return img
+	}
+
+	if width >= height {
+		return resize(toRGBA(img), size, max(1, height*size/width))
+	}
+
+	return resize(toRGBA(img), max(1, width*size/height), size)
+}
+
+// Square crops the center of the image and scales it down to a size x size
+// square
+func Square(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+
+	x := bounds.Min.X + (bounds.Dx()-side)/2
+	y := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	cropped := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(cropped, cropped.Bounds(), img, image.Pt(x, y), draw.Src)
+
+	if side <= size {
+		return cropped
+	}
+
+	return resize(cropped, size, size)
+}
+
+// EncodeJPEG encodes the image without any metadata, the transparent parts
+// become white
+func EncodeJPEG(img image.Image) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	if err := jpeg.Encode(&buffer, toRGBA(img), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Private
+
+// toRGBA draws the image over a white background, it is returned as is when
+// already opaque RGBA
+func toRGBA(img image.Image) *image.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok && rgba.Opaque() {
+		return rgba
+	}
+
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+	draw.Draw(rgba, rgba.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Over)
+
+	return rgba
+}
+
+// resize scales the image down by averaging the source pixels covered by
+// each destination pixel
+func resize(src *image.RGBA, width int, height int) *image.RGBA {
+	bounds := src.Bounds()
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := y * srcHeight / height
+		y1 := max(y0+1, (y+1)*srcHeight/height)
+
+		for x := 0; x < width; x++ {
+			x0 := x * srcWidth / width
+			x1 := max(x0+1, (x+1)*srcWidth/width)
+
+			var r, g, b, a, count uint32
+
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(bounds.Min.X+x0, bounds.Min.Y+sy)
+
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(src.Pix[offset])
+					g += uint32(src.Pix[offset+1])
+					b += uint32(src.Pix[offset+2])
+					a += uint32(src.Pix[offset+3])
+					count++
+					offset += 4
+				}
+			}
+
+			offset := dst.PixOffset(x, y)
+			dst.Pix[offset] = uint8(r / count)
+			dst.Pix[offset+1] = uint8(g / count)
+			dst.Pix[offset+2] = uint8(b / count)
+			dst.Pix[offset+3] = uint8(a / count)
+		}
+	}
+
+	return dst
+}
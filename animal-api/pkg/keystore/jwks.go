@@ -0,0 +1,43 @@
+package keystore
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+
+	"feldrise.com/animal-api/pkg/model"
+)
+
+// JWKS returns the public part of the keys, so other services can verify
+// the tokens without sharing any secret
+func (store *KeyStore) JWKS() *model.JSONWebKeySet {
+	set := &model.JSONWebKeySet{
+		Keys: []model.JSONWebKey{},
+	}
+
+	for _, key := range store.Keys() {
+		jwk := model.JSONWebKey{
+			KeyID:     key.ID,
+			Use:       "sig",
+			Algorithm: key.Algorithm,
+		}
+
+		switch publicKey := key.PublicKey().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.Modulus = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.Exponent = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		default:
+			continue
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set
+}
@@ -0,0 +1,362 @@
+package keystore
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"feldrise.com/animal-api/helper"
+)
+
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmEdDSA = "EdDSA"
+
+	manifestFile = "keys.json"
+
+	// How often the manifest is checked for a rotation made by another
+	// process
+	reloadInterval = 10 * time.Second
+
+	// How often an unknown key id forces the check, as the key may come from
+	// a rotation made by another process since the last one
+	missReloadInterval = time.Second
+)
+
+var ErrUnknownAlgorithm = errors.New("unknown signing algorithm")
+
+type Key struct {
+	ID        string     `json:"kid"`
+	Algorithm string     `json:"alg"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at"`
+
+	PrivateKey crypto.Signer `json:"-"`
+}
+
+func (key *Key) PublicKey() crypto.PublicKey {
+	return key.PrivateKey.Public()
+}
+
+// KeyStore holds the keys used to sign the tokens. Each key is stored as a
+// PEM file next to a manifest listing them, the first key of the manifest
+// signs the new tokens while the retired ones are kept to verify the tokens
+// signed before a rotation, until their grace period is over.
+type KeyStore struct {
+	path        string
+	algorithm   string
+	gracePeriod time.Duration
+
+	mutex       sync.RWMutex
+	keys        []*Key
+	loadedAt    time.Time // modification time of the loaded manifest
+	lastCheckAt time.Time
+}
+
+func Open(path string, algorithm string, gracePeriod time.Duration) (*KeyStore, error) {
+	if algorithm != AlgorithmRS256 && algorithm != AlgorithmEdDSA {
+		return nil, ErrUnknownAlgorithm
+	}
+
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		return nil, err
+	}
+
+	store := &KeyStore{
+		path:        path,
+		algorithm:   algorithm,
+		gracePeriod: gracePeriod,
+	}
+
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	if len(store.keys) == 0 {
+		if _, err := store.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
+
+// SigningKey returns the key to sign new tokens with
+func (store *KeyStore) SigningKey() *Key {
+	store.reloadIfChanged(reloadInterval)
+
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	return store.keys[0]
+}
+
+// VerificationKey returns the key with the given id if it can still be used
+// to verify tokens
+func (store *KeyStore) VerificationKey(id string) *Key {
+	store.reloadIfChanged(reloadInterval)
+
+	if key := store.findValidKey(id); key != nil {
+		return key
+	}
+
+	store.reloadIfChanged(missReloadInterval)
+
+	return store.findValidKey(id)
+}
+
+// Keys returns the keys that can still be used to verify tokens
+func (store *KeyStore) Keys() []*Key {
+	store.reloadIfChanged(reloadInterval)
+
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	keys := make([]*Key, 0, len(store.keys))
+	for _, key := range store.keys {
+		if store.isValid(key) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// Rotate generates a new signing key and retires the current one. The keys
+// whose grace period is over are deleted.
+func (store *KeyStore) Rotate() (*Key, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	// Another process may have rotated the keys since our last load
+	if err := store.loadLocked(); err != nil {
+		return nil, err
+	}
+
+	key, err := generateKey(store.algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writePrivateKey(store.keyPath(key.ID), key.PrivateKey); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	keys := []*Key{key}
+	var expired []*Key
+
+	for _, previous := range store.keys {
+		if previous.RetiredAt == nil {
+			previous.RetiredAt = &now
+		}
+
+		if store.isValid(previous) {
+			keys = append(keys, previous)
+		} else {
+			expired = append(expired, previous)
+		}
+	}
+
+	if err := store.writeManifest(keys); err != nil {
+		return nil, err
+	}
+
+	for _, previous := range expired {
+		os.Remove(store.keyPath(previous.ID))
+	}
+
+	store.keys = keys
+
+	return key, nil
+}
+
+// Private
+
+func (store *KeyStore) isValid(key *Key) bool {
+	return key.RetiredAt == nil || time.Since(*key.RetiredAt) < store.gracePeriod
+}
+
+func (store *KeyStore) findValidKey(id string) *Key {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	for _, key := range store.keys {
+		if key.ID == id && store.isValid(key) {
+			return key
+		}
+	}
+
+	return nil
+}
+
+func (store *KeyStore) keyPath(id string) string {
+	return filepath.Join(store.path, id+".pem")
+}
+
+func (store *KeyStore) load() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return store.loadLocked()
+}
+
+func (store *KeyStore) loadLocked() error {
+	manifestPath := filepath.Join(store.path, manifestFile)
+
+	info, err := os.Stat(manifestPath)
+	if os.IsNotExist(err) {
+		store.keys = nil
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return err
+	}
+
+	var keys []*Key
+	if err := json.Unmarshal(content, &keys); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		key.PrivateKey, err = readPrivateKey(store.keyPath(key.ID))
+		if err != nil {
+			return fmt.Errorf("key %s: %w", key.ID, err)
+		}
+	}
+
+	store.keys = keys
+	store.loadedAt = info.ModTime()
+	store.lastCheckAt = time.Now()
+
+	return nil
+}
+
+// reloadIfChanged reloads the manifest if it changed, when it wasn't checked
+// for the interval
+func (store *KeyStore) reloadIfChanged(interval time.Duration) {
+	store.mutex.RLock()
+	check := time.Since(store.lastCheckAt) > interval
+	store.mutex.RUnlock()
+
+	if !check {
+		return
+	}
+
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.lastCheckAt = time.Now()
+
+	info, err := os.Stat(filepath.Join(store.path, manifestFile))
+	if err != nil || info.ModTime().Equal(store.loadedAt) {
+		return
+	}
+
+	// On failure we keep the keys we already have
+	keys := store.keys
+	if err := store.loadLocked(); err != nil || len(store.keys) == 0 {
+		store.keys = keys
+	}
+}
+
+func (store *KeyStore) writeManifest(keys []*Key) error {
+	content, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	manifestPath := filepath.Join(store.path, manifestFile)
+	temporaryPath := manifestPath + ".tmp"
+
+	if err := os.WriteFile(temporaryPath, content, 0o600); err != nil {
+		return err
+	}
+
+	if err := os.Rename(temporaryPath, manifestPath); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(manifestPath); err == nil {
+		store.loadedAt = info.ModTime()
+	}
+
+	return nil
+}
+
+func generateKey(algorithm string) (*Key, error) {
+	id, err := helper.RandomToken(12)
+	if err != nil {
+		return nil, err
+	}
+
+	var privateKey crypto.Signer
+
+	switch algorithm {
+	case AlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case AlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		err = ErrUnknownAlgorithm
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{
+		ID:         id,
+		Algorithm:  algorithm,
+		CreatedAt:  time.Now(),
+		PrivateKey: privateKey,
+	}, nil
+}
+
+func writePrivateKey(path string, privateKey crypto.Signer) error {
+	bytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: bytes}), 0o600)
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("invalid PEM file")
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key")
+	}
+
+	return signer, nil
+}
@@ -0,0 +1,54 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FileMailer writes every message as an .eml file in the outbox directory,
+// it is meant for development
+type FileMailer struct {
+	outboxPath string
+}
+
+func NewFileMailer(outboxPath string) (*FileMailer, error) {
+	if err := os.MkdirAll(outboxPath, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &FileMailer{
+		outboxPath: outboxPath,
+	}, nil
+}
+
+func (m *FileMailer) Send(message *Message) error {
+	message.SentAt = time.Now()
+
+	recipient := strings.NewReplacer("@", "_at_", "/", "_", "\\", "_").Replace(message.To)
+	fileName := fmt.Sprintf("%d-%s.eml", message.SentAt.UnixNano(), recipient)
+
+	return os.WriteFile(filepath.Join(m.outboxPath, fileName), []byte(format("", message)), 0o640)
+}
+
+func format(from string, message *Message) string {
+	var builder strings.Builder
+
+	// Header values must stay on a single line
+	header := strings.NewReplacer("\r", "", "\n", "")
+
+	if from != "" {
+		builder.WriteString("From: " + header.Replace(from) + "\r\n")
+	}
+	builder.WriteString("To: " + header.Replace(message.To) + "\r\n")
+	builder.WriteString("Subject: " + header.Replace(message.Subject) + "\r\n")
+	builder.WriteString("Date: " + message.SentAt.Format(time.RFC1123Z) + "\r\n")
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+
+	return builder.String()
+}
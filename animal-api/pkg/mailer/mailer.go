@@ -0,0 +1,55 @@
+package mailer
+
+import (
+	"fmt"
+	"time"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+
+	SentAt time.Time
+}
+
+// Mailer delivers messages to users. The implementation is selected with
+// the `mailer` configuration key.
+type Mailer interface {
+	Send(message *Message) error
+}
+
+type UnknownMailerError struct {
+	Name string
+}
+
+func (e *UnknownMailerError) Error() string {
+	return fmt.Sprintf("unknown mailer %q", e.Name)
+}
+
+type Options struct {
+	Name string
+	From string
+
+	// File outbox
+	OutboxPath string
+
+	// SMTP
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+}
+
+func New(options Options) (Mailer, error) {
+	switch options.Name {
+	case "", "memory":
+		return NewMemoryMailer(), nil
+	case "file":
+		return NewFileMailer(options.OutboxPath)
+	case "smtp":
+		return NewSMTPMailer(options.SMTPHost, options.SMTPPort, options.SMTPUsername, options.SMTPPassword, options.From), nil
+	}
+
+	return nil, &UnknownMailerError{Name: options.Name}
+}
@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// MemoryMailer keeps every message in memory, it is meant for development
+type MemoryMailer struct {
+	mutex    sync.Mutex
+	messages []Message
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(message *Message) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	message.SentAt = time.Now()
+	m.messages = append(m.messages, *message)
+
+	log.Printf("Mail to %s: %s\n%s", message.To, message.Subject, message.Body)
+
+	return nil
+}
+
+// Messages returns a copy of the outbox
+func (m *MemoryMailer) Messages() []Message {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	messages := make([]Message, len(m.messages))
+	copy(messages, m.messages)
+
+	return messages
+}
@@ -0,0 +1,217 @@
+package model
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	SexMale    = "male"
+	SexFemale  = "female"
+	SexUnknown = "unknown"
+)
+
+var (
+	// ISO 11784 identification codes are 15 digits, the first 3 being a
+	// country or a manufacturer code
+	microchipRegexp = regexp.MustCompile(`^[0-9]{15}$`)
+	tattooRegexp    = regexp.MustCompile(`^[A-Z0-9]{3,10}$`)
+)
+
+type Animal struct {
+	ID        uint    `json:"id"`                   // @id
+	CreatedAt string  `json:"created_at"`           // the animal's creation date
+	UpdatedAt string  `json:"updated_at"`           // the animal's last update date
+	DeletedAt *string `json:"deleted_at,omitempty"` // when the animal was moved to the trash
+	Links     Links   `json:"links"`                // the animal's resources
+
+	Name       string                 `json:"name"`
+	Species    string                 `json:"species"`         // the code of the animal's species
+	Attributes map[string]interface{} `json:"attributes"`      // the attributes specific to the species
+	OwnerID    *uint                  `json:"owner_id"`        // the user owning the animal
+	Owner      *User                  `json:"owner,omitempty"` // the user owning the animal, when requested
+
+	Breed      *string    `json:"breed"`       // the animal's breed
+	BirthDate  *time.Time `json:"birth_date"`  // the animal's date of birth
+	AgeYears   *int       `json:"age_years"`   // the animal's age, computed from its date of birth
+	AgeMonths  *int       `json:"age_months"`  // the months to add to age_years
+	Sex        string     `json:"sex"`         // male, female or unknown
+	Neutered   bool       `json:"neutered"`    // whether the animal is neutered
+	NeuteredAt *time.Time `json:"neutered_at"` // the date of the neutering
+	Color      *string    `json:"color"`       // the animal's coat color
+	Microchip  *string    `json:"microchip"`   // the ISO 11784 identification code
+	Tattoo     *string    `json:"tattoo"`      // the tattoo identifier
+
+	ProfilePhoto *Photo   `json:"profile_photo"`    // the animal's profile picture
+	Vitals       []*Vital `json:"vitals,omitempty"` // the animal's vitals, when requested
+} // @name Animal
+
+type AnimalCreatePayload struct {
+	Name       string                 `json:"name"`
+	Species    *string                `json:"species" example:"dog"` // the code of the species, implied by the /cat routes
+	Attributes map[string]interface{} `json:"attributes"`            // the attributes specific to the species
+	OwnerID    *uint                  `json:"owner_id" example:"3"`  // the user owning the animal, clients always own the animals they create
+
+	Breed      *string    `json:"breed" example:"Maine Coon"`
+	BirthDate  *time.Time `json:"birth_date" example:"2020-04-12T00:00:00Z"`
+	Sex        *string    `json:"sex" example:"female" enums:"male,female,unknown"`
+	Neutered   *bool      `json:"neutered" example:"true"`
+	NeuteredAt *time.Time `json:"neutered_at" example:"2021-01-08T00:00:00Z"`
+	Color      *string    `json:"color" example:"brown tabby"`
+	Microchip  *string    `json:"microchip" example:"250269604123456"`
+	Tattoo     *string    `json:"tattoo" example:"ABC123"`
+} // @name AnimalCreatePayload
+
+func (c *AnimalCreatePayload) Bind(r *http.Request) error {
+	if c.Name == "" {
+		return errors.New("missing name property")
+	}
+
+	return normalizeIdentity(c.BirthDate, c.Sex, c.Neutered, c.NeuteredAt, c.Microchip, c.Tattoo)
+}
+
+// StaffFields returns the fields given in the payload that only the staff
+// can set
+func (c *AnimalCreatePayload) StaffFields() []string {
+	var fields []string
+
+	if c.Neutered != nil {
+		fields = append(fields, "neutered")
+	}
+
+	if c.NeuteredAt != nil {
+		fields = append(fields, "neutered_at")
+	}
+
+	if c.Microchip != nil {
+		fields = append(fields, "microchip")
+	}
+
+	if c.Tattoo != nil {
+		fields = append(fields, "tattoo")
+	}
+
+	return fields
+}
+
+type AnimalUpdatePayload struct {
+	Name       *string                `json:"name" example:"Garfield"`
+	Attributes map[string]interface{} `json:"attributes"` // replaces all the attributes specific to the species
+	OwnerID    *uint                  `json:"owner_id" example:"3"`
+
+	Breed      *string    `json:"breed" example:"Maine Coon"`
+	BirthDate  *time.Time `json:"birth_date" example:"2020-04-12T00:00:00Z"`
+	Sex        *string    `json:"sex" example:"female" enums:"male,female,unknown"`
+	Neutered   *bool      `json:"neutered" example:"true"`
+	NeuteredAt *time.Time `json:"neutered_at" example:"2021-01-08T00:00:00Z"`
+	Color      *string    `json:"color" example:"brown tabby"`
+	Microchip  *string    `json:"microchip" example:"250269604123456"`
+	Tattoo     *string    `json:"tattoo" example:"ABC123"`
+} // @name AnimalUpdatePayload
+
+// AnimalUpdateFields lists the fields each role can change on an animal, the
+// identification and neutering are recorded by the staff
+var AnimalUpdateFields = map[uint][]string{
+	RoleAdmin:       {"name", "attributes", "owner_id", "breed", "birth_date", "sex", "neutered", "neutered_at", "color", "microchip", "tattoo"},
+	RoleVeterinaire: {"name", "attributes", "owner_id", "breed", "birth_date", "sex", "neutered", "neutered_at", "color", "microchip", "tattoo"},
+	RoleClient:      {"name", "attributes", "breed", "birth_date", "sex", "color"},
+}
+
+func (c *AnimalUpdatePayload) Validate() error {
+	if c.Name != nil && *c.Name == "" {
+		return errors.New("empty name property")
+	}
+
+	return normalizeIdentity(c.BirthDate, c.Sex, c.Neutered, c.NeuteredAt, c.Microchip, c.Tattoo)
+}
+
+// NormalizeMicrochip removes the separators people type in a microchip
+// number and checks it is an ISO 11784 code
+func NormalizeMicrochip(number string) (string, error) {
+	number = strings.NewReplacer(" ", "", "-", "", ".", "").Replace(number)
+
+	if !microchipRegexp.MatchString(number) {
+		return "", errors.New("invalid microchip property, 15 digits expected")
+	}
+
+	return number, nil
+}
+
+// NormalizeTattoo removes the spaces of a tattoo identifier and upper cases
+// it
+func NormalizeTattoo(identifier string) (string, error) {
+	identifier = strings.ToUpper(strings.ReplaceAll(identifier, " ", ""))
+
+	if !tattooRegexp.MatchString(identifier) {
+		return "", errors.New("invalid tattoo property, 3 to 10 letters or digits expected")
+	}
+
+	return identifier, nil
+}
+
+// Age returns the complete years and the remaining months elapsed between
+// the birth date and now
+func Age(birthDate time.Time, now time.Time) (int, int) {
+	months := (now.Year()-birthDate.Year())*12 + int(now.Month()) - int(birthDate.Month())
+
+	if now.Day() < birthDate.Day() {
+		months--
+	}
+
+	if months < 0 {
+		months = 0
+	}
+
+	return months / 12, months % 12
+}
+
+// normalizeIdentity validates the identity fields and normalizes the
+// identifiers in place
+func normalizeIdentity(birthDate *time.Time, sex *string, neutered *bool, neuteredAt *time.Time, microchip *string, tattoo *string) error {
+	now := time.Now()
+
+	if birthDate != nil && birthDate.After(now) {
+		return errors.New("invalid birth_date property, the date is in the future")
+	}
+
+	if sex != nil && *sex != SexMale && *sex != SexFemale && *sex != SexUnknown {
+		return errors.New("invalid sex property, male, female or unknown expected")
+	}
+
+	if neuteredAt != nil {
+		if neuteredAt.After(now) {
+			return errors.New("invalid neutered_at property, the date is in the future")
+		}
+
+		if birthDate != nil && neuteredAt.Before(*birthDate) {
+			return errors.New("invalid neutered_at property, the date is before birth_date")
+		}
+
+		if neutered != nil && !*neutered {
+			return errors.New("invalid neutered_at property, the animal is not neutered")
+		}
+	}
+
+	if microchip != nil {
+		number, err := NormalizeMicrochip(*microchip)
+		if err != nil {
+			return err
+		}
+
+		*microchip = number
+	}
+
+	if tattoo != nil {
+		identifier, err := NormalizeTattoo(*tattoo)
+		if err != nil {
+			return err
+		}
+
+		*tattoo = identifier
+	}
+
+	return nil
+}
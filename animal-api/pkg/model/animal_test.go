@@ -0,0 +1,105 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeMicrochip(t *testing.T) {
+	tests := []struct {
+		number  string
+		want    string
+		wantErr bool
+	}{
+		{number: "250269604123456", want: "250269604123456"},
+		{number: "250 269 604 123 456", want: "250269604123456"},
+		{number: "250-269-604-123.456", want: "250269604123456"},
+		{number: "25026960412345", wantErr: true},
+		{number: "2502696041234567", wantErr: true},
+		{number: "25026960412345A", wantErr: true},
+		{number: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.number, func(t *testing.T) {
+			got, err := NormalizeMicrochip(test.number)
+
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("NormalizeMicrochip() = %s, want an error", got)
+				}
+				return
+			}
+
+			if err != nil || got != test.want {
+				t.Errorf("NormalizeMicrochip() = %s, %v, want %s", got, err, test.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTattoo(t *testing.T) {
+	tests := []struct {
+		identifier string
+		want       string
+		wantErr    bool
+	}{
+		{identifier: "ABC123", want: "ABC123"},
+		{identifier: "abc 123", want: "ABC123"},
+		{identifier: "2AB", want: "2AB"},
+		{identifier: "AB", wantErr: true},
+		{identifier: "ABCDEF123456", wantErr: true},
+		{identifier: "AB-123", wantErr: true},
+		{identifier: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.identifier, func(t *testing.T) {
+			got, err := NormalizeTattoo(test.identifier)
+
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("NormalizeTattoo() = %s, want an error", got)
+				}
+				return
+			}
+
+			if err != nil || got != test.want {
+				t.Errorf("NormalizeTattoo() = %s, %v, want %s", got, err, test.want)
+			}
+		})
+	}
+}
+
+func TestAge(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		birthDate  time.Time
+		now        time.Time
+		wantYears  int
+		wantMonths int
+	}{
+		{"born today", date(2026, 3, 15), date(2026, 3, 15), 0, 0},
+		{"the day before a month", date(2026, 1, 15), date(2026, 2, 14), 0, 0},
+		{"one month", date(2026, 1, 15), date(2026, 2, 15), 0, 1},
+		{"the day before a birthday", date(2020, 3, 15), date(2026, 3, 14), 5, 11},
+		{"on a birthday", date(2020, 3, 15), date(2026, 3, 15), 6, 0},
+		{"years and months", date(2020, 4, 12), date(2026, 10, 18), 6, 6},
+		{"born on the 29th of February", date(2024, 2, 29), date(2025, 2, 28), 0, 11},
+		{"born in the future", date(2027, 1, 1), date(2026, 1, 1), 0, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			years, months := Age(test.birthDate, test.now)
+
+			if years != test.wantYears || months != test.wantMonths {
+				t.Errorf("Age() = %d years %d months, want %d years %d months", years, months, test.wantYears, test.wantMonths)
+			}
+		})
+	}
+}
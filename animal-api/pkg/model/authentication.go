@@ -6,10 +6,17 @@ import (
 )
 
 type User struct {
-	ID        uint   `json:"id"`         // @id
-	CreatedAt string `json:"created_at"` // the user's creation date
-
-	Email string `json:"email"` // the user's email
+	ID        uint    `json:"id"`                   // @id
+	CreatedAt string  `json:"created_at"`           // the user's creation date
+	UpdatedAt string  `json:"updated_at"`           // the user's last update date
+	DeletedAt *string `json:"deleted_at,omitempty"` // when the user was moved to the trash
+	Links     Links   `json:"links"`                // the user's resources
+
+	Email         string `json:"email"`          // the user's email
+	EmailVerified bool   `json:"email_verified"` // whether the user confirmed its email
+	RoleID        uint   `json:"role_id"`        // the user's role
+	MFAEnabled    bool   `json:"mfa_enabled"`    // whether the user enabled two-factor authentication
+	Suspended     bool   `json:"suspended"`      // whether an administrator suspended the account
 
 	Profile *UserProfile `json:"profile"` // the user's profile
 } // @name User
@@ -91,3 +98,76 @@ func (rp *ResetPasswordPostPayload) Bind(r *http.Request) error {
 
 	return nil
 }
+
+type VerifyEmailPostPayload struct {
+	Token *string `json:"token" validate:"required" example:"Jc2k8YbVxQ"` // the token received by email
+} // @name VerifyEmailPostPayload
+
+func (vp *VerifyEmailPostPayload) Bind(r *http.Request) error {
+	if vp.Token == nil {
+		return errors.New("missing token property")
+	}
+
+	return nil
+}
+
+type Tokens struct {
+	Token        string `json:"token"`         // the access token, to send in the Authorization header
+	ExpiresAt    int64  `json:"expires_at"`    // the access token's expiration timestamp
+	RefreshToken string `json:"refresh_token"` // the single-use token to get new tokens
+} // @name Tokens
+
+type UserWithTokens struct {
+	User
+	Tokens
+} // @name UserWithTokens
+
+type RefreshPostPayload struct {
+	RefreshToken *string `json:"refresh_token" validate:"required" example:"kCZB4p3dfJqV6q"` // the refresh token
+} // @name RefreshPostPayload
+
+func (rp *RefreshPostPayload) Bind(r *http.Request) error {
+	if rp.RefreshToken == nil {
+		return errors.New("missing refresh_token property")
+	}
+
+	return nil
+}
+
+type LogoutPostPayload struct {
+	RefreshToken *string `json:"refresh_token" example:"kCZB4p3dfJqV6q"` // the refresh token of the session to end
+} // @name LogoutPostPayload
+
+type ChangePasswordPostPayload struct {
+	CurrentPassword *string `json:"current_password" validate:"required" example:"password"`  // the user's current password
+	NewPassword     *string `json:"new_password" validate:"required" example:"MyNewPassword"` // the user's new password
+} // @name ChangePasswordPostPayload
+
+func (cp *ChangePasswordPostPayload) Bind(r *http.Request) error {
+	if cp.CurrentPassword == nil {
+		return errors.New("missing current_password property")
+	}
+
+	if cp.NewPassword == nil {
+		return errors.New("missing new_password property")
+	}
+
+	return nil
+}
+
+type ChangeEmailPostPayload struct {
+	Password *string `json:"password" validate:"required" example:"password"`              // the user's current password
+	NewEmail *string `json:"new_email" validate:"required" example:"contact@feldrise.com"` // the email to switch to once verified
+} // @name ChangeEmailPostPayload
+
+func (cp *ChangeEmailPostPayload) Bind(r *http.Request) error {
+	if cp.Password == nil {
+		return errors.New("missing password property")
+	}
+
+	if cp.NewEmail == nil || *cp.NewEmail == "" {
+		return errors.New("missing new_email property")
+	}
+
+	return nil
+}
@@ -0,0 +1,5 @@
+package model
+
+type CalendarFeed struct {
+	URL string `json:"url"` // the feed to subscribe to in a calendar application, only returned on creation
+} // @name CalendarFeed
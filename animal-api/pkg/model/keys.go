@@ -0,0 +1,21 @@
+package model
+
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`           // RSA or OKP
+	KeyID     string `json:"kid"`           // the id found in the tokens' header
+	Use       string `json:"use"`           // always sig
+	Algorithm string `json:"alg"`           // RS256 or EdDSA
+	Modulus   string `json:"n,omitempty"`   // RSA modulus
+	Exponent  string `json:"e,omitempty"`   // RSA public exponent
+	Curve     string `json:"crv,omitempty"` // OKP curve, always Ed25519
+	X         string `json:"x,omitempty"`   // OKP public key
+} // @name JSONWebKey
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+} // @name JSONWebKeySet
+
+type SigningKey struct {
+	KeyID     string `json:"kid"` // the id of the new signing key
+	Algorithm string `json:"alg"` // the algorithm of the new signing key
+} // @name SigningKey
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The path the API routes are mounted on
+const apiPath = "/api/v1"
+
+// Signed links stay valid at least this long. They expire on the hour so
+// they don't change on every request and can be cached.
+const signedLinkLifetime = 24 * time.Hour
+
+var (
+	baseURL    = apiPath
+	signingKey []byte
+)
+
+// SetBaseURL sets the public URL of the API the links of the models are built
+// from
+func SetBaseURL(url string) {
+	baseURL = strings.TrimSuffix(url, "/") + apiPath
+}
+
+// SetSigningKey sets the key of the signed links, which give access to a
+// resource without authentication
+func SetSigningKey(key string) {
+	signingKey = []byte(key)
+}
+
+// Links are the URLs of a resource and of the resources related to it
+type Links map[string]string // @name Links
+
+func AnimalLinks(id uint) Links {
+	return Links{
+		"self":       link("/animals/%d", id),
+		"visits":     link("/%d/visits", id),
+		"vitals":     link("/animals/%d/vitals", id),
+		"photos":     link("/animals/%d/photos", id),
+		"treatments": link("/animals/%d/treatments", id),
+	}
+}
+
+func VisitLinks(animalID uint, id uint) Links {
+	return Links{
+		"self":   link("/%d/visits/%d", animalID, id),
+		"animal": link("/animals/%d", animalID),
+	}
+}
+
+func VisitSeriesLinks(animalID uint) Links {
+	return Links{
+		"visits": link("/%d/visits", animalID),
+		"animal": link("/animals/%d", animalID),
+	}
+}
+
+func VisitTreatmentLinks(animalID uint, visitID uint, id uint, treatmentID uint) Links {
+	return Links{
+		"self":      link("/%d/visits/%d/treatments/%d", animalID, visitID, id),
+		"visit":     link("/%d/visits/%d", animalID, visitID),
+		"treatment": link("/treatments/%d", treatmentID),
+	}
+}
+
+func TreatmentLinks(id uint) Links {
+	return Links{
+		"self": link("/treatments/%d", id),
+	}
+}
+
+func AppointmentTypeLinks(id uint) Links {
+	return Links{
+		"self": link("/schedule/appointment-types/%d", id),
+	}
+}
+
+func TimeOffLinks(vetID uint, id uint) Links {
+	return Links{
+		"self":          link("/schedule/vets/%d/time-off/%d", vetID, id),
+		"working_hours": link("/schedule/vets/%d/working-hours", vetID),
+	}
+}
+
+func VitalLinks(animalID uint, id uint) Links {
+	return Links{
+		"self":   link("/animals/%d/vitals/%d", animalID, id),
+		"animal": link("/animals/%d", animalID),
+	}
+}
+
+func PhotoLinks(animalID uint, id uint) Links {
+	return Links{
+		"self":   link("/animals/%d/photos/%d", animalID, id),
+		"animal": link("/animals/%d", animalID),
+	}
+}
+
+// CalendarFeedURL returns the URL of the calendar feed of a token, which
+// gives access to it without authentication
+func CalendarFeedURL(token string) string {
+	return link("/calendar/feeds/%s.ics", token)
+}
+
+// PhotoURLs returns the signed URLs of the sizes of a photo
+func PhotoURLs(id uint) Links {
+	urls := Links{}
+
+	for _, size := range PhotoSizes {
+		urls[size] = signedLink(fmt.Sprintf("/photos/%d/%s", id, size))
+	}
+
+	return urls
+}
+
+// CheckSignature tells whether the signed link of the path is genuine and
+// not expired
+func CheckSignature(path string, expires string, signature string) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	return hmac.Equal(expected, sign(path, expires))
+}
+
+func UserLinks(id uint) Links {
+	return Links{
+		"self": link("/users/%d", id),
+	}
+}
+
+func RoleLinks(id uint) Links {
+	return Links{
+		"self": link("/authentication/roles/%d", id),
+	}
+}
+
+func link(format string, args ...interface{}) string {
+	return baseURL + fmt.Sprintf(format, args...)
+}
+
+func signedLink(path string) string {
+	expires := strconv.FormatInt(time.Now().Truncate(time.Hour).Add(signedLinkLifetime+time.Hour).Unix(), 10)
+
+	return baseURL + path + "?expires=" + expires + "&signature=" + hex.EncodeToString(sign(path, expires))
+}
+
+func sign(path string, expires string) []byte {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(path + "\n" + expires))
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"net/http"
+)
+
+type MFARequired struct {
+	MFARequired bool   `json:"mfa_required"` // always true, the login must be completed with /authentication/mfa/verify
+	MFAToken    string `json:"mfa_token"`    // the short-lived token to send with the second factor
+} // @name MFARequired
+
+type MFAEnrollment struct {
+	Secret string `json:"secret"` // the base32 secret, for manual entry
+	URI    string `json:"uri"`    // the otpauth:// URI, to display as a QR code
+} // @name MFAEnrollment
+
+type RecoveryCodes struct {
+	Codes []string `json:"codes"` // single-use codes replacing the second factor, only shown once
+} // @name RecoveryCodes
+
+type MFACodePostPayload struct {
+	Code *string `json:"code" validate:"required" example:"123456"` // the code from the authenticator application
+} // @name MFACodePostPayload
+
+func (mp *MFACodePostPayload) Bind(r *http.Request) error {
+	if mp.Code == nil {
+		return errors.New("missing code property")
+	}
+
+	return nil
+}
+
+type MFAVerifyPostPayload struct {
+	MFAToken     *string `json:"mfa_token" validate:"required" example:"eyJhbGciOiJFZERTQSJ9"` // the token returned by the login
+	Code         *string `json:"code" example:"123456"`                                        // the code from the authenticator application
+	RecoveryCode *string `json:"recovery_code" example:"abcd-efgh"`                            // a recovery code, in place of code
+} // @name MFAVerifyPostPayload
+
+func (mp *MFAVerifyPostPayload) Bind(r *http.Request) error {
+	if mp.MFAToken == nil {
+		return errors.New("missing mfa_token property")
+	}
+
+	if mp.Code == nil && mp.RecoveryCode == nil {
+		return errors.New("missing code or recovery_code property")
+	}
+
+	return nil
+}
+
+type MFADisablePostPayload struct {
+	Password *string `json:"password" validate:"required" example:"password"` // the user's password
+	Code     *string `json:"code" validate:"required" example:"123456"`       // the code from the authenticator application
+} // @name MFADisablePostPayload
+
+func (mp *MFADisablePostPayload) Bind(r *http.Request) error {
+	if mp.Password == nil {
+		return errors.New("missing password property")
+	}
+
+	if mp.Code == nil {
+		return errors.New("missing code property")
+	}
+
+	return nil
+}
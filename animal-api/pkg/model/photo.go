@@ -0,0 +1,23 @@
+package model
+
+const (
+	PhotoSizeLarge     = "large"
+	PhotoSizeMedium    = "medium"
+	PhotoSizeThumbnail = "thumbnail"
+)
+
+// PhotoSizes are the sizes each photo is stored in
+var PhotoSizes = []string{PhotoSizeLarge, PhotoSizeMedium, PhotoSizeThumbnail}
+
+type Photo struct {
+	ID        uint   `json:"id"`         // @id
+	CreatedAt string `json:"created_at"` // the photo's upload date
+	UpdatedAt string `json:"updated_at"` // the photo's last update date
+	Links     Links  `json:"links"`      // the photo's resources
+
+	AnimalID uint  `json:"animal_id"`
+	Profile  bool  `json:"profile"` // whether the photo is the animal's profile picture
+	Width    int   `json:"width"`   // the width of the large size, in pixels
+	Height   int   `json:"height"`  // the height of the large size, in pixels
+	URLs     Links `json:"urls"`    // the signed URLs of the large (2048px), medium (800px) and thumbnail (200px square) sizes, valid for a day
+} // @name Photo
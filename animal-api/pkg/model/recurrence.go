@@ -0,0 +1,185 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The frequencies of the recurrences, a subset of the RRULE of RFC 5545
+const (
+	FrequencyDaily   = "DAILY"
+	FrequencyWeekly  = "WEEKLY"
+	FrequencyMonthly = "MONTHLY"
+	FrequencyYearly  = "YEARLY"
+)
+
+// MaxRecurrenceOccurrences is the most visits a series can have
+const MaxRecurrenceOccurrences = 100
+
+// Recurrence repeats a visit every Interval days, weeks, months or years,
+// Count times or until a date
+type Recurrence struct {
+	Frequency string
+	Interval  int
+	Count     int
+	Until     *time.Time
+}
+
+// ParseRecurrence parses a rule like FREQ=WEEKLY;INTERVAL=2;COUNT=6, the
+// parts FREQ, INTERVAL, COUNT and UNTIL are supported and COUNT or UNTIL is
+// required. UNTIL is a UTC date time like 20260101T000000Z or a date like
+// 20260101, in the location.
+func ParseRecurrence(rule string, location *time.Location) (*Recurrence, error) {
+	recurrence := &Recurrence{Interval: 1}
+
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"), ";") {
+		name, value, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid recurrence part %q, NAME=value expected", part)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			recurrence.Frequency = strings.ToUpper(value)
+
+			switch recurrence.Frequency {
+			case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+			default:
+				return nil, errors.New("invalid recurrence FREQ, DAILY, WEEKLY, MONTHLY or YEARLY expected")
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, errors.New("invalid recurrence INTERVAL, a positive number expected")
+			}
+
+			recurrence.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 || count > MaxRecurrenceOccurrences {
+				return nil, fmt.Errorf("invalid recurrence COUNT, 1 to %d expected", MaxRecurrenceOccurrences)
+			}
+
+			recurrence.Count = count
+		case "UNTIL":
+			until, err := time.Parse("20060102T150405Z", value)
+			if err != nil {
+				// A date includes its whole day
+				date, dateErr := time.ParseInLocation("20060102", value, location)
+				if dateErr != nil {
+					return nil, errors.New("invalid recurrence UNTIL, 20060102 or 20060102T150405Z expected")
+				}
+
+				until = date.AddDate(0, 0, 1).Add(-time.Second)
+			}
+
+			recurrence.Until = &until
+		default:
+			return nil, fmt.Errorf("unsupported recurrence part %s", name)
+		}
+	}
+
+	if recurrence.Frequency == "" {
+		return nil, errors.New("missing recurrence FREQ")
+	}
+
+	if recurrence.Count == 0 && recurrence.Until == nil {
+		return nil, errors.New("missing recurrence COUNT or UNTIL")
+	}
+
+	if recurrence.Count != 0 && recurrence.Until != nil {
+		return nil, errors.New("invalid recurrence, COUNT and UNTIL can't be both given")
+	}
+
+	return recurrence, nil
+}
+
+// String formats the recurrence as a RRULE
+func (r *Recurrence) String() string {
+	rule := "FREQ=" + r.Frequency
+
+	if r.Interval > 1 {
+		rule += ";INTERVAL=" + strconv.Itoa(r.Interval)
+	}
+
+	if r.Count != 0 {
+		rule += ";COUNT=" + strconv.Itoa(r.Count)
+	}
+
+	if r.Until != nil {
+		rule += ";UNTIL=" + r.Until.UTC().Format("20060102T150405Z")
+	}
+
+	return rule
+}
+
+// Occurrences returns the dates of the visits, starting with the first one.
+// They keep its clock time in the location, and the months missing its day
+// are skipped. An error is returned when there are more than
+// MaxRecurrenceOccurrences.
+func (r *Recurrence) Occurrences(first time.Time, location *time.Location) ([]time.Time, error) {
+	var occurrences []time.Time
+
+	first = first.In(location)
+
+	for i := 0; r.Count == 0 || len(occurrences) < r.Count; i++ {
+		var years, months, days int
+
+		switch r.Frequency {
+		case FrequencyDaily:
+			days = i * r.Interval
+		case FrequencyWeekly:
+			days = 7 * i * r.Interval
+		case FrequencyMonthly:
+			months = i * r.Interval
+		case FrequencyYearly:
+			years = i * r.Interval
+		}
+
+		date := time.Date(first.Year()+years, first.Month()+time.Month(months), first.Day()+days, first.Hour(), first.Minute(), first.Second(), 0, location)
+
+		if r.Until != nil && date.After(*r.Until) {
+			break
+		}
+
+		// Normalized to the next month, like the 31st of a 30 days month
+		if days == 0 && date.Day() != first.Day() {
+			continue
+		}
+
+		if len(occurrences) == MaxRecurrenceOccurrences {
+			return nil, fmt.Errorf("invalid recurrence, %d occurrences at most", MaxRecurrenceOccurrences)
+		}
+
+		occurrences = append(occurrences, date)
+	}
+
+	return occurrences, nil
+}
+
+// ShiftDate moves the date like a visit moved from one date to another: by
+// the same number of days and to the same clock time change in the
+// location, so the series keep their clock time across the changes of
+// offset
+func ShiftDate(date time.Time, from time.Time, to time.Time, location *time.Location) time.Time {
+	date, from, to = date.In(location), from.In(location), to.In(location)
+
+	days := int(civilDay(to).Sub(civilDay(from)).Hours() / 24)
+	clock := clockTime(to) - clockTime(from)
+
+	// The seconds are normalized by time.Date in clock time
+	seconds := int((clockTime(date) + clock) / time.Second)
+
+	return time.Date(date.Year(), date.Month(), date.Day()+days, 0, 0, seconds, 0, location)
+}
+
+func civilDay(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func clockTime(date time.Time) time.Duration {
+	return time.Duration(date.Hour())*time.Hour + time.Duration(date.Minute())*time.Minute + time.Duration(date.Second())*time.Second
+}
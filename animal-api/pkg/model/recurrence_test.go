@@ -0,0 +1,249 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	paris := loadParis(t)
+	until := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	endOfDay := time.Date(2026, 1, 1, 23, 59, 59, 0, paris)
+
+	tests := []struct {
+		rule    string
+		want    *Recurrence
+		wantErr bool
+	}{
+		{rule: "FREQ=WEEKLY;COUNT=6", want: &Recurrence{Frequency: FrequencyWeekly, Interval: 1, Count: 6}},
+		{rule: "RRULE:FREQ=MONTHLY;INTERVAL=2;COUNT=3", want: &Recurrence{Frequency: FrequencyMonthly, Interval: 2, Count: 3}},
+		{rule: "freq=daily;count=2", want: &Recurrence{Frequency: FrequencyDaily, Interval: 1, Count: 2}},
+		{rule: "FREQ=YEARLY;UNTIL=20260101T000000Z", want: &Recurrence{Frequency: FrequencyYearly, Interval: 1, Until: &until}},
+		{rule: "FREQ=DAILY;UNTIL=20260101", want: &Recurrence{Frequency: FrequencyDaily, Interval: 1, Until: &endOfDay}},
+		{rule: "", wantErr: true},
+		{rule: "FREQ=WEEKLY", wantErr: true},
+		{rule: "COUNT=3", wantErr: true},
+		{rule: "FREQ=HOURLY;COUNT=3", wantErr: true},
+		{rule: "FREQ=WEEKLY;COUNT=0", wantErr: true},
+		{rule: "FREQ=WEEKLY;COUNT=101", wantErr: true},
+		{rule: "FREQ=WEEKLY;INTERVAL=0;COUNT=3", wantErr: true},
+		{rule: "FREQ=WEEKLY;COUNT=3;UNTIL=20260101", wantErr: true},
+		{rule: "FREQ=WEEKLY;UNTIL=2026-01-01", wantErr: true},
+		{rule: "FREQ=WEEKLY;BYDAY=MO;COUNT=3", wantErr: true},
+		{rule: "FREQ=WEEKLY;COUNT", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			got, err := ParseRecurrence(test.rule, paris)
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ParseRecurrence() = %+v, want an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseRecurrence() error = %v", err)
+			}
+
+			if got.Frequency != test.want.Frequency || got.Interval != test.want.Interval || got.Count != test.want.Count {
+				t.Errorf("ParseRecurrence() = %+v, want %+v", got, test.want)
+			}
+
+			if (got.Until == nil) != (test.want.Until == nil) || got.Until != nil && !got.Until.Equal(*test.want.Until) {
+				t.Errorf("ParseRecurrence() until = %v, want %v", got.Until, test.want.Until)
+			}
+		})
+	}
+}
+
+func TestRecurrenceString(t *testing.T) {
+	paris := loadParis(t)
+
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{"RRULE:freq=weekly;interval=1;count=6", "FREQ=WEEKLY;COUNT=6"},
+		{"FREQ=MONTHLY;INTERVAL=2;COUNT=3", "FREQ=MONTHLY;INTERVAL=2;COUNT=3"},
+		{"FREQ=DAILY;UNTIL=20260101", "FREQ=DAILY;UNTIL=20260101T225959Z"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			recurrence, err := ParseRecurrence(test.rule, paris)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := recurrence.String(); got != test.want {
+				t.Errorf("String() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	paris := loadParis(t)
+
+	date := func(year int, month time.Month, day int, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, paris)
+	}
+
+	tests := []struct {
+		name    string
+		rule    string
+		first   time.Time
+		want    []time.Time
+		wantErr bool
+	}{
+		{
+			name:  "weekly",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;COUNT=3",
+			first: date(2026, 1, 5, 9),
+			want:  []time.Time{date(2026, 1, 5, 9), date(2026, 1, 19, 9), date(2026, 2, 2, 9)},
+		},
+		{
+			name:  "daily keeps the clock time across DST",
+			rule:  "FREQ=DAILY;COUNT=3",
+			first: date(2026, 3, 28, 9),
+			want:  []time.Time{date(2026, 3, 28, 9), date(2026, 3, 29, 9), date(2026, 3, 30, 9)},
+		},
+		{
+			name:  "monthly skips the months without the 31st",
+			rule:  "FREQ=MONTHLY;COUNT=4",
+			first: date(2026, 1, 31, 10),
+			want:  []time.Time{date(2026, 1, 31, 10), date(2026, 3, 31, 10), date(2026, 5, 31, 10), date(2026, 7, 31, 10)},
+		},
+		{
+			name:  "yearly skips the years without the 29th of February",
+			rule:  "FREQ=YEARLY;COUNT=2",
+			first: date(2024, 2, 29, 10),
+			want:  []time.Time{date(2024, 2, 29, 10), date(2028, 2, 29, 10)},
+		},
+		{
+			name:  "until a date includes its day",
+			rule:  "FREQ=WEEKLY;UNTIL=20260119",
+			first: date(2026, 1, 5, 18),
+			want:  []time.Time{date(2026, 1, 5, 18), date(2026, 1, 12, 18), date(2026, 1, 19, 18)},
+		},
+		{
+			name:  "until a date time",
+			rule:  "FREQ=WEEKLY;UNTIL=20260119T080000Z",
+			first: date(2026, 1, 5, 9),
+			want:  []time.Time{date(2026, 1, 5, 9), date(2026, 1, 12, 9), date(2026, 1, 19, 9)},
+		},
+		{
+			name:  "until before the first visit",
+			rule:  "FREQ=WEEKLY;UNTIL=20200101",
+			first: date(2026, 1, 5, 9),
+			want:  nil,
+		},
+		{
+			name:    "too many occurrences",
+			rule:    "FREQ=DAILY;UNTIL=20270101",
+			first:   date(2026, 1, 5, 9),
+			wantErr: true,
+		},
+		{
+			name:  "the first date is read in the location",
+			rule:  "FREQ=WEEKLY;COUNT=2",
+			first: time.Date(2026, 3, 23, 8, 0, 0, 0, time.UTC),
+			want:  []time.Time{date(2026, 3, 23, 9), date(2026, 3, 30, 9)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			recurrence, err := ParseRecurrence(test.rule, paris)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := recurrence.Occurrences(test.first, paris)
+
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("Occurrences() = %v, want an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Occurrences() error = %v", err)
+			}
+
+			if len(got) != len(test.want) {
+				t.Fatalf("Occurrences() = %v, want %v", got, test.want)
+			}
+
+			for i := range got {
+				if !got[i].Equal(test.want[i]) {
+					t.Errorf("Occurrences()[%d] = %v, want %v", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestShiftDate(t *testing.T) {
+	paris := loadParis(t)
+
+	date := func(month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, paris)
+	}
+
+	tests := []struct {
+		name string
+		date time.Time
+		from time.Time
+		to   time.Time
+		want time.Time
+	}{
+		{
+			name: "same offset",
+			date: date(1, 19, 9, 0),
+			from: date(1, 5, 9, 0),
+			to:   date(1, 6, 10, 30),
+			want: date(1, 20, 10, 30),
+		},
+		{
+			name: "moved in winter, shifted in summer",
+			date: date(4, 6, 9, 0),
+			from: date(3, 23, 9, 0),
+			to:   date(3, 24, 10, 0),
+			want: date(4, 7, 10, 0),
+		},
+		{
+			name: "moved across the change of offset",
+			date: date(4, 6, 9, 0),
+			from: date(3, 27, 9, 0),
+			to:   date(3, 30, 9, 0),
+			want: date(4, 9, 9, 0),
+		},
+		{
+			name: "moved back to the previous day",
+			date: date(11, 2, 8, 0),
+			from: date(10, 19, 8, 0),
+			to:   date(10, 18, 23, 30),
+			want: date(11, 1, 23, 30),
+		},
+		{
+			name: "given in another location",
+			date: date(4, 6, 9, 0).UTC(),
+			from: date(3, 23, 9, 0).UTC(),
+			to:   date(3, 23, 14, 0).UTC(),
+			want: date(4, 6, 14, 0),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ShiftDate(test.date, test.from, test.to, paris); !got.Equal(test.want) {
+				t.Errorf("ShiftDate() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Roles created by seed.SeedV1
+const (
+	RoleAdmin       uint = 1
+	RoleVeterinaire uint = 2
+	RoleClient      uint = 3
+)
+
+var (
+	AllRoles   = []uint{RoleAdmin, RoleVeterinaire, RoleClient}
+	StaffRoles = []uint{RoleAdmin, RoleVeterinaire}
+)
+
+func IsValidRole(roleID uint) bool {
+	for _, role := range AllRoles {
+		if role == roleID {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Role struct {
+	ID        uint   `json:"id"`         // @id
+	CreatedAt string `json:"created_at"` // the role's creation date
+	UpdatedAt string `json:"updated_at"` // the role's last update date
+	Links     Links  `json:"links"`      // the role's resources
+
+	Name        string `json:"name"`        // the role's name
+	Description string `json:"description"` // the role's description
+	RequireMFA  bool   `json:"require_mfa"` // whether the users having the role must enable two-factor authentication
+} // @name Role
+
+type RoleSettingsPayload struct {
+	RequireMFA *bool `json:"require_mfa" validate:"required" example:"true"` // whether the users having the role must enable two-factor authentication
+} // @name RoleSettingsPayload
+
+func (rp *RoleSettingsPayload) Bind(r *http.Request) error {
+	if rp.RequireMFA == nil {
+		return errors.New("missing require_mfa property")
+	}
+
+	return nil
+}
+
+type RoleUpdatePayload struct {
+	RoleID *uint `json:"role_id" validate:"required" example:"2"` // the new role of the user
+} // @name RoleUpdatePayload
+
+func (rp *RoleUpdatePayload) Bind(r *http.Request) error {
+	if rp.RoleID == nil {
+		return errors.New("missing role_id property")
+	}
+
+	if !IsValidRole(*rp.RoleID) {
+		return errors.New("invalid role_id property")
+	}
+
+	return nil
+}
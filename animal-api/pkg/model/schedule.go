@@ -0,0 +1,325 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The slots offered to book start every SlotStep from the beginning of the
+// working hours
+const SlotStep = 15 * time.Minute
+
+// MaxAvailabilityPeriod is the longest period the free slots are searched on
+// at once
+const MaxAvailabilityPeriod = 31 * 24 * time.Hour
+
+type AppointmentType struct {
+	ID        uint    `json:"id"`                   // @id
+	CreatedAt string  `json:"created_at"`           // the type's creation date
+	UpdatedAt string  `json:"updated_at"`           // the type's last update date
+	DeletedAt *string `json:"deleted_at,omitempty"` // when the type was removed
+	Links     Links   `json:"links"`                // the type's resources
+
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Duration    int    `json:"duration"` // in minutes
+} // @name AppointmentType
+
+type AppointmentTypeCreatePayload struct {
+	Name        string `json:"name" validate:"required" example:"Vaccination"`
+	Description string `json:"description" example:"Annual booster and check-up"`
+	Duration    int    `json:"duration" validate:"required" example:"20"` // in minutes
+} // @name AppointmentTypeCreatePayload
+
+func (a *AppointmentTypeCreatePayload) Bind(r *http.Request) error {
+	a.Name = strings.TrimSpace(a.Name)
+
+	if a.Name == "" {
+		return errors.New("missing name property")
+	}
+
+	return validateVisit(nil, &a.Duration, nil)
+}
+
+type AppointmentTypeUpdatePayload struct {
+	Name        *string `json:"name" example:"Vaccination"`
+	Description *string `json:"description" example:"Annual booster and check-up"`
+	Duration    *int    `json:"duration" example:"20"` // the visits already booked keep their duration
+} // @name AppointmentTypeUpdatePayload
+
+// AppointmentTypeUpdateFields lists the fields each role can change on an
+// appointment type
+var AppointmentTypeUpdateFields = map[uint][]string{
+	RoleAdmin:       {"name", "description", "duration"},
+	RoleVeterinaire: {},
+	RoleClient:      {},
+}
+
+func (a *AppointmentTypeUpdatePayload) Validate() error {
+	if a.Name != nil {
+		*a.Name = strings.TrimSpace(*a.Name)
+
+		if *a.Name == "" {
+			return errors.New("empty name property")
+		}
+	}
+
+	return validateVisit(nil, a.Duration, nil)
+}
+
+// WorkingHours is a period a veterinarian works every week, a day can have
+// several periods around a break
+type WorkingHours struct {
+	Weekday int    `json:"weekday" example:"1"`   // from 0 for Sunday to 6 for Saturday
+	Start   string `json:"start" example:"09:00"` // in the clinic's time zone
+	End     string `json:"end" example:"12:30"`   // in the clinic's time zone
+} // @name WorkingHours
+
+type WorkingHoursPayload struct {
+	Hours []WorkingHours `json:"hours" validate:"required"` // replaces all the working hours of the veterinarian
+} // @name WorkingHoursPayload
+
+func (w *WorkingHoursPayload) Bind(r *http.Request) error {
+	if w.Hours == nil {
+		return errors.New("missing hours property")
+	}
+
+	periods := make([]WorkingPeriod, 0, len(w.Hours))
+
+	for _, hours := range w.Hours {
+		period, err := hours.Period()
+		if err != nil {
+			return err
+		}
+
+		periods = append(periods, period)
+	}
+
+	sort.Slice(periods, func(i, j int) bool {
+		if periods[i].Weekday != periods[j].Weekday {
+			return periods[i].Weekday < periods[j].Weekday
+		}
+
+		return periods[i].Start < periods[j].Start
+	})
+
+	for i := 1; i < len(periods); i++ {
+		if periods[i].Weekday == periods[i-1].Weekday && periods[i].Start < periods[i-1].End {
+			return errors.New("invalid hours property, the periods of a day overlap")
+		}
+	}
+
+	return nil
+}
+
+// WorkingPeriod is a period of working hours, in minutes since midnight
+type WorkingPeriod struct {
+	Weekday time.Weekday
+	Start   int
+	End     int
+}
+
+// Period parses the working hours
+func (w *WorkingHours) Period() (WorkingPeriod, error) {
+	if w.Weekday < 0 || w.Weekday > 6 {
+		return WorkingPeriod{}, errors.New("invalid weekday property, 0 for Sunday to 6 for Saturday expected")
+	}
+
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return WorkingPeriod{}, fmt.Errorf("invalid start property, %w", err)
+	}
+
+	end, err := parseClock(w.End)
+	if err != nil {
+		return WorkingPeriod{}, fmt.Errorf("invalid end property, %w", err)
+	}
+
+	if end <= start {
+		return WorkingPeriod{}, errors.New("invalid end property, the period ends before it starts")
+	}
+
+	return WorkingPeriod{Weekday: time.Weekday(w.Weekday), Start: start, End: end}, nil
+}
+
+// NewWorkingHours formats a period of working hours
+func NewWorkingHours(period WorkingPeriod) WorkingHours {
+	return WorkingHours{
+		Weekday: int(period.Weekday),
+		Start:   fmt.Sprintf("%02d:%02d", period.Start/60, period.Start%60),
+		End:     fmt.Sprintf("%02d:%02d", period.End/60, period.End%60),
+	}
+}
+
+type TimeOff struct {
+	ID        uint   `json:"id"`         // @id
+	CreatedAt string `json:"created_at"` // the time off's creation date
+	UpdatedAt string `json:"updated_at"` // the time off's last update date
+	Links     Links  `json:"links"`      // the time off's resources
+
+	VetID  uint      `json:"vet_id"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason string    `json:"reason"`
+} // @name TimeOff
+
+type TimeOffCreatePayload struct {
+	Start  *time.Time `json:"start" validate:"required" example:"2021-08-02T00:00:00+02:00"`
+	End    *time.Time `json:"end" validate:"required" example:"2021-08-16T00:00:00+02:00"`
+	Reason string     `json:"reason" example:"Holidays"`
+} // @name TimeOffCreatePayload
+
+func (t *TimeOffCreatePayload) Bind(r *http.Request) error {
+	if t.Start == nil {
+		return errors.New("missing start property")
+	}
+
+	if t.End == nil {
+		return errors.New("missing end property")
+	}
+
+	if !t.End.After(*t.Start) {
+		return errors.New("invalid end property, the time off ends before it starts")
+	}
+
+	return nil
+}
+
+// Slot is a free period of a veterinarian an appointment can be booked on
+type Slot struct {
+	VetID uint      `json:"vet_id"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+} // @name Slot
+
+type AppointmentCreatePayload struct {
+	AnimalID          *uint      `json:"animal_id" validate:"required" example:"4"`
+	AppointmentTypeID *uint      `json:"appointment_type_id" validate:"required" example:"1"`
+	VetID             *uint      `json:"vet_id" example:"2"` // any available veterinarian when not given
+	Start             *time.Time `json:"start" validate:"required" example:"2021-01-04T09:30:00+01:00"`
+	Reason            string     `json:"reason" example:"Vaccination"` // the name of the appointment type by default
+} // @name AppointmentCreatePayload
+
+func (a *AppointmentCreatePayload) Bind(r *http.Request) error {
+	if a.AnimalID == nil {
+		return errors.New("missing animal_id property")
+	}
+
+	if a.AppointmentTypeID == nil {
+		return errors.New("missing appointment_type_id property")
+	}
+
+	if a.Start == nil {
+		return errors.New("missing start property")
+	}
+
+	if a.Start.Before(time.Now()) {
+		return errors.New("invalid start property, the date is in the past")
+	}
+
+	return validateVisit(&a.Reason, nil, nil)
+}
+
+// TimeRange is a period between two dates
+type TimeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+func (t TimeRange) Overlaps(other TimeRange) bool {
+	return t.Start.Before(other.End) && other.Start.Before(t.End)
+}
+
+// FreeSlots returns the slots of the duration starting between from and to,
+// within the working periods and outside the busy ranges. The working
+// periods are in the location.
+func FreeSlots(from time.Time, to time.Time, location *time.Location, periods []WorkingPeriod, busy []TimeRange, duration time.Duration) []TimeRange {
+	var slots []TimeRange
+
+	from = from.In(location)
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, location)
+
+	for ; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, period := range periods {
+			if period.Weekday != day.Weekday() {
+				continue
+			}
+
+			// The minutes are normalized by time.Date, which keeps the
+			// clock times right on the days the clocks change
+			periodEnd := time.Date(day.Year(), day.Month(), day.Day(), 0, period.End, 0, 0, location)
+
+			for minute := period.Start; ; minute += int(SlotStep / time.Minute) {
+				slot := TimeRange{Start: time.Date(day.Year(), day.Month(), day.Day(), 0, minute, 0, 0, location)}
+				slot.End = slot.Start.Add(duration)
+
+				if slot.End.After(periodEnd) || !slot.Start.Before(to) {
+					break
+				}
+
+				if slot.Start.Before(from) || overlapsAny(slot, busy) {
+					continue
+				}
+
+				slots = append(slots, slot)
+			}
+		}
+	}
+
+	sort.Slice(slots, func(i, j int) bool {
+		return slots[i].Start.Before(slots[j].Start)
+	})
+
+	return slots
+}
+
+// WithinWorkingHours returns whether the range is within one of the working
+// periods, which are in the location
+func WithinWorkingHours(timeRange TimeRange, location *time.Location, periods []WorkingPeriod) bool {
+	start := timeRange.Start.In(location)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, location)
+
+	for _, period := range periods {
+		if period.Weekday != day.Weekday() {
+			continue
+		}
+
+		periodStart := time.Date(day.Year(), day.Month(), day.Day(), 0, period.Start, 0, 0, location)
+		periodEnd := time.Date(day.Year(), day.Month(), day.Day(), 0, period.End, 0, 0, location)
+
+		if !timeRange.Start.Before(periodStart) && !timeRange.End.After(periodEnd) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func overlapsAny(timeRange TimeRange, ranges []TimeRange) bool {
+	for _, other := range ranges {
+		if timeRange.Overlaps(other) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseClock returns the minutes since midnight of a HH:MM time
+func parseClock(clock string) (int, error) {
+	// The end of the day, which time.Parse refuses
+	if clock == "24:00" {
+		return 24 * 60, nil
+	}
+
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, errors.New("HH:MM expected")
+	}
+
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
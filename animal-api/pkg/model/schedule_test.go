@@ -0,0 +1,146 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func loadParis(t *testing.T) *time.Location {
+	t.Helper()
+
+	location, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return location
+}
+
+func TestFreeSlots(t *testing.T) {
+	paris := loadParis(t)
+
+	date := func(month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, paris)
+	}
+
+	// The 2026-03-23 and 2026-03-30 are Mondays, the clocks change on the
+	// 2026-03-29
+	mondayMorning := WorkingPeriod{Weekday: time.Monday, Start: 9 * 60, End: 10 * 60}
+	mondayAfternoon := WorkingPeriod{Weekday: time.Monday, Start: 14 * 60, End: 14*60 + 30}
+	fridayMorning := WorkingPeriod{Weekday: time.Friday, Start: 9 * 60, End: 9*60 + 30}
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		to       time.Time
+		periods  []WorkingPeriod
+		busy     []TimeRange
+		duration time.Duration
+		want     []time.Time
+	}{
+		{
+			name:     "every step of the period",
+			from:     date(3, 23, 0, 0),
+			to:       date(3, 24, 0, 0),
+			periods:  []WorkingPeriod{mondayMorning},
+			duration: 30 * time.Minute,
+			want:     []time.Time{date(3, 23, 9, 0), date(3, 23, 9, 15), date(3, 23, 9, 30)},
+		},
+		{
+			name:     "busy ranges",
+			from:     date(3, 23, 0, 0),
+			to:       date(3, 24, 0, 0),
+			periods:  []WorkingPeriod{mondayMorning},
+			busy:     []TimeRange{{Start: date(3, 23, 9, 0), End: date(3, 23, 9, 20)}},
+			duration: 30 * time.Minute,
+			want:     []time.Time{date(3, 23, 9, 30)},
+		},
+		{
+			name:     "from and to cut the period",
+			from:     date(3, 23, 9, 10),
+			to:       date(3, 23, 9, 30),
+			periods:  []WorkingPeriod{mondayMorning},
+			duration: 15 * time.Minute,
+			want:     []time.Time{date(3, 23, 9, 15)},
+		},
+		{
+			name:     "sorted across the periods",
+			from:     date(3, 23, 0, 0),
+			to:       date(3, 24, 0, 0),
+			periods:  []WorkingPeriod{mondayAfternoon, mondayMorning},
+			duration: 30 * time.Minute,
+			want:     []time.Time{date(3, 23, 9, 0), date(3, 23, 9, 15), date(3, 23, 9, 30), date(3, 23, 14, 0)},
+		},
+		{
+			name:     "longer than the period",
+			from:     date(3, 23, 0, 0),
+			to:       date(3, 24, 0, 0),
+			periods:  []WorkingPeriod{mondayMorning},
+			duration: 2 * time.Hour,
+			want:     nil,
+		},
+		{
+			name:     "same clock time across the change of offset",
+			from:     date(3, 27, 0, 0).UTC(),
+			to:       date(3, 31, 0, 0).UTC(),
+			periods:  []WorkingPeriod{fridayMorning, {Weekday: time.Monday, Start: 9 * 60, End: 9*60 + 30}},
+			duration: 30 * time.Minute,
+			want:     []time.Time{date(3, 27, 9, 0), date(3, 30, 9, 0)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := FreeSlots(test.from, test.to, paris, test.periods, test.busy, test.duration)
+
+			if len(got) != len(test.want) {
+				t.Fatalf("FreeSlots() = %v, want the starts %v", got, test.want)
+			}
+
+			for i := range got {
+				if !got[i].Start.Equal(test.want[i]) || !got[i].End.Equal(test.want[i].Add(test.duration)) {
+					t.Errorf("FreeSlots()[%d] = %v, want the start %v", i, got[i], test.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWithinWorkingHours(t *testing.T) {
+	paris := loadParis(t)
+
+	date := func(month time.Month, day int, hour int, minute int) time.Time {
+		return time.Date(2026, month, day, hour, minute, 0, 0, paris)
+	}
+
+	periods := []WorkingPeriod{
+		{Weekday: time.Monday, Start: 9 * 60, End: 12 * 60},
+		{Weekday: time.Monday, Start: 14 * 60, End: 18 * 60},
+	}
+
+	tests := []struct {
+		name  string
+		start time.Time
+		end   time.Time
+		want  bool
+	}{
+		{"inside", date(3, 23, 10, 0), date(3, 23, 10, 30), true},
+		{"the whole period", date(3, 23, 9, 0), date(3, 23, 12, 0), true},
+		{"before the start", date(3, 23, 8, 45), date(3, 23, 9, 15), false},
+		{"after the end", date(3, 23, 11, 45), date(3, 23, 12, 15), false},
+		{"over the break", date(3, 23, 11, 30), date(3, 23, 14, 30), false},
+		{"another day", date(3, 24, 10, 0), date(3, 24, 10, 30), false},
+		{"after the change of offset", date(3, 30, 9, 0), date(3, 30, 9, 30), true},
+		{"given in UTC", date(3, 30, 17, 30).UTC(), date(3, 30, 18, 0).UTC(), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := WithinWorkingHours(TimeRange{Start: test.start, End: test.end}, paris, periods)
+
+			if got != test.want {
+				t.Errorf("WithinWorkingHours() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
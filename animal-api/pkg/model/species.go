@@ -0,0 +1,64 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Species created by seed.SeedV4
+const (
+	SpeciesCat    = "cat"
+	SpeciesDog    = "dog"
+	SpeciesRabbit = "rabbit"
+	SpeciesNAC    = "nac" // new companion animals
+)
+
+// Types of the values of the species attributes
+const (
+	AttributeString  = "string"
+	AttributeNumber  = "number"
+	AttributeBoolean = "boolean"
+)
+
+type Species struct {
+	ID        uint   `json:"id"`         // @id
+	CreatedAt string `json:"created_at"` // the species' creation date
+	UpdatedAt string `json:"updated_at"` // the species' last update date
+
+	Code       string            `json:"code"`       // the code used to refer to the species
+	Name       string            `json:"name"`       // the species' name
+	Attributes map[string]string `json:"attributes"` // the optional attributes of the species' animals, with the type of their value
+} // @name Species
+
+// ValidateAttributes checks the attributes are known by the species and have
+// the expected type
+func (species *Species) ValidateAttributes(attributes map[string]interface{}) error {
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		kind, ok := species.Attributes[name]
+		if !ok {
+			return fmt.Errorf("unknown attribute %q for species %s", name, species.Code)
+		}
+
+		valid := false
+		switch attributes[name].(type) {
+		case string:
+			valid = kind == AttributeString
+		case float64:
+			valid = kind == AttributeNumber
+		case bool:
+			valid = kind == AttributeBoolean
+		}
+
+		if !valid {
+			return fmt.Errorf("invalid attribute %q, %s expected", name, kind)
+		}
+	}
+
+	return nil
+}
@@ -1,6 +1,203 @@
 package model
 
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"feldrise.com/animal-api/helper"
+)
+
+const (
+	TreatmentCategoryMedication    = "medication"
+	TreatmentCategoryVaccine       = "vaccine"
+	TreatmentCategoryAntiparasitic = "antiparasitic"
+	TreatmentCategoryProcedure     = "procedure"
+	TreatmentCategoryOther         = "other"
+)
+
+var TreatmentCategories = []string{TreatmentCategoryMedication, TreatmentCategoryVaccine, TreatmentCategoryAntiparasitic, TreatmentCategoryProcedure, TreatmentCategoryOther}
+
+// Whether a treatment was given during the visit or prescribed to be given
+// at home
+const (
+	TreatmentAdministered = "administered"
+	TreatmentPrescribed   = "prescribed"
+)
+
+// The routes a treatment is given by
+var TreatmentRoutes = []string{"oral", "topical", "subcutaneous", "intramuscular", "intravenous", "ophthalmic", "otic", "inhaled", "rectal", "other"}
+
 type Treatment struct {
+	ID        uint    `json:"id"`                   // @id
+	CreatedAt string  `json:"created_at"`           // the treatment's creation date
+	UpdatedAt string  `json:"updated_at"`           // the treatment's last update date
+	DeletedAt *string `json:"deleted_at,omitempty"` // when the treatment was removed from the catalog
+	Links     Links   `json:"links"`                // the treatment's resources
+
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	Category    string `json:"category" enums:"medication,vaccine,antiparasitic,procedure,other"`
 } // @name Treatment
+
+type TreatmentCreatePayload struct {
+	Name        string  `json:"name" validate:"required" example:"Meloxicam 1.5 mg/ml"`
+	Description string  `json:"description" example:"Anti-inflammatory, oral suspension"`
+	Category    *string `json:"category" example:"medication" enums:"medication,vaccine,antiparasitic,procedure,other"` // medication by default
+} // @name TreatmentCreatePayload
+
+func (t *TreatmentCreatePayload) Bind(r *http.Request) error {
+	t.Name = strings.TrimSpace(t.Name)
+
+	if t.Name == "" {
+		return errors.New("missing name property")
+	}
+
+	return validateTreatment(t.Category)
+}
+
+type TreatmentUpdatePayload struct {
+	Name        *string `json:"name" example:"Meloxicam 1.5 mg/ml"`
+	Description *string `json:"description" example:"Anti-inflammatory, oral suspension"`
+	Category    *string `json:"category" example:"medication" enums:"medication,vaccine,antiparasitic,procedure,other"`
+} // @name TreatmentUpdatePayload
+
+// TreatmentUpdateFields lists the fields each role can change in the catalog
+var TreatmentUpdateFields = map[uint][]string{
+	RoleAdmin:       {"name", "description", "category"},
+	RoleVeterinaire: {"name", "description", "category"},
+	RoleClient:      {},
+}
+
+func (t *TreatmentUpdatePayload) Validate() error {
+	if t.Name != nil {
+		*t.Name = strings.TrimSpace(*t.Name)
+
+		if *t.Name == "" {
+			return errors.New("empty name property")
+		}
+	}
+
+	return validateTreatment(t.Category)
+}
+
+// VisitTreatment is a treatment of the catalog given or prescribed during a
+// visit
+type VisitTreatment struct {
+	ID        uint   `json:"id"`         // @id
+	CreatedAt string `json:"created_at"` // the record's creation date
+	UpdatedAt string `json:"updated_at"` // the record's last update date
+	Links     Links  `json:"links"`      // the record's resources
+
+	VisitID     uint       `json:"visit_id"`
+	VisitDate   *time.Time `json:"visit_date,omitempty"` // the date of the visit, in the history of an animal
+	TreatmentID uint       `json:"treatment_id"`
+	Treatment   *Treatment `json:"treatment,omitempty"` // the treatment of the catalog
+
+	Kind         string  `json:"kind" enums:"administered,prescribed"`
+	Dosage       float64 `json:"dosage"`
+	Unit         string  `json:"unit"`          // the unit of the dosage, like mg, ml or tablet
+	Route        string  `json:"route"`         // oral, topical, subcutaneous, intramuscular, intravenous, ophthalmic, otic, inhaled, rectal or other
+	Frequency    string  `json:"frequency"`     // like once or every 12 hours
+	DurationDays *int    `json:"duration_days"` // how long a prescription lasts, in days
+	Notes        string  `json:"notes"`
+} // @name VisitTreatment
+
+type VisitTreatmentCreatePayload struct {
+	TreatmentID  *uint    `json:"treatment_id" validate:"required" example:"4"`
+	Kind         *string  `json:"kind" example:"prescribed" enums:"administered,prescribed"` // administered by default
+	Dosage       *float64 `json:"dosage" validate:"required" example:"0.5"`
+	Unit         string   `json:"unit" validate:"required" example:"ml"`
+	Route        string   `json:"route" validate:"required" example:"oral"`
+	Frequency    string   `json:"frequency" example:"once a day"`
+	DurationDays *int     `json:"duration_days" example:"5"`
+	Notes        string   `json:"notes" example:"With food"`
+} // @name VisitTreatmentCreatePayload
+
+func (t *VisitTreatmentCreatePayload) Bind(r *http.Request) error {
+	if t.TreatmentID == nil {
+		return errors.New("missing treatment_id property")
+	}
+
+	if t.Dosage == nil {
+		return errors.New("missing dosage property")
+	}
+
+	if t.Unit == "" {
+		return errors.New("missing unit property")
+	}
+
+	if t.Route == "" {
+		return errors.New("missing route property")
+	}
+
+	return validateVisitTreatment(t.Kind, t.Dosage, &t.Unit, &t.Route, &t.Frequency, t.DurationDays)
+}
+
+type VisitTreatmentUpdatePayload struct {
+	Kind         *string  `json:"kind" example:"prescribed" enums:"administered,prescribed"`
+	Dosage       *float64 `json:"dosage" example:"0.5"`
+	Unit         *string  `json:"unit" example:"ml"`
+	Route        *string  `json:"route" example:"oral"`
+	Frequency    *string  `json:"frequency" example:"once a day"`
+	DurationDays *int     `json:"duration_days" example:"5"` // 0 to remove the duration
+	Notes        *string  `json:"notes" example:"With food"`
+} // @name VisitTreatmentUpdatePayload
+
+// VisitTreatmentUpdateFields lists the fields each role can change on the
+// treatment of a visit
+var VisitTreatmentUpdateFields = map[uint][]string{
+	RoleAdmin:       {"kind", "dosage", "unit", "route", "frequency", "duration_days", "notes"},
+	RoleVeterinaire: {"kind", "dosage", "unit", "route", "frequency", "duration_days", "notes"},
+	RoleClient:      {},
+}
+
+func (t *VisitTreatmentUpdatePayload) Validate() error {
+	if t.Unit != nil && *t.Unit == "" {
+		return errors.New("empty unit property")
+	}
+
+	durationDays := t.DurationDays
+	if durationDays != nil && *durationDays == 0 {
+		durationDays = nil
+	}
+
+	return validateVisitTreatment(t.Kind, t.Dosage, t.Unit, t.Route, t.Frequency, durationDays)
+}
+
+func validateTreatment(category *string) error {
+	if category != nil && !helper.Contains(TreatmentCategories, *category) {
+		return errors.New("invalid category property, medication, vaccine, antiparasitic, procedure or other expected")
+	}
+
+	return nil
+}
+
+func validateVisitTreatment(kind *string, dosage *float64, unit *string, route *string, frequency *string, durationDays *int) error {
+	if kind != nil && *kind != TreatmentAdministered && *kind != TreatmentPrescribed {
+		return errors.New("invalid kind property, administered or prescribed expected")
+	}
+
+	if dosage != nil && *dosage <= 0 {
+		return errors.New("invalid dosage property, a positive number expected")
+	}
+
+	if unit != nil && len(*unit) > 20 {
+		return errors.New("invalid unit property, 20 characters at most")
+	}
+
+	if route != nil && !helper.Contains(TreatmentRoutes, *route) {
+		return errors.New("invalid route property, oral, topical, subcutaneous, intramuscular, intravenous, ophthalmic, otic, inhaled, rectal or other expected")
+	}
+
+	if frequency != nil && len(*frequency) > 100 {
+		return errors.New("invalid frequency property, 100 characters at most")
+	}
+
+	if durationDays != nil && (*durationDays <= 0 || *durationDays > 365) {
+		return errors.New("invalid duration_days property, 1 to 365 days expected")
+	}
+
+	return nil
+}
@@ -3,8 +3,14 @@ package model
 import (
 	"errors"
 	"net/http"
+	"regexp"
+	"strings"
 )
 
+// A French social security number: the sex, the year and month of birth, the
+// department (2A and 2B for Corsica), the town, the order and the key
+var socialSecurityNumberRegexp = regexp.MustCompile(`^[12][0-9]{4}(?:[0-9]{2}|2A|2B)[0-9]{8}$`)
+
 type UserCreatePayload struct {
 	Email     *string `json:"email" validate:"required,email" example:"admin@feldrise.com"`
 	FirstName *string `json:"first_name" validate:"required" example:"Victor"`
@@ -24,3 +30,49 @@ func (iup *UserCreatePayload) Bind(r *http.Request) error {
 
 	return nil
 }
+
+type UserUpdatePayload struct {
+	FirstName            *string `json:"first_name" example:"Victor"`
+	LastName             *string `json:"last_name" example:"DENIS"`
+	SocialSecurityNumber *string `json:"social_security_number" example:"1 85 05 78 006 084 36"`
+} // @name UserUpdatePayload
+
+// UserUpdateFields lists, for each role, the fields a user can change on
+// its own account. The email goes through /authentication/change-email, and
+// the social security number is only kept for the staff.
+var UserUpdateFields = map[uint][]string{
+	RoleAdmin:       {"first_name", "last_name", "social_security_number"},
+	RoleVeterinaire: {"first_name", "last_name", "social_security_number"},
+	RoleClient:      {"first_name", "last_name"},
+}
+
+// Validate checks the fields and removes the spaces of the social security
+// number
+func (up *UserUpdatePayload) Validate() error {
+	if up.FirstName != nil && strings.TrimSpace(*up.FirstName) == "" {
+		return errors.New("empty first_name property")
+	}
+
+	if up.LastName != nil && strings.TrimSpace(*up.LastName) == "" {
+		return errors.New("empty last_name property")
+	}
+
+	if up.SocialSecurityNumber != nil {
+		number := strings.ToUpper(strings.ReplaceAll(*up.SocialSecurityNumber, " ", ""))
+
+		if !socialSecurityNumberRegexp.MatchString(number) {
+			return errors.New("invalid social_security_number property, 15 characters expected")
+		}
+
+		*up.SocialSecurityNumber = number
+	}
+
+	return nil
+}
+
+type UserList struct {
+	Users   []*User `json:"users"`    // the users of the page
+	Total   int64   `json:"total"`    // the number of users matching the filters
+	Page    int     `json:"page"`     // the current page, starting at 1
+	PerPage int     `json:"per_page"` // the number of users per page
+} // @name UserList
@@ -1,6 +1,10 @@
 package model
 
 type UserProfile struct {
+	ID        uint   `json:"id"`         // @id
+	CreatedAt string `json:"created_at"` // the profile's creation date
+	UpdatedAt string `json:"updated_at"` // the profile's last update date
+
 	FirstName            *string `json:"first_name"`
 	LastName             *string `json:"last_name"`
 	SocialSecurityNumber *string `json:"social_security_number"`
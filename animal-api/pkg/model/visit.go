@@ -4,21 +4,160 @@ import (
 	"errors"
 	"net/http"
 	"time"
+
+	"feldrise.com/animal-api/helper"
+)
+
+// The statuses only change through the actions of VisitActions
+const (
+	VisitStatusRequested  = "requested" // booked by a client, waiting for the clinic to confirm it
+	VisitStatusConfirmed  = "confirmed"
+	VisitStatusCheckedIn  = "checked_in" // the animal arrived at the clinic
+	VisitStatusInProgress = "in_progress"
+	VisitStatusCompleted  = "completed"
+	VisitStatusCancelled  = "cancelled"
+	VisitStatusNoShow     = "no_show" // the animal never came
+)
+
+var VisitStatuses = []string{VisitStatusRequested, VisitStatusConfirmed, VisitStatusCheckedIn, VisitStatusInProgress, VisitStatusCompleted, VisitStatusCancelled, VisitStatusNoShow}
+
+// VisitInitialStatuses are the statuses a visit can be created with
+var VisitInitialStatuses = []string{VisitStatusRequested, VisitStatusConfirmed}
+
+// VisitFreeStatuses are the statuses of the visits which don't take the time
+// of their veterinarian
+var VisitFreeStatuses = []string{VisitStatusCancelled, VisitStatusNoShow}
+
+// VisitClosedStatuses are the final statuses, the visits can't be changed
+// anymore
+var VisitClosedStatuses = []string{VisitStatusCompleted, VisitStatusCancelled, VisitStatusNoShow}
+
+// MaxVisitsCalendarPeriod is the longest period the calendar of the visits
+// is listed on at once
+const MaxVisitsCalendarPeriod = 31 * 24 * time.Hour
+
+// The bounds of the duration of a visit, in minutes
+const (
+	DefaultVisitDuration = 30
+	MaxVisitDuration     = 8 * 60
+	maxVisitReasonLength = 500
 )
 
 type Visit struct {
-	Date time.Time `json:"date"`
-	Cat  *Cat      `json:"cat"`
+	ID        uint    `json:"id"`                   // @id
+	CreatedAt string  `json:"created_at"`           // the visit's creation date
+	UpdatedAt string  `json:"updated_at"`           // the visit's last update date
+	DeletedAt *string `json:"deleted_at,omitempty"` // when the visit was moved to the trash
+	Links     Links   `json:"links"`                // the visit's resources
+
+	Date     time.Time `json:"date"`
+	AnimalID uint      `json:"animal_id"`
+	Animal   *Animal   `json:"animal"`
+
+	Reason            string    `json:"reason"`                                                                                // the reason for consultation
+	VetID             *uint     `json:"vet_id"`                                                                                // the veterinarian assigned to the visit
+	AppointmentTypeID *uint     `json:"appointment_type_id"`                                                                   // the type the visit was booked as, if any
+	Duration          int       `json:"duration"`                                                                              // in minutes
+	Status            string    `json:"status" enums:"requested,confirmed,checked_in,in_progress,completed,cancelled,no_show"` // the visit's progress
+	Notes             SOAPNotes `json:"notes"`                                                                                 // the clinical notes of the visit
+
+	SeriesID     *uint `json:"series_id"`       // the series the visit was created in, if any
+	FollowUpOfID *uint `json:"follow_up_of_id"` // the visit this one follows up, if any
+
+	Treatments  []*VisitTreatment  `json:"treatments,omitempty"`  // the treatments given or prescribed, when requested
+	Transitions []*VisitTransition `json:"transitions,omitempty"` // the changes of status, the oldest first, when requested
 } // @name Visit
 
+// SOAPNotes are the clinical notes of a visit, in the SOAP format
+type SOAPNotes struct {
+	Subjective string `json:"subjective"` // the history and the owner's observations
+	Objective  string `json:"objective"`  // the examination findings
+	Assessment string `json:"assessment"` // the diagnosis
+	Plan       string `json:"plan"`       // the treatment and the follow-up
+} // @name SOAPNotes
+
+type SOAPNotesPayload struct {
+	Subjective *string `json:"subjective" example:"Eats less since a week"`
+	Objective  *string `json:"objective" example:"Dehydration, 39.4 °C"`
+	Assessment *string `json:"assessment" example:"Suspected renal failure"`
+	Plan       *string `json:"plan" example:"Blood test, fluids, control in 7 days"`
+} // @name SOAPNotesPayload
+
+// Apply copies the notes given in the payload, leaving the others unchanged
+func (p *SOAPNotesPayload) Apply(notes *SOAPNotes) {
+	if p.Subjective != nil {
+		notes.Subjective = *p.Subjective
+	}
+
+	if p.Objective != nil {
+		notes.Objective = *p.Objective
+	}
+
+	if p.Assessment != nil {
+		notes.Assessment = *p.Assessment
+	}
+
+	if p.Plan != nil {
+		notes.Plan = *p.Plan
+	}
+}
+
 type VisitCreatePayload struct {
-	Date *time.Time `json:"date" validate:"required" example:"2021-01-01T00:00:00Z"`
+	Date     *time.Time        `json:"date" validate:"required" example:"2021-01-01T00:00:00Z"`
+	Reason   string            `json:"reason" validate:"required" example:"Annual vaccination"`
+	VetID    *uint             `json:"vet_id" example:"2"`                                     // a user with the veterinaire role
+	Duration *int              `json:"duration" example:"30"`                                  // in minutes, 30 by default
+	Status   *string           `json:"status" example:"confirmed" enums:"requested,confirmed"` // confirmed by default
+	Notes    *SOAPNotesPayload `json:"notes"`
 } // @name VisitCreatePayload
 
-func (v VisitCreatePayload) Bind(r *http.Request) error {
+func (v *VisitCreatePayload) Bind(r *http.Request) error {
 	if v.Date == nil {
 		return errors.New("missing date property")
 	}
 
+	if v.Reason == "" {
+		return errors.New("missing reason property")
+	}
+
+	return validateVisit(&v.Reason, v.Duration, v.Status)
+}
+
+type VisitUpdatePayload struct {
+	Date     *time.Time        `json:"date" example:"2021-01-01T00:00:00Z"`
+	Reason   *string           `json:"reason" example:"Annual vaccination"`
+	VetID    *uint             `json:"vet_id" example:"2"` // a user with the veterinaire role, 0 to unassign the visit
+	Duration *int              `json:"duration" example:"30"`
+	Notes    *SOAPNotesPayload `json:"notes"` // only the notes given are changed
+} // @name VisitUpdatePayload
+
+// VisitUpdateFields lists the fields each role can change on a visit
+var VisitUpdateFields = map[uint][]string{
+	RoleAdmin:       {"date", "reason", "vet_id", "duration", "notes"},
+	RoleVeterinaire: {"date", "reason", "vet_id", "duration", "notes"},
+	RoleClient:      {},
+}
+
+func (v *VisitUpdatePayload) Validate() error {
+	if v.Reason != nil && *v.Reason == "" {
+		return errors.New("empty reason property")
+	}
+
+	return validateVisit(v.Reason, v.Duration, nil)
+}
+
+func validateVisit(reason *string, duration *int, status *string) error {
+	if reason != nil && len([]rune(*reason)) > maxVisitReasonLength {
+		return errors.New("invalid reason property, 500 characters at most")
+	}
+
+	if duration != nil && (*duration <= 0 || *duration > MaxVisitDuration) {
+		return errors.New("invalid duration property, 1 to 480 minutes expected")
+	}
+
+	if status != nil && !helper.Contains(VisitInitialStatuses, *status) {
+		return errors.New("invalid status property, requested or confirmed expected")
+	}
+
 	return nil
 }
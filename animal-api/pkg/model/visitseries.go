@@ -0,0 +1,49 @@
+package model
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// The visits of a series an update or a cancellation applies to
+const (
+	VisitScopeThis      = "this"
+	VisitScopeFollowing = "following" // the visit and the next open visits of its series
+)
+
+// VisitSeries is a visit repeated on a fixed cadence, like the checks of a
+// chronic disease
+type VisitSeries struct {
+	ID        uint   `json:"id"`         // @id
+	CreatedAt string `json:"created_at"` // the series' creation date
+	Links     Links  `json:"links"`      // the series' resources
+
+	AnimalID   uint     `json:"animal_id"`
+	Recurrence string   `json:"recurrence"` // the RRULE the visits were created from
+	Visits     []*Visit `json:"visits"`
+} // @name VisitSeries
+
+type VisitSeriesCreatePayload struct {
+	Date       *time.Time `json:"date" validate:"required" example:"2021-01-04T09:00:00+01:00"` // the first visit
+	Reason     string     `json:"reason" validate:"required" example:"Glycemia check"`
+	VetID      *uint      `json:"vet_id" example:"2"`                                                      // a user with the veterinaire role
+	Duration   *int       `json:"duration" example:"30"`                                                   // in minutes, 30 by default
+	Recurrence string     `json:"recurrence" validate:"required" example:"FREQ=WEEKLY;INTERVAL=2;COUNT=6"` // FREQ (DAILY, WEEKLY, MONTHLY or YEARLY), INTERVAL, and COUNT or UNTIL
+} // @name VisitSeriesCreatePayload
+
+func (v *VisitSeriesCreatePayload) Bind(r *http.Request) error {
+	if v.Date == nil {
+		return errors.New("missing date property")
+	}
+
+	if v.Reason == "" {
+		return errors.New("missing reason property")
+	}
+
+	if v.Recurrence == "" {
+		return errors.New("missing recurrence property")
+	}
+
+	return validateVisit(&v.Reason, v.Duration, nil)
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	"feldrise.com/animal-api/helper"
+)
+
+// The actions change the status of a visit, each has its own endpoint
+const (
+	VisitActionConfirm  = "confirm"
+	VisitActionCheckIn  = "check-in"
+	VisitActionStart    = "start"
+	VisitActionComplete = "complete"
+	VisitActionCancel   = "cancel"
+	VisitActionNoShow   = "no-show"
+)
+
+// VisitAction is a change of status of a visit, each role can take it from
+// the statuses listed for it
+type VisitAction struct {
+	To             string
+	From           map[uint][]string
+	ReasonRequired bool
+	Series         bool // can be taken on the following visits of the series too
+}
+
+var VisitActions = map[string]VisitAction{
+	VisitActionConfirm: {
+		To: VisitStatusConfirmed,
+		From: map[uint][]string{
+			RoleAdmin:       {VisitStatusRequested},
+			RoleVeterinaire: {VisitStatusRequested},
+		},
+	},
+	VisitActionCheckIn: {
+		To: VisitStatusCheckedIn,
+		From: map[uint][]string{
+			RoleAdmin:       {VisitStatusConfirmed},
+			RoleVeterinaire: {VisitStatusConfirmed},
+		},
+	},
+	VisitActionStart: {
+		To: VisitStatusInProgress,
+		From: map[uint][]string{
+			RoleAdmin:       {VisitStatusConfirmed, VisitStatusCheckedIn},
+			RoleVeterinaire: {VisitStatusConfirmed, VisitStatusCheckedIn},
+		},
+	},
+	VisitActionComplete: {
+		To: VisitStatusCompleted,
+		From: map[uint][]string{
+			RoleAdmin:       {VisitStatusInProgress},
+			RoleVeterinaire: {VisitStatusInProgress},
+		},
+	},
+	// The clients can only cancel their visits before coming
+	VisitActionCancel: {
+		To: VisitStatusCancelled,
+		From: map[uint][]string{
+			RoleAdmin:       {VisitStatusRequested, VisitStatusConfirmed, VisitStatusCheckedIn},
+			RoleVeterinaire: {VisitStatusRequested, VisitStatusConfirmed, VisitStatusCheckedIn},
+			RoleClient:      {VisitStatusRequested, VisitStatusConfirmed},
+		},
+		ReasonRequired: true,
+		Series:         true,
+	},
+	VisitActionNoShow: {
+		To: VisitStatusNoShow,
+		From: map[uint][]string{
+			RoleAdmin:       {VisitStatusConfirmed},
+			RoleVeterinaire: {VisitStatusConfirmed},
+		},
+	},
+}
+
+// Allowed returns whether the role can take the action on a visit of the
+// status
+func (a *VisitAction) Allowed(role uint, status string) bool {
+	return helper.Contains(a.From[role], status)
+}
+
+// VisitTransition records a change of status of a visit
+type VisitTransition struct {
+	ID        uint   `json:"id"`         // @id
+	CreatedAt string `json:"created_at"` // when the status changed
+
+	UserID *uint  `json:"user_id"` // who changed the status, null once the user is deleted
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Reason string `json:"reason"`
+} // @name VisitTransition
+
+// MaxFollowUpDays is the longest a follow-up can be scheduled after a visit
+const MaxFollowUpDays = 365
+
+type VisitTransitionPayload struct {
+	Reason       string `json:"reason" example:"The owner is ill"` // required to cancel a visit
+	FollowUpDays *int   `json:"follow_up_days" example:"14"`       // to complete a visit, schedules a follow-up this many days later
+} // @name VisitTransitionPayload
+
+func (v *VisitTransitionPayload) Validate(action *VisitAction) error {
+	if action.ReasonRequired && v.Reason == "" {
+		return errors.New("missing reason property")
+	}
+
+	if v.FollowUpDays != nil && action.To != VisitStatusCompleted {
+		return errors.New("invalid follow_up_days property, only given to complete a visit")
+	}
+
+	if v.FollowUpDays != nil && (*v.FollowUpDays < 1 || *v.FollowUpDays > MaxFollowUpDays) {
+		return fmt.Errorf("invalid follow_up_days property, 1 to %d expected", MaxFollowUpDays)
+	}
+
+	return validateVisit(&v.Reason, nil, nil)
+}
+
+// FollowUpReason returns the reason of the follow-up of a visit
+func FollowUpReason(reason string) string {
+	followUp := []rune("Follow-up: " + reason)
+
+	if len(followUp) > maxVisitReasonLength {
+		followUp = followUp[:maxVisitReasonLength]
+	}
+
+	return string(followUp)
+}
@@ -0,0 +1,190 @@
+package model
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	BucketDay   = "day"
+	BucketWeek  = "week"
+	BucketMonth = "month"
+)
+
+type Vital struct {
+	ID        uint   `json:"id"`         // @id
+	CreatedAt string `json:"created_at"` // the measurement's creation date
+	UpdatedAt string `json:"updated_at"` // the measurement's last update date
+	Links     Links  `json:"links"`      // the measurement's resources
+
+	AnimalID   uint      `json:"animal_id"`
+	VisitID    *uint     `json:"visit_id"`    // the visit the measurement was taken at, if any
+	RecordedAt time.Time `json:"recorded_at"` // when the measurement was taken
+
+	Weight             *float64 `json:"weight"`               // in kilograms
+	Temperature        *float64 `json:"temperature"`          // in degrees Celsius
+	HeartRate          *int     `json:"heart_rate"`           // in beats per minute
+	RespiratoryRate    *int     `json:"respiratory_rate"`     // in breaths per minute
+	BodyConditionScore *int     `json:"body_condition_score"` // from 1 (emaciated) to 9 (obese)
+} // @name Vital
+
+type VitalCreatePayload struct {
+	VisitID    *uint      `json:"visit_id" example:"12"`                      // the visit the measurement was taken at
+	RecordedAt *time.Time `json:"recorded_at" example:"2021-01-01T10:30:00Z"` // the date of the visit or now by default
+
+	Weight             *float64 `json:"weight" example:"4.2"`
+	Temperature        *float64 `json:"temperature" example:"38.5"`
+	HeartRate          *int     `json:"heart_rate" example:"160"`
+	RespiratoryRate    *int     `json:"respiratory_rate" example:"24"`
+	BodyConditionScore *int     `json:"body_condition_score" example:"5"`
+} // @name VitalCreatePayload
+
+func (v *VitalCreatePayload) Bind(r *http.Request) error {
+	if v.Weight == nil && v.Temperature == nil && v.HeartRate == nil && v.RespiratoryRate == nil && v.BodyConditionScore == nil {
+		return errors.New("missing measurement, at least one of weight, temperature, heart_rate, respiratory_rate or body_condition_score expected")
+	}
+
+	return validateVitals(v.RecordedAt, v.Weight, v.Temperature, v.HeartRate, v.RespiratoryRate, v.BodyConditionScore)
+}
+
+type VitalUpdatePayload struct {
+	RecordedAt *time.Time `json:"recorded_at" example:"2021-01-01T10:30:00Z"`
+
+	Weight             *float64 `json:"weight" example:"4.2"`
+	Temperature        *float64 `json:"temperature" example:"38.5"`
+	HeartRate          *int     `json:"heart_rate" example:"160"`
+	RespiratoryRate    *int     `json:"respiratory_rate" example:"24"`
+	BodyConditionScore *int     `json:"body_condition_score" example:"5"`
+} // @name VitalUpdatePayload
+
+// VitalUpdateFields lists the fields each role can change on a measurement
+var VitalUpdateFields = map[uint][]string{
+	RoleAdmin:       {"recorded_at", "weight", "temperature", "heart_rate", "respiratory_rate", "body_condition_score"},
+	RoleVeterinaire: {"recorded_at", "weight", "temperature", "heart_rate", "respiratory_rate", "body_condition_score"},
+	RoleClient:      {},
+}
+
+func (v *VitalUpdatePayload) Validate() error {
+	return validateVitals(v.RecordedAt, v.Weight, v.Temperature, v.HeartRate, v.RespiratoryRate, v.BodyConditionScore)
+}
+
+type VitalsPoint struct {
+	Date               time.Time `json:"date"`                 // the start of the bucket
+	Count              int       `json:"count"`                // the measurements in the bucket
+	Weight             *float64  `json:"weight"`               // the average of the bucket, null without measurement
+	Temperature        *float64  `json:"temperature"`          // the average of the bucket, null without measurement
+	HeartRate          *float64  `json:"heart_rate"`           // the average of the bucket, null without measurement
+	RespiratoryRate    *float64  `json:"respiratory_rate"`     // the average of the bucket, null without measurement
+	BodyConditionScore *float64  `json:"body_condition_score"` // the average of the bucket, null without measurement
+} // @name VitalsPoint
+
+type WeightLossAlert struct {
+	FromWeight  float64   `json:"from_weight"`  // the highest weight of the period, in kilograms
+	FromDate    time.Time `json:"from_date"`    // when the highest weight was measured
+	ToWeight    float64   `json:"to_weight"`    // the last weight, in kilograms
+	ToDate      time.Time `json:"to_date"`      // when the last weight was measured
+	LossPercent float64   `json:"loss_percent"` // the weight lost between both measurements
+	Threshold   float64   `json:"threshold"`    // the loss percentage raising the alert
+	PeriodDays  int       `json:"period_days"`  // the period the loss is looked for on
+} // @name WeightLossAlert
+
+type VitalsSeries struct {
+	Bucket     string           `json:"bucket" enums:"day,week,month"` // the duration each point averages
+	From       time.Time        `json:"from"`
+	To         time.Time        `json:"to"`
+	Points     []VitalsPoint    `json:"points"`      // the buckets having measurements, the oldest first
+	WeightLoss *WeightLossAlert `json:"weight_loss"` // set when the animal lost weight rapidly
+} // @name VitalsSeries
+
+// WeightMeasure is a weight and the date it was measured at
+type WeightMeasure struct {
+	Weight float64
+	Date   time.Time
+}
+
+// SeriesBucket returns the bucket keeping a series between from and to
+// readable on a chart, a hundred points at most
+func SeriesBucket(from time.Time, to time.Time) string {
+	span := to.Sub(from)
+
+	switch {
+	case span <= 100*24*time.Hour:
+		return BucketDay
+	case span <= 100*7*24*time.Hour:
+		return BucketWeek
+	default:
+		return BucketMonth
+	}
+}
+
+// DetectWeightLoss compares the last weight with the highest one measured
+// during the period before it. An alert is returned when the loss reaches
+// the threshold, in percent. The measures must be sorted, the oldest first.
+func DetectWeightLoss(measures []WeightMeasure, threshold float64, period time.Duration) *WeightLossAlert {
+	if len(measures) < 2 || threshold <= 0 {
+		return nil
+	}
+
+	last := measures[len(measures)-1]
+	highest := last
+
+	for _, measure := range measures[:len(measures)-1] {
+		if last.Date.Sub(measure.Date) > period {
+			continue
+		}
+
+		if measure.Weight > highest.Weight {
+			highest = measure
+		}
+	}
+
+	if highest.Weight <= 0 {
+		return nil
+	}
+
+	loss := (highest.Weight - last.Weight) / highest.Weight * 100
+
+	if loss < threshold {
+		return nil
+	}
+
+	return &WeightLossAlert{
+		FromWeight:  highest.Weight,
+		FromDate:    highest.Date,
+		ToWeight:    last.Weight,
+		ToDate:      last.Date,
+		LossPercent: loss,
+		Threshold:   threshold,
+		PeriodDays:  int(period.Hours() / 24),
+	}
+}
+
+func validateVitals(recordedAt *time.Time, weight *float64, temperature *float64, heartRate *int, respiratoryRate *int, bodyConditionScore *int) error {
+	// A few minutes of tolerance for the clocks of the devices
+	if recordedAt != nil && recordedAt.After(time.Now().Add(5*time.Minute)) {
+		return errors.New("invalid recorded_at property, the date is in the future")
+	}
+
+	if weight != nil && (*weight <= 0 || *weight > 2000) {
+		return errors.New("invalid weight property, kilograms expected")
+	}
+
+	if temperature != nil && (*temperature < 20 || *temperature > 50) {
+		return errors.New("invalid temperature property, degrees Celsius expected")
+	}
+
+	if heartRate != nil && (*heartRate <= 0 || *heartRate > 1000) {
+		return errors.New("invalid heart_rate property, beats per minute expected")
+	}
+
+	if respiratoryRate != nil && (*respiratoryRate <= 0 || *respiratoryRate > 300) {
+		return errors.New("invalid respiratory_rate property, breaths per minute expected")
+	}
+
+	if bodyConditionScore != nil && (*bodyConditionScore < 1 || *bodyConditionScore > 9) {
+		return errors.New("invalid body_condition_score property, 1 to 9 expected")
+	}
+
+	return nil
+}
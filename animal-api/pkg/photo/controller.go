@@ -0,0 +1,104 @@
+package photo
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"feldrise.com/animal-api/helper"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"feldrise.com/animal-api/pkg/storage"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// The lifetime of the URLs the photos are redirected to, when the storage
+// makes them
+const presignedLifetime = time.Hour
+
+// Get godoc
+// @Summary Get a photo file
+// @Description Serve a size of a photo. The URL comes signed in the urls of the photo, it works without authentication until it expires.
+// @ID get-photo-file
+// @Tags photos
+// @Produce jpeg
+// @Param id path int true "Photo ID"
+// @Param size path string true "large, medium or thumbnail"
+// @Param expires query int true "expiration of the signature, as a Unix timestamp"
+// @Param signature query string true "signature of the URL"
+// @Success 200 {file} file "ok"
+// @Success 302 "redirection to the storage, when it serves the files"
+// @Failure 403 {string} string "invalid or expired signature"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /photos/{id}/{size} [get]
+func (config *Config) Get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	size := chi.URLParam(r, "size")
+	query := r.URL.Query()
+
+	if !model.CheckSignature("/photos/"+id+"/"+size, query.Get("expires"), query.Get("signature")) {
+		render.Render(w, r, errors.ErrForbidden("invalid or expired signature"))
+		return
+	}
+
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil || !helper.Contains(model.PhotoSizes, size) {
+		render.Render(w, r, errors.ErrNotFound())
+		return
+	}
+
+	dbPhoto, err := config.PhotosRepository.FindByID(uint(idUint))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if dbPhoto == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return
+	}
+
+	key := dbPhoto.StorageKey(size)
+
+	// The backends which can sign their own URLs serve the file themselves
+	url, err := config.Storage.PresignedURL(key, presignedLifetime)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if url != "" {
+		http.Redirect(w, r, url, http.StatusFound)
+		return
+	}
+
+	file, err := config.Storage.Open(key)
+
+	if err == storage.ErrNotFound {
+		render.Render(w, r, errors.ErrNotFound())
+		return
+	}
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+	defer file.Close()
+
+	// The files never change, a new file gets a new key
+	w.Header().Set("Cache-Control", "private, max-age=86400, immutable")
+	w.Header().Set("Content-Type", "image/jpeg")
+
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, size+".jpg", dbPhoto.CreatedAt, seeker)
+		return
+	}
+
+	io.Copy(w, file)
+}
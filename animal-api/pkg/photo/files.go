@@ -0,0 +1,127 @@
+package photo
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"feldrise.com/animal-api/config"
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/helper"
+	"feldrise.com/animal-api/pkg/model"
+)
+
+const (
+	// The lock of a key outlasts the uploads of its sizes, it only expires
+	// by itself when the server holding it stopped
+	keyLockLifetime = 30 * time.Minute
+	// How long to wait for another request to release the lock of a key
+	keyLockWait     = 10 * time.Second
+	keyLockInterval = 200 * time.Millisecond
+)
+
+var ErrKeyLocked = errors.New("the same photo is being stored or deleted, try again later")
+
+// Create stores the sizes of the photo with store, unless another photo of the
+// same file already did, then creates the photo. Its key is locked meanwhile
+// so DeleteFiles can't remove the files in between.
+func Create(configuration *config.Config, photo *dbmodel.Photo, store func() error) error {
+	return lockKey(configuration, photo.Key, func() error {
+		// The large size is stored last, so the sizes are all there when it is
+		stored, err := configuration.Storage.Exists(photo.StorageKey(model.PhotoSizeLarge))
+
+		if err != nil {
+			return err
+		}
+
+		if !stored {
+			if err := store(); err != nil {
+				deleteFiles(configuration, photo)
+				return err
+			}
+		}
+
+		if _, err := configuration.PhotosRepository.Create(photo); err != nil {
+			deleteFiles(configuration, photo)
+			return err
+		}
+
+		return nil
+	})
+}
+
+// DeleteFiles removes the sizes of the deleted photos from the storage,
+// unless another photo of the same file still uses them. The photos are
+// already gone so the failures are only logged.
+func DeleteFiles(configuration *config.Config, photos ...*dbmodel.Photo) {
+	for _, photo := range photos {
+		err := lockKey(configuration, photo.Key, func() error {
+			deleteFiles(configuration, photo)
+			return nil
+		})
+
+		if err != nil {
+			log.Printf("Error locking the photos of %s: %s", photo.Key, err)
+		}
+	}
+}
+
+// Private
+
+// lockKey runs fn while holding the lock of the key, so its files aren't
+// stored and deleted at the same time by different requests. ErrKeyLocked is
+// returned when another request keeps it.
+func lockKey(configuration *config.Config, key string, fn func() error) error {
+	token, err := helper.RandomToken(16)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(keyLockWait)
+
+	for {
+		locked, err := configuration.PhotosRepository.LockKey(key, token, keyLockLifetime)
+		if err != nil {
+			return err
+		}
+
+		if locked {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			return ErrKeyLocked
+		}
+
+		time.Sleep(keyLockInterval)
+	}
+
+	defer func() {
+		if err := configuration.PhotosRepository.UnlockKey(key, token); err != nil {
+			log.Printf("Error unlocking the photos of %s: %s", key, err)
+		}
+	}()
+
+	return fn()
+}
+
+// deleteFiles removes the sizes of the photo unless another photo uses them,
+// the key must be locked
+func deleteFiles(configuration *config.Config, photo *dbmodel.Photo) {
+	count, err := configuration.PhotosRepository.CountByKey(photo.Key)
+
+	if err != nil {
+		log.Printf("Error counting the photos of %s: %s", photo.Key, err)
+		return
+	}
+
+	if count > 0 {
+		return
+	}
+
+	for _, size := range model.PhotoSizes {
+		if err := configuration.Storage.Delete(photo.StorageKey(size)); err != nil {
+			log.Printf("Error deleting the photo %s: %s", photo.StorageKey(size), err)
+		}
+	}
+}
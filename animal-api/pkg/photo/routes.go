@@ -0,0 +1,20 @@
+package photo
+
+import (
+	"feldrise.com/animal-api/config"
+	"github.com/go-chi/chi/v5"
+)
+
+func New(configuration *config.Config) *Config {
+	return &Config{configuration}
+}
+
+func (config *Config) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	// Signed URLs, they work without authentication so the photos can be
+	// displayed by any image view
+	router.Get("/{id}/{size}", config.Get)
+
+	return router
+}
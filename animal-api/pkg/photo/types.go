@@ -1,4 +1,4 @@
-package cat
+package photo
 
 import "feldrise.com/animal-api/config"
 
@@ -0,0 +1,341 @@
+package schedule
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/render"
+)
+
+// The free slots are searched on a week when no end is given
+const defaultAvailabilityPeriod = 7 * 24 * time.Hour
+
+// GetAvailability godoc
+// @Summary Get the free slots
+// @Description Get the slots an appointment of the type can be booked on, the earliest first. A slot is free when it is within the working hours of the veterinarian, outside their time off and doesn't overlap any of their visits. The slots start every 15 minutes.
+// @ID get-availability
+// @Tags schedule
+// @Param appointment_type query int true "Appointment type ID"
+// @Param vet query int false "only the slots of this veterinarian"
+// @Param from query string false "only the slots starting after this date, RFC 3339, now by default"
+// @Param to query string false "only the slots starting before this date, RFC 3339, a week after from by default and at most 31 days after it"
+// @Success 200 {array} Slot "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/availability [get]
+func (config *Config) GetAvailability(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	typeID, err := strconv.ParseUint(query.Get("appointment_type"), 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid appointment_type parameter")))
+		return
+	}
+
+	dbAppointmentType, err := config.AppointmentTypesRepository.FindByID(uint(typeID))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if dbAppointmentType == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return
+	}
+
+	from, err := queryTime(query.Get("from"))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	to, err := queryTime(query.Get("to"))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if now := time.Now(); from == nil || from.Before(now) {
+		from = &now
+	}
+
+	if to == nil {
+		end := from.Add(defaultAvailabilityPeriod)
+		to = &end
+	}
+
+	if to.Sub(*from) > model.MaxAvailabilityPeriod {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("the period can't be longer than %d days", model.MaxAvailabilityPeriod/(24*time.Hour))))
+		return
+	}
+
+	var dbVets []*dbmodel.User
+
+	if vet := query.Get("vet"); vet != "" {
+		vetID, err := strconv.ParseUint(vet, 10, 64)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid vet parameter")))
+			return
+		}
+
+		dbVet, err := config.UserRepository.FindByID(uint(vetID), false)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		if dbVet == nil || dbVet.RoleID != model.RoleVeterinaire || dbVet.SuspendedAt != nil {
+			render.Render(w, r, errors.ErrNotFound())
+			return
+		}
+
+		dbVets = append(dbVets, dbVet)
+	} else if dbVets, err = config.findVets(); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	duration := time.Duration(dbAppointmentType.Duration) * time.Minute
+	slots := []model.Slot{}
+
+	for _, dbVet := range dbVets {
+		periods, err := config.workingPeriods(dbVet.ID)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		if len(periods) == 0 {
+			continue
+		}
+
+		busy, err := config.busyRanges(dbVet.ID, *from, to.Add(duration))
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		for _, slot := range model.FreeSlots(*from, *to, config.Location, periods, busy, duration) {
+			slots = append(slots, model.Slot{VetID: dbVet.ID, Start: slot.Start, End: slot.End})
+		}
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].Start.Before(slots[j].Start)
+	})
+
+	render.JSON(w, r, slots)
+}
+
+// Book godoc
+// @Summary Book an appointment
+// @Description Book a visit of the animal on a free slot. The visits booked by clients are requested and wait for the clinic to confirm them, the ones booked by the staff are confirmed. When no veterinarian is given, the first one available is assigned. The animal must belong to the client.
+// @ID book-appointment
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Param request body AppointmentCreatePayload true "Appointment info"
+// @Success 201 {object} Visit "created"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "no veterinarian is available at this time"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/appointments [post]
+func (config *Config) Book(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	data := &model.AppointmentCreatePayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	dbAnimal, err := config.AnimalsRepository.FindByID(*data.AnimalID, dbmodel.AnimalsFilterForUser(loggedUser), nil)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if dbAnimal == nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("unknown animal_id property")))
+		return
+	}
+
+	dbAppointmentType, err := config.AppointmentTypesRepository.FindByID(*data.AppointmentTypeID)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if dbAppointmentType == nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("unknown appointment_type_id property")))
+		return
+	}
+
+	var dbVets []*dbmodel.User
+
+	if data.VetID != nil {
+		dbVet, err := config.UserRepository.FindByID(*data.VetID, false)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		if dbVet == nil || dbVet.RoleID != model.RoleVeterinaire || dbVet.SuspendedAt != nil {
+			render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("unknown vet_id property")))
+			return
+		}
+
+		dbVets = append(dbVets, dbVet)
+	} else if dbVets, err = config.findVets(); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	reason := data.Reason
+	if reason == "" {
+		reason = dbAppointmentType.Name
+	}
+
+	status := model.VisitStatusConfirmed
+	if loggedUser.RoleID == model.RoleClient {
+		status = model.VisitStatusRequested
+	}
+
+	slot := model.TimeRange{Start: *data.Start}
+	slot.End = slot.Start.Add(time.Duration(dbAppointmentType.Duration) * time.Minute)
+
+	for _, dbVet := range dbVets {
+		available, err := config.available(dbVet.ID, slot)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		if !available {
+			continue
+		}
+
+		vetID := dbVet.ID
+		appointmentTypeID := dbAppointmentType.ID
+
+		// The visits are checked again inside the transaction, another
+		// booking may have taken the slot since
+		dbVisit, err := config.VisitsRepository.Book(&dbmodel.Visit{
+			Date:              slot.Start,
+			AnimalID:          dbAnimal.ID,
+			Reason:            reason,
+			VetID:             &vetID,
+			AppointmentTypeID: &appointmentTypeID,
+			Duration:          dbAppointmentType.Duration,
+			Status:            status,
+		})
+
+		if err == dbmodel.ErrVisitConflict || err == dbmodel.ErrVisitVetUnavailable {
+			continue
+		}
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		render.Status(r, http.StatusCreated)
+		render.JSON(w, r, dbVisit.ToModel())
+		return
+	}
+
+	render.Render(w, r, errors.ErrConflict("no veterinarian is available at this time"))
+}
+
+// Private
+
+func (config *Config) workingPeriods(vetID uint) ([]model.WorkingPeriod, error) {
+	dbHours, err := config.WorkingHoursRepository.FindByVet(vetID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	periods := make([]model.WorkingPeriod, 0, len(dbHours))
+
+	for _, dbHour := range dbHours {
+		periods = append(periods, dbHour.Period())
+	}
+
+	return periods, nil
+}
+
+// busyRanges returns the time off and the visits of the veterinarian
+// overlapping the period
+func (config *Config) busyRanges(vetID uint, from time.Time, to time.Time) ([]model.TimeRange, error) {
+	dbTimeOffs, err := config.TimeOffRepository.FindAll(&dbmodel.TimeOffFilter{VetID: vetID, From: &from, To: &to})
+
+	if err != nil {
+		return nil, err
+	}
+
+	dbVisits, err := config.VisitsRepository.FindAll(&dbmodel.VisitsFilter{VetID: &vetID, From: &from, To: &to, Active: true}, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	busy := make([]model.TimeRange, 0, len(dbTimeOffs)+len(dbVisits))
+
+	for _, dbTimeOff := range dbTimeOffs {
+		busy = append(busy, model.TimeRange{Start: dbTimeOff.Start, End: dbTimeOff.End})
+	}
+
+	for _, dbVisit := range dbVisits {
+		busy = append(busy, model.TimeRange{Start: dbVisit.Date, End: dbVisit.Date.Add(time.Duration(dbVisit.Duration) * time.Minute)})
+	}
+
+	return busy, nil
+}
+
+// available returns whether the slot is within the working hours of the
+// veterinarian and outside their time off, the visits are checked by Book
+func (config *Config) available(vetID uint, slot model.TimeRange) (bool, error) {
+	periods, err := config.workingPeriods(vetID)
+
+	if err != nil {
+		return false, err
+	}
+
+	if !model.WithinWorkingHours(slot, config.Location, periods) {
+		return false, nil
+	}
+
+	dbTimeOffs, err := config.TimeOffRepository.FindAll(&dbmodel.TimeOffFilter{VetID: vetID, From: &slot.Start, To: &slot.End})
+
+	if err != nil {
+		return false, err
+	}
+
+	return len(dbTimeOffs) == 0, nil
+}
@@ -0,0 +1,257 @@
+package schedule
+
+import (
+	"net/http"
+	"strconv"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/helper"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// GetAppointmentTypes godoc
+// @Summary Get the appointment types
+// @Description Get the kinds of visits which can be booked, with their duration
+// @ID get-appointment-types
+// @Tags schedule
+// @Success 200 {array} AppointmentType "ok"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/appointment-types [get]
+func (config *Config) GetAppointmentTypes(w http.ResponseWriter, r *http.Request) {
+	dbAppointmentTypes, err := config.AppointmentTypesRepository.FindAll()
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	appointmentTypes := make([]model.AppointmentType, 0, len(dbAppointmentTypes))
+
+	for _, dbAppointmentType := range dbAppointmentTypes {
+		appointmentTypes = append(appointmentTypes, *dbAppointmentType.ToModel())
+	}
+
+	render.JSON(w, r, appointmentTypes)
+}
+
+// GetAppointmentType godoc
+// @Summary Get an appointment type
+// @Description Get a kind of visit which can be booked
+// @ID get-appointment-type
+// @Tags schedule
+// @Param id path int true "Appointment type ID"
+// @Success 200 {object} AppointmentType "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/appointment-types/{id} [get]
+func (config *Config) GetAppointmentType(w http.ResponseWriter, r *http.Request) {
+	dbAppointmentType := config.findAppointmentType(w, r)
+
+	if dbAppointmentType == nil {
+		return
+	}
+
+	render.JSON(w, r, dbAppointmentType.ToModel())
+}
+
+// CreateAppointmentType godoc
+// @Summary Create an appointment type
+// @Description Create a kind of visit the clients can book. Only available to administrators.
+// @ID create-appointment-type
+// @Tags schedule
+// @Accept json
+// @Produce json
+// @Param request body AppointmentTypeCreatePayload true "Appointment type info"
+// @Success 201 {object} AppointmentType "created"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/appointment-types [post]
+func (config *Config) CreateAppointmentType(w http.ResponseWriter, r *http.Request) {
+	data := &model.AppointmentTypeCreatePayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	dbAppointmentType, err := config.AppointmentTypesRepository.Create(&dbmodel.AppointmentType{
+		Name:        data.Name,
+		Description: data.Description,
+		Duration:    data.Duration,
+	})
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, dbAppointmentType.ToModel())
+}
+
+// UpdateAppointmentType godoc
+// @Summary Update an appointment type
+// @Description Update a kind of visit, the visits already booked are left unchanged. Only available to administrators.
+// @ID update-appointment-type
+// @Tags schedule
+// @Param id path int true "Appointment type ID"
+// @Accept json
+// @Produce json
+// @Param request body AppointmentTypeUpdatePayload true "Appointment type info"
+// @Success 200 {object} AppointmentType "ok"
+// @Failure 400 {object} ErrResponse "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/appointment-types/{id} [put]
+func (config *Config) UpdateAppointmentType(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	dbAppointmentType := config.findAppointmentType(w, r)
+
+	if dbAppointmentType == nil {
+		return
+	}
+
+	data := &model.AppointmentTypeUpdatePayload{}
+
+	forbidden, err := helper.DecodeAllowed(r.Body, model.AppointmentTypeUpdateFields[loggedUser.RoleID], data)
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if len(forbidden) > 0 {
+		render.Render(w, r, errors.ErrForbiddenFields(forbidden))
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if data.Name != nil {
+		dbAppointmentType.Name = *data.Name
+	}
+
+	if data.Description != nil {
+		dbAppointmentType.Description = *data.Description
+	}
+
+	if data.Duration != nil {
+		dbAppointmentType.Duration = *data.Duration
+	}
+
+	dbAppointmentType, err = config.AppointmentTypesRepository.Update(dbAppointmentType)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, dbAppointmentType.ToModel())
+}
+
+// DeleteAppointmentType godoc
+// @Summary Delete an appointment type
+// @Description Stop offering a kind of visit, the visits already booked keep it. Only available to administrators.
+// @ID delete-appointment-type
+// @Tags schedule
+// @Param id path int true "Appointment type ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/appointment-types/{id} [delete]
+func (config *Config) DeleteAppointmentType(w http.ResponseWriter, r *http.Request) {
+	dbAppointmentType := config.findAppointmentType(w, r)
+
+	if dbAppointmentType == nil {
+		return
+	}
+
+	if err := config.AppointmentTypesRepository.Delete(dbAppointmentType); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// GetVets godoc
+// @Summary Get the veterinarians
+// @Description Get the veterinarians appointments can be booked with, without their contact details
+// @ID get-vets
+// @Tags schedule
+// @Success 200 {array} User "ok"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/vets [get]
+func (config *Config) GetVets(w http.ResponseWriter, r *http.Request) {
+	dbVets, err := config.findVets()
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	vets := make([]model.User, 0, len(dbVets))
+
+	for _, dbVet := range dbVets {
+		vets = append(vets, *dbVet.ToModel(false, false))
+	}
+
+	render.JSON(w, r, vets)
+}
+
+// Private
+
+// findAppointmentType returns the appointment type of the id URL parameter,
+// the error is rendered when nil is returned
+func (config *Config) findAppointmentType(w http.ResponseWriter, r *http.Request) *dbmodel.AppointmentType {
+	id := chi.URLParam(r, "id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return nil
+	}
+
+	dbAppointmentType, err := config.AppointmentTypesRepository.FindByID(uint(idUint))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbAppointmentType == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbAppointmentType
+}
+
+// findVets returns the veterinarians who aren't suspended
+func (config *Config) findVets() ([]*dbmodel.User, error) {
+	role := model.RoleVeterinaire
+	suspended := false
+
+	return config.UserRepository.FindAll(&dbmodel.UserFilter{
+		RoleID:    &role,
+		Suspended: &suspended,
+	})
+}
@@ -0,0 +1,310 @@
+package schedule
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// GetWorkingHours godoc
+// @Summary Get the working hours of a veterinarian
+// @Description Get the periods a veterinarian works every week, in the time zone of the clinic. Only available to the staff.
+// @ID get-working-hours
+// @Tags schedule
+// @Param vetid path int true "Veterinarian ID"
+// @Success 200 {array} WorkingHours "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/vets/{vetid}/working-hours [get]
+func (config *Config) GetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	dbVet := config.findVet(w, r)
+
+	if dbVet == nil {
+		return
+	}
+
+	dbHours, err := config.WorkingHoursRepository.FindByVet(dbVet.ID)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, workingHoursToModel(dbHours))
+}
+
+// UpdateWorkingHours godoc
+// @Summary Set the working hours of a veterinarian
+// @Description Replace the periods a veterinarian works every week, in the time zone of the clinic. The visits already booked are left unchanged. Only available to the veterinarian and to administrators.
+// @ID update-working-hours
+// @Tags schedule
+// @Param vetid path int true "Veterinarian ID"
+// @Accept json
+// @Produce json
+// @Param request body WorkingHoursPayload true "Working hours"
+// @Success 200 {array} WorkingHours "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/vets/{vetid}/working-hours [put]
+func (config *Config) UpdateWorkingHours(w http.ResponseWriter, r *http.Request) {
+	dbVet := config.findVet(w, r)
+
+	if dbVet == nil || !config.canManage(w, r, dbVet) {
+		return
+	}
+
+	data := &model.WorkingHoursPayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	periods := make([]model.WorkingPeriod, 0, len(data.Hours))
+
+	// The hours were validated by Bind
+	for _, hours := range data.Hours {
+		period, _ := hours.Period()
+		periods = append(periods, period)
+	}
+
+	dbHours, err := config.WorkingHoursRepository.Replace(dbVet.ID, periods)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, workingHoursToModel(dbHours))
+}
+
+// GetTimeOffs godoc
+// @Summary Get the time off of a veterinarian
+// @Description Get the periods a veterinarian doesn't work, the earliest first. Only available to the staff.
+// @ID get-time-offs
+// @Tags schedule
+// @Param vetid path int true "Veterinarian ID"
+// @Param from query string false "only the time off ending after this date, RFC 3339, now by default"
+// @Param to query string false "only the time off starting before this date, RFC 3339"
+// @Success 200 {array} TimeOff "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/vets/{vetid}/time-off [get]
+func (config *Config) GetTimeOffs(w http.ResponseWriter, r *http.Request) {
+	dbVet := config.findVet(w, r)
+
+	if dbVet == nil {
+		return
+	}
+
+	filter := &dbmodel.TimeOffFilter{VetID: dbVet.ID}
+
+	var err error
+
+	if filter.From, err = queryTime(r.URL.Query().Get("from")); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if filter.To, err = queryTime(r.URL.Query().Get("to")); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if filter.From == nil {
+		now := time.Now()
+		filter.From = &now
+	}
+
+	dbTimeOffs, err := config.TimeOffRepository.FindAll(filter)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	timeOffs := make([]model.TimeOff, 0, len(dbTimeOffs))
+
+	for _, dbTimeOff := range dbTimeOffs {
+		timeOffs = append(timeOffs, *dbTimeOff.ToModel())
+	}
+
+	render.JSON(w, r, timeOffs)
+}
+
+// CreateTimeOff godoc
+// @Summary Add time off
+// @Description Add a period a veterinarian doesn't work, no appointment can be booked with them during it. The visits already booked are left unchanged. Only available to the veterinarian and to administrators.
+// @ID create-time-off
+// @Tags schedule
+// @Param vetid path int true "Veterinarian ID"
+// @Accept json
+// @Produce json
+// @Param request body TimeOffCreatePayload true "Time off info"
+// @Success 201 {object} TimeOff "created"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/vets/{vetid}/time-off [post]
+func (config *Config) CreateTimeOff(w http.ResponseWriter, r *http.Request) {
+	dbVet := config.findVet(w, r)
+
+	if dbVet == nil || !config.canManage(w, r, dbVet) {
+		return
+	}
+
+	data := &model.TimeOffCreatePayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	dbTimeOff, err := config.TimeOffRepository.Create(&dbmodel.TimeOff{
+		VetID:  dbVet.ID,
+		Start:  *data.Start,
+		End:    *data.End,
+		Reason: data.Reason,
+	})
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, dbTimeOff.ToModel())
+}
+
+// DeleteTimeOff godoc
+// @Summary Delete time off
+// @Description Delete a period a veterinarian doesn't work. Only available to the veterinarian and to administrators.
+// @ID delete-time-off
+// @Tags schedule
+// @Param vetid path int true "Veterinarian ID"
+// @Param id path int true "Time off ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /schedule/vets/{vetid}/time-off/{id} [delete]
+func (config *Config) DeleteTimeOff(w http.ResponseWriter, r *http.Request) {
+	dbVet := config.findVet(w, r)
+
+	if dbVet == nil || !config.canManage(w, r, dbVet) {
+		return
+	}
+
+	id := chi.URLParam(r, "id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	dbTimeOff, err := config.TimeOffRepository.FindByID(uint(idUint), dbVet.ID)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if dbTimeOff == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return
+	}
+
+	if err := config.TimeOffRepository.Delete(dbTimeOff); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// Private
+
+// findVet returns the veterinarian of the vetid URL parameter, the error is
+// rendered when nil is returned
+func (config *Config) findVet(w http.ResponseWriter, r *http.Request) *dbmodel.User {
+	id := chi.URLParam(r, "vetid")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return nil
+	}
+
+	dbVet, err := config.UserRepository.FindByID(uint(idUint), false)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbVet == nil || dbVet.RoleID != model.RoleVeterinaire {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbVet
+}
+
+// canManage renders an error and returns false when the logged user can't
+// change the schedule of the veterinarian, only they and the administrators
+// can
+func (config *Config) canManage(w http.ResponseWriter, r *http.Request, vet *dbmodel.User) bool {
+	loggedUser := authentication.ForContext(r.Context())
+
+	if loggedUser.RoleID != model.RoleAdmin && loggedUser.ID != vet.ID {
+		render.Render(w, r, errors.ErrForbidden("cannot change the schedule of another veterinarian"))
+		return false
+	}
+
+	return true
+}
+
+func workingHoursToModel(dbHours []*dbmodel.WorkingHours) []model.WorkingHours {
+	hours := make([]model.WorkingHours, 0, len(dbHours))
+
+	for _, dbHour := range dbHours {
+		hours = append(hours, model.NewWorkingHours(dbHour.Period()))
+	}
+
+	return hours
+}
+
+func queryTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q, RFC 3339 expected", value)
+	}
+
+	return &date, nil
+}
@@ -0,0 +1,34 @@
+package schedule
+
+import (
+	"feldrise.com/animal-api/config"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+)
+
+func New(configuration *config.Config) *Config {
+	return &Config{configuration}
+}
+
+func (config *Config) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/appointment-types", config.GetAppointmentTypes)
+	router.With(authentication.RequireRole(model.RoleAdmin)).Post("/appointment-types", config.CreateAppointmentType)
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/appointment-types/{id}", config.GetAppointmentType)
+	router.With(authentication.RequireRole(model.RoleAdmin)).Put("/appointment-types/{id}", config.UpdateAppointmentType)
+	router.With(authentication.RequireRole(model.RoleAdmin)).Delete("/appointment-types/{id}", config.DeleteAppointmentType)
+
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/vets", config.GetVets)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Get("/vets/{vetid}/working-hours", config.GetWorkingHours)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Put("/vets/{vetid}/working-hours", config.UpdateWorkingHours)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Get("/vets/{vetid}/time-off", config.GetTimeOffs)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Post("/vets/{vetid}/time-off", config.CreateTimeOff)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Delete("/vets/{vetid}/time-off/{id}", config.DeleteTimeOff)
+
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/availability", config.GetAvailability)
+	router.With(authentication.RequireRole(model.AllRoles...), authentication.RequireVerifiedEmail).Post("/appointments", config.Book)
+
+	return router
+}
@@ -0,0 +1,7 @@
+package schedule
+
+import "feldrise.com/animal-api/config"
+
+type Config struct {
+	*config.Config
+}
@@ -0,0 +1,341 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Requests are signed with AWS Signature Version 4, which S3 and the
+// S3-compatible servers like MinIO accept
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3Service         = "s3"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3DateFormat      = "20060102T150405Z"
+	s3RequestTimeout  = 5 * time.Minute
+
+	// Presigned URLs can't last longer
+	s3MaxPresignedLifetime = 7 * 24 * time.Hour
+)
+
+type S3Options struct {
+	Endpoint  string // like https://s3.eu-west-3.amazonaws.com or http://localhost:9000
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+
+	// MinIO needs the bucket in the path rather than in the host name
+	PathStyle bool
+}
+
+// S3Storage keeps the files in an S3 bucket, so every instance of the API
+// shares them
+type S3Storage struct {
+	options  S3Options
+	endpoint *url.URL
+	client   *http.Client
+}
+
+func NewS3Storage(options S3Options) (*S3Storage, error) {
+	endpoint, err := url.Parse(options.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if endpoint.Scheme == "" || endpoint.Host == "" || options.Bucket == "" {
+		return nil, fmt.Errorf("the S3 storage needs an endpoint URL and a bucket")
+	}
+
+	if options.Region == "" {
+		options.Region = "us-east-1"
+	}
+
+	return &S3Storage{
+		options:  options,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: s3RequestTimeout},
+	}, nil
+}
+
+// Put streams the content in a single request, the size must be known
+func (s *S3Storage) Put(key string, content io.Reader, size int64, contentType string) error {
+	if size < 0 {
+		return fmt.Errorf("the S3 storage needs the size of the content")
+	}
+
+	request, err := s.request(http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+
+	request.ContentLength = size
+	if size == 0 {
+		request.Body = http.NoBody
+	}
+
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := s.do(request)
+	if err != nil {
+		return err
+	}
+
+	return response.Body.Close()
+}
+
+func (s *S3Storage) Open(key string) (io.ReadCloser, error) {
+	request, err := s.request(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Body, nil
+}
+
+func (s *S3Storage) Exists(key string) (bool, error) {
+	request, err := s.request(http.MethodHead, key, nil)
+	if err != nil {
+		return false, err
+	}
+
+	response, err := s.do(request)
+	if err == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, response.Body.Close()
+}
+
+func (s *S3Storage) Delete(key string) error {
+	request, err := s.request(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := s.do(request)
+	if err == ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return response.Body.Close()
+}
+
+// PresignedURL returns a URL downloading the file straight from the bucket
+func (s *S3Storage) PresignedURL(key string, lifetime time.Duration) (string, error) {
+	if lifetime > s3MaxPresignedLifetime {
+		lifetime = s3MaxPresignedLifetime
+	}
+
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	amzDate := now.Format(s3DateFormat)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s.options.AccessKey+"/"+s.scope(now))
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(lifetime.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	header := http.Header{}
+	header.Set("Host", objectURL.Host)
+
+	signature := s.signature(now, http.MethodGet, objectURL.EscapedPath(), query, header, s3UnsignedPayload)
+	query.Set("X-Amz-Signature", signature)
+
+	objectURL.RawQuery = canonicalQuery(query)
+
+	return objectURL.String(), nil
+}
+
+// Private
+
+func (s *S3Storage) objectURL(key string) (*url.URL, error) {
+	if key == "" || strings.Contains(key, "..") {
+		return nil, ErrInvalidKey
+	}
+
+	objectURL := *s.endpoint
+	path := strings.TrimSuffix(objectURL.Path, "/")
+
+	if s.options.PathStyle {
+		path += "/" + s.options.Bucket
+	} else {
+		objectURL.Host = s.options.Bucket + "." + objectURL.Host
+	}
+
+	objectURL.Path = path + "/" + key
+	objectURL.RawPath = uriEncode(objectURL.Path, false)
+
+	return &objectURL, nil
+}
+
+func (s *S3Storage) request(method string, key string, body io.Reader) (*http.Request, error) {
+	objectURL, err := s.objectURL(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return http.NewRequest(method, objectURL.String(), body)
+}
+
+// do signs and sends the request, the error statuses become errors
+func (s *S3Storage) do(request *http.Request) (*http.Response, error) {
+	now := time.Now().UTC()
+
+	request.Header.Set("Host", request.URL.Host)
+	request.Header.Set("X-Amz-Date", now.Format(s3DateFormat))
+	request.Header.Set("X-Amz-Content-Sha256", s3UnsignedPayload)
+
+	signedHeaders := http.Header{}
+	for _, name := range []string{"Host", "X-Amz-Content-Sha256", "X-Amz-Date"} {
+		signedHeaders.Set(name, request.Header.Get(name))
+	}
+
+	signature := s.signature(now, request.Method, request.URL.EscapedPath(), request.URL.Query(), signedHeaders, s3UnsignedPayload)
+
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s.options.AccessKey, s.scope(now), signedHeaderNames(signedHeaders), signature))
+	request.Header.Del("Host")
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		response.Body.Close()
+		return nil, ErrNotFound
+	}
+
+	if response.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		response.Body.Close()
+
+		return nil, fmt.Errorf("S3 %s %s: %s %s", request.Method, request.URL.Path, response.Status, strings.TrimSpace(string(message)))
+	}
+
+	return response, nil
+}
+
+func (s *S3Storage) scope(now time.Time) string {
+	return now.Format("20060102") + "/" + s.options.Region + "/" + s3Service + "/aws4_request"
+}
+
+func (s *S3Storage) signature(now time.Time, method string, path string, query url.Values, header http.Header, payloadHash string) string {
+	var canonicalHeaders strings.Builder
+
+	for _, name := range strings.Split(signedHeaderNames(header), ";") {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(header.Get(name)) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		canonicalQuery(query),
+		canonicalHeaders.String(),
+		signedHeaderNames(header),
+		payloadHash,
+	}, "\n")
+
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		now.Format(s3DateFormat),
+		s.scope(now),
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.options.SecretKey), now.Format("20060102"))
+	key = hmacSHA256(key, s.options.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func signedHeaderNames(header http.Header) string {
+	names := make([]string, 0, len(header))
+
+	for name := range header {
+		names = append(names, strings.ToLower(name))
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(names, ";")
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	parameters := make([]string, 0, len(keys))
+
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parameters = append(parameters, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	return strings.Join(parameters, "&")
+}
+
+// uriEncode percent-encodes everything but the unreserved characters, and
+// the slashes unless encodeSlash
+func uriEncode(value string, encodeSlash bool) string {
+	var builder strings.Builder
+
+	for _, b := range []byte(value) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+
+	return builder.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+
+	return mac.Sum(nil)
+}
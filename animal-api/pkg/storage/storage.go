@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+var (
+	ErrNotFound   = errors.New("file not found")
+	ErrInvalidKey = errors.New("invalid storage key")
+)
+
+// Storage keeps the files uploaded to the API, like the photos. The
+// implementation is selected with the `storage` configuration key.
+type Storage interface {
+	// Put streams the content to the key, replacing the file there if any.
+	// The size is required by some backends, -1 when unknown.
+	Put(key string, content io.Reader, size int64, contentType string) error
+
+	// Open returns the content of the key, ErrNotFound when there is none
+	Open(key string) (io.ReadCloser, error)
+
+	Exists(key string) (bool, error)
+
+	// Delete removes the file of the key, it is not an error if there is none
+	Delete(key string) error
+
+	// PresignedURL returns a URL downloading the file without credentials
+	// until it expires, or an empty string when the backend can't make one
+	PresignedURL(key string, lifetime time.Duration) (string, error)
+}
+
+type UnknownStorageError struct {
+	Name string
+}
+
+func (e *UnknownStorageError) Error() string {
+	return fmt.Sprintf("unknown storage %q", e.Name)
+}
+
+type Options struct {
+	Name string
+
+	// Local
+	Path string
+
+	// S3
+	S3 S3Options
+}
+
+func New(options Options) (Storage, error) {
+	switch options.Name {
+	case "", "local":
+		return NewLocalStorage(options.Path)
+	case "s3":
+		return NewS3Storage(options.S3)
+	}
+
+	return nil, &UnknownStorageError{Name: options.Name}
+}
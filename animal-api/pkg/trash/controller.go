@@ -0,0 +1,411 @@
+package trash
+
+import (
+	"net/http"
+	"strconv"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"feldrise.com/animal-api/pkg/photo"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// GetAnimals godoc
+// @Summary Get the deleted animals
+// @Description Get the animals in the trash, the most recently deleted first. Only available to administrators.
+// @ID get-trash-animals
+// @Tags trash
+// @Success 200 {array} Animal "ok"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /trash/animals [get]
+func (config *Config) GetAnimals(w http.ResponseWriter, r *http.Request) {
+	dbAnimals, err := config.AnimalsRepository.FindDeleted()
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	animals := make([]model.Animal, 0, len(dbAnimals))
+
+	for _, dbAnimal := range dbAnimals {
+		animals = append(animals, *dbAnimal.ToModel())
+	}
+
+	render.JSON(w, r, animals)
+}
+
+// RestoreAnimal godoc
+// @Summary Restore a deleted animal
+// @Description Take an animal and the visits, vitals and photos deleted with it out of the trash, only available to administrators
+// @ID restore-trash-animal
+// @Tags trash
+// @Param id path int true "Animal ID"
+// @Success 200 {object} Animal "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "identifier already registered, or the veterinarian has another visit at the time of one of its visits"
+// @Failure 500 {string} string "internal server error"
+// @Router /trash/animals/{id}/restore [post]
+func (config *Config) RestoreAnimal(w http.ResponseWriter, r *http.Request) {
+	dbAnimal := config.findDeletedAnimal(w, r)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	// The identifiers may have been given to another animal in the meantime
+	if dbAnimal.Microchip != nil {
+		other, err := config.AnimalsRepository.FindByMicrochip(*dbAnimal.Microchip, nil)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		if other != nil {
+			render.Render(w, r, errors.ErrConflict("microchip already registered"))
+			return
+		}
+	}
+
+	if dbAnimal.Tattoo != nil {
+		other, err := config.AnimalsRepository.FindByTattoo(*dbAnimal.Tattoo)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		if other != nil {
+			render.Render(w, r, errors.ErrConflict("tattoo already registered"))
+			return
+		}
+	}
+
+	err := config.AnimalsRepository.Restore(dbAnimal)
+
+	if err == dbmodel.ErrVisitConflict {
+		render.Render(w, r, errors.ErrConflict(err.Error()))
+		return
+	}
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, dbAnimal.ToModel())
+}
+
+// PurgeAnimal godoc
+// @Summary Purge a deleted animal
+// @Description Permanently delete an animal of the trash and all its visits, treatments, vitals and photos, only available to administrators
+// @ID purge-trash-animal
+// @Tags trash
+// @Param id path int true "Animal ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /trash/animals/{id} [delete]
+func (config *Config) PurgeAnimal(w http.ResponseWriter, r *http.Request) {
+	dbAnimal := config.findDeletedAnimal(w, r)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	dbPhotos, err := config.PhotosRepository.FindAll(dbAnimal.ID, true)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if err := config.AnimalsRepository.Purge(dbAnimal); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	photo.DeleteFiles(config.Config, dbPhotos...)
+
+	render.NoContent(w, r)
+}
+
+// GetVisits godoc
+// @Summary Get the deleted visits
+// @Description Get the visits in the trash, the most recently deleted first. Only available to administrators.
+// @ID get-trash-visits
+// @Tags trash
+// @Success 200 {array} Visit "ok"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /trash/visits [get]
+func (config *Config) GetVisits(w http.ResponseWriter, r *http.Request) {
+	dbVisits, err := config.VisitsRepository.FindDeleted()
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	visits := make([]model.Visit, 0, len(dbVisits))
+
+	for _, dbVisit := range dbVisits {
+		visits = append(visits, *dbVisit.ToModel())
+	}
+
+	render.JSON(w, r, visits)
+}
+
+// RestoreVisit godoc
+// @Summary Restore a deleted visit
+// @Description Take a visit out of the trash, its animal must not be in the trash. Only available to administrators.
+// @ID restore-trash-visit
+// @Tags trash
+// @Param id path int true "Visit ID"
+// @Success 200 {object} Visit "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "animal in the trash, or the veterinarian has another visit at this time"
+// @Failure 500 {string} string "internal server error"
+// @Router /trash/visits/{id}/restore [post]
+func (config *Config) RestoreVisit(w http.ResponseWriter, r *http.Request) {
+	dbVisit := config.findDeletedVisit(w, r)
+
+	if dbVisit == nil {
+		return
+	}
+
+	dbAnimal, err := config.AnimalsRepository.FindByID(dbVisit.AnimalID, nil, nil)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if dbAnimal == nil {
+		render.Render(w, r, errors.ErrConflict("the animal of the visit is in the trash"))
+		return
+	}
+
+	err = config.VisitsRepository.Restore(dbVisit)
+
+	if err == dbmodel.ErrVisitConflict {
+		render.Render(w, r, errors.ErrConflict(err.Error()))
+		return
+	}
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, dbVisit.ToModel())
+}
+
+// PurgeVisit godoc
+// @Summary Purge a deleted visit
+// @Description Permanently delete a visit of the trash and its treatments, only available to administrators
+// @ID purge-trash-visit
+// @Tags trash
+// @Param id path int true "Visit ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /trash/visits/{id} [delete]
+func (config *Config) PurgeVisit(w http.ResponseWriter, r *http.Request) {
+	dbVisit := config.findDeletedVisit(w, r)
+
+	if dbVisit == nil {
+		return
+	}
+
+	if err := config.VisitsRepository.Purge(dbVisit); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// GetUsers godoc
+// @Summary Get the deleted users
+// @Description Get the users in the trash, the most recently deleted first. Only available to administrators.
+// @ID get-trash-users
+// @Tags trash
+// @Success 200 {array} User "ok"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /trash/users [get]
+func (config *Config) GetUsers(w http.ResponseWriter, r *http.Request) {
+	dbUsers, err := config.UserRepository.FindDeleted()
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	users := make([]model.User, 0, len(dbUsers))
+
+	for _, dbUser := range dbUsers {
+		users = append(users, *dbUser.ToModel(true, true))
+	}
+
+	render.JSON(w, r, users)
+}
+
+// RestoreUser godoc
+// @Summary Restore a deleted user
+// @Description Take a user and its profile out of the trash, only available to administrators
+// @ID restore-trash-user
+// @Tags trash
+// @Param id path int true "User ID"
+// @Success 200 {object} User "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /trash/users/{id}/restore [post]
+func (config *Config) RestoreUser(w http.ResponseWriter, r *http.Request) {
+	dbUser := config.findDeletedUser(w, r)
+
+	if dbUser == nil {
+		return
+	}
+
+	if err := config.UserRepository.Restore(dbUser); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, dbUser.ToModel(true, true))
+}
+
+// PurgeUser godoc
+// @Summary Purge a deleted user
+// @Description Permanently delete a user of the trash, its animals are kept without owner. Only available to administrators.
+// @ID purge-trash-user
+// @Tags trash
+// @Param id path int true "User ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /trash/users/{id} [delete]
+func (config *Config) PurgeUser(w http.ResponseWriter, r *http.Request) {
+	dbUser := config.findDeletedUser(w, r)
+
+	if dbUser == nil {
+		return
+	}
+
+	if err := config.UserRepository.Purge(dbUser); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// Private
+
+// The find functions return the record of the id URL parameter when it is in
+// the trash, the error is rendered when nil is returned
+
+func (config *Config) findDeletedAnimal(w http.ResponseWriter, r *http.Request) *dbmodel.Animal {
+	id, ok := urlID(w, r)
+
+	if !ok {
+		return nil
+	}
+
+	dbAnimal, err := config.AnimalsRepository.FindDeletedByID(id)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbAnimal == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbAnimal
+}
+
+func (config *Config) findDeletedVisit(w http.ResponseWriter, r *http.Request) *dbmodel.Visit {
+	id, ok := urlID(w, r)
+
+	if !ok {
+		return nil
+	}
+
+	dbVisit, err := config.VisitsRepository.FindDeletedByID(id)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbVisit == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbVisit
+}
+
+func (config *Config) findDeletedUser(w http.ResponseWriter, r *http.Request) *dbmodel.User {
+	id, ok := urlID(w, r)
+
+	if !ok {
+		return nil
+	}
+
+	dbUser, err := config.UserRepository.FindDeletedByID(id)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbUser == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbUser
+}
+
+func urlID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id := chi.URLParam(r, "id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return 0, false
+	}
+
+	return uint(idUint), true
+}
@@ -0,0 +1,33 @@
+package trash
+
+import (
+	"feldrise.com/animal-api/config"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+)
+
+func New(configuration *config.Config) *Config {
+	return &Config{configuration}
+}
+
+func (config *Config) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	// Administrators
+	router.Use(authentication.RequireRole(model.RoleAdmin))
+
+	router.Get("/animals", config.GetAnimals)
+	router.Post("/animals/{id}/restore", config.RestoreAnimal)
+	router.Delete("/animals/{id}", config.PurgeAnimal)
+
+	router.Get("/visits", config.GetVisits)
+	router.Post("/visits/{id}/restore", config.RestoreVisit)
+	router.Delete("/visits/{id}", config.PurgeVisit)
+
+	router.Get("/users", config.GetUsers)
+	router.Post("/users/{id}/restore", config.RestoreUser)
+	router.Delete("/users/{id}", config.PurgeUser)
+
+	return router
+}
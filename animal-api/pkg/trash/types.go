@@ -0,0 +1,7 @@
+package trash
+
+import "feldrise.com/animal-api/config"
+
+type Config struct {
+	*config.Config
+}
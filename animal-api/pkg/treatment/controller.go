@@ -0,0 +1,273 @@
+package treatment
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/helper"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// GetAll godoc
+// @Summary Get the treatment catalog
+// @Description Get the treatments and medications of the catalog, sorted by name. Only available to the staff.
+// @ID get-treatments
+// @Tags treatments
+// @Param search query string false "part of the name"
+// @Param category query string false "medication, vaccine, antiparasitic, procedure or other"
+// @Success 200 {array} Treatment "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /treatments [get]
+func (config *Config) GetAll(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := &dbmodel.TreatmentsFilter{
+		Search:   strings.TrimSpace(query.Get("search")),
+		Category: query.Get("category"),
+	}
+
+	if filter.Category != "" && !helper.Contains(model.TreatmentCategories, filter.Category) {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid category, medication, vaccine, antiparasitic, procedure or other expected")))
+		return
+	}
+
+	dbTreatments, err := config.TreatmentsRepository.FindAll(filter)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	treatments := make([]model.Treatment, 0, len(dbTreatments))
+
+	for _, dbTreatment := range dbTreatments {
+		treatments = append(treatments, *dbTreatment.ToModel())
+	}
+
+	render.JSON(w, r, treatments)
+}
+
+// Get godoc
+// @Summary Get a treatment
+// @Description Get a treatment of the catalog. Only available to the staff.
+// @ID get-treatment
+// @Tags treatments
+// @Param id path int true "Treatment ID"
+// @Success 200 {object} Treatment "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /treatments/{id} [get]
+func (config *Config) Get(w http.ResponseWriter, r *http.Request) {
+	dbTreatment := config.findTreatment(w, r)
+
+	if dbTreatment == nil {
+		return
+	}
+
+	render.JSON(w, r, dbTreatment.ToModel())
+}
+
+// Create godoc
+// @Summary Add a treatment
+// @Description Add a treatment or a medication to the catalog. Only available to the staff.
+// @ID create-treatment
+// @Tags treatments
+// @Accept json
+// @Produce json
+// @Param request body TreatmentCreatePayload true "Treatment info"
+// @Success 201 {object} Treatment "created"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 409 {string} string "name already used"
+// @Failure 500 {string} string "internal server error"
+// @Router /treatments [post]
+func (config *Config) Create(w http.ResponseWriter, r *http.Request) {
+	data := &model.TreatmentCreatePayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	dbTreatment := &dbmodel.Treatment{
+		Name:        data.Name,
+		Description: data.Description,
+		Category:    model.TreatmentCategoryMedication,
+	}
+
+	if data.Category != nil {
+		dbTreatment.Category = *data.Category
+	}
+
+	if !config.checkName(w, r, dbTreatment) {
+		return
+	}
+
+	dbTreatment, err := config.TreatmentsRepository.Create(dbTreatment)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, dbTreatment.ToModel())
+}
+
+// Update godoc
+// @Summary Update a treatment
+// @Description Update a treatment of the catalog, the visits which recorded it show the change. Only available to the staff.
+// @ID update-treatment
+// @Tags treatments
+// @Param id path int true "Treatment ID"
+// @Accept json
+// @Produce json
+// @Param request body TreatmentUpdatePayload true "Treatment info"
+// @Success 200 {object} Treatment "ok"
+// @Failure 400 {object} ErrResponse "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "name already used"
+// @Failure 500 {string} string "internal server error"
+// @Router /treatments/{id} [put]
+func (config *Config) Update(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	dbTreatment := config.findTreatment(w, r)
+
+	if dbTreatment == nil {
+		return
+	}
+
+	data := &model.TreatmentUpdatePayload{}
+
+	forbidden, err := helper.DecodeAllowed(r.Body, model.TreatmentUpdateFields[loggedUser.RoleID], data)
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if len(forbidden) > 0 {
+		render.Render(w, r, errors.ErrForbiddenFields(forbidden))
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if data.Name != nil {
+		dbTreatment.Name = *data.Name
+	}
+
+	if data.Description != nil {
+		dbTreatment.Description = *data.Description
+	}
+
+	if data.Category != nil {
+		dbTreatment.Category = *data.Category
+	}
+
+	if !config.checkName(w, r, dbTreatment) {
+		return
+	}
+
+	dbTreatment, err = config.TreatmentsRepository.Update(dbTreatment)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, dbTreatment.ToModel())
+}
+
+// Delete godoc
+// @Summary Remove a treatment
+// @Description Remove a treatment from the catalog, the visits which recorded it keep it. Only available to the staff.
+// @ID delete-treatment
+// @Tags treatments
+// @Param id path int true "Treatment ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /treatments/{id} [delete]
+func (config *Config) Delete(w http.ResponseWriter, r *http.Request) {
+	dbTreatment := config.findTreatment(w, r)
+
+	if dbTreatment == nil {
+		return
+	}
+
+	if err := config.TreatmentsRepository.Delete(dbTreatment); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// Private
+
+// findTreatment returns the treatment of the id URL parameter, the error is
+// rendered when nil is returned
+func (config *Config) findTreatment(w http.ResponseWriter, r *http.Request) *dbmodel.Treatment {
+	id := chi.URLParam(r, "id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return nil
+	}
+
+	dbTreatment, err := config.TreatmentsRepository.FindByID(uint(idUint))
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbTreatment == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbTreatment
+}
+
+// checkName renders an error and returns false when another treatment of the
+// catalog has the same name
+func (config *Config) checkName(w http.ResponseWriter, r *http.Request, treatment *dbmodel.Treatment) bool {
+	other, err := config.TreatmentsRepository.FindByName(treatment.Name)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return false
+	}
+
+	if other != nil && other.ID != treatment.ID {
+		render.Render(w, r, errors.ErrConflict("treatment name already used"))
+		return false
+	}
+
+	return true
+}
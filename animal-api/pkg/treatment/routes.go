@@ -1,8 +1,10 @@
-package cat
+package treatment
 
 import (
 	"feldrise.com/animal-api/config"
-	"github.com/go-chi/chi"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
 )
 
 func New(configuration *config.Config) *Config {
@@ -12,10 +14,14 @@ func New(configuration *config.Config) *Config {
 func (config *Config) Routes() *chi.Mux {
 	router := chi.NewRouter()
 
+	// Staff
+	router.Use(authentication.RequireRole(model.StaffRoles...))
+
 	router.Get("/", config.GetAll)
 	router.Post("/", config.Create)
 	router.Get("/{id}", config.Get)
 	router.Put("/{id}", config.Update)
+	router.Delete("/{id}", config.Delete)
 
 	return router
 }
@@ -0,0 +1,7 @@
+package treatment
+
+import "feldrise.com/animal-api/config"
+
+type Config struct {
+	*config.Config
+}
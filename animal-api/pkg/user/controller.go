@@ -0,0 +1,383 @@
+package user
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// GetAll godoc
+// @Summary Get the users
+// @Description Search the users by email, first or last name, one page at a time. Only available to administrators.
+// @ID get-users
+// @Tags users
+// @Param search query string false "part of the email, first or last name"
+// @Param role_id query int false "only the users having this role"
+// @Param suspended query bool false "only the suspended, or not suspended, users"
+// @Param page query int false "page, starting at 1"
+// @Param per_page query int false "users per page, 20 by default and 100 at most"
+// @Success 200 {object} UserList "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 500 {string} string "internal server error"
+// @Router /users [get]
+func (config *Config) GetAll(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := &dbmodel.UserFilter{
+		SearchString: strings.TrimSpace(query.Get("search")),
+	}
+
+	if roleID := query.Get("role_id"); roleID != "" {
+		roleIDUint, err := strconv.ParseUint(roleID, 10, 64)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrInvalidRequest(err))
+			return
+		}
+
+		role := uint(roleIDUint)
+		filter.RoleID = &role
+	}
+
+	if suspended := query.Get("suspended"); suspended != "" {
+		suspendedBool, err := strconv.ParseBool(suspended)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrInvalidRequest(err))
+			return
+		}
+
+		filter.Suspended = &suspendedBool
+	}
+
+	page, err := queryInt(query.Get("page"), 1)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	perPage, err := queryInt(query.Get("per_page"), defaultPerPage)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if perPage > maxPerPage {
+		perPage = maxPerPage
+	}
+
+	total, err := config.UserRepository.Count(filter)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	var dbUsers []*dbmodel.User
+
+	// The pages after the last one are empty, checked before the offset is
+	// computed so a huge page can't overflow it
+	if int64(page-1) <= total/int64(perPage) {
+		filter.Offset = (page - 1) * perPage
+		filter.Limit = perPage
+
+		dbUsers, err = config.UserRepository.FindAll(filter)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+	}
+
+	users := make([]*model.User, 0, len(dbUsers))
+	for _, user := range dbUsers {
+		users = append(users, user.ToModel(true, true))
+	}
+
+	render.JSON(w, r, &model.UserList{
+		Users:   users,
+		Total:   total,
+		Page:    page,
+		PerPage: perPage,
+	})
+}
+
+// Get godoc
+// @Summary Get a user
+// @Description Get a user by its id, only available to administrators
+// @ID get-user
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 200 {object} User "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /users/{id} [get]
+func (config *Config) Get(w http.ResponseWriter, r *http.Request) {
+	user := config.findUser(w, r)
+
+	if user == nil {
+		return
+	}
+
+	render.JSON(w, r, user.ToModel(true, true))
+}
+
+// UpdateRole godoc
+// @Summary Change the role of a user
+// @Description Change the role of a user, only available to administrators
+// @ID update-user-role
+// @Tags users
+// @Param id path int true "User ID"
+// @Param request body RoleUpdatePayload true "new role"
+// @Success 200 {object} User "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /users/{id}/role [put]
+func (config *Config) UpdateRole(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	data := &model.RoleUpdatePayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	user := config.findUser(w, r)
+
+	if user == nil {
+		return
+	}
+
+	// An administrator demoting itself could leave the clinic without any
+	if loggedUser.ID == user.ID {
+		render.Render(w, r, errors.ErrForbidden("cannot change your own role"))
+		return
+	}
+
+	user.RoleID = *data.RoleID
+
+	user, err := config.UserRepository.Update(user)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, user.ToModel(true, true))
+}
+
+// Suspend godoc
+// @Summary Suspend a user
+// @Description Prevent a user from logging in and end its sessions, only available to administrators
+// @ID suspend-user
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 200 {object} User "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /users/{id}/suspend [post]
+func (config *Config) Suspend(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	user := config.findUser(w, r)
+
+	if user == nil {
+		return
+	}
+
+	if loggedUser.ID == user.ID {
+		render.Render(w, r, errors.ErrForbidden("cannot suspend your own account"))
+		return
+	}
+
+	if user.SuspendedAt == nil {
+		now := time.Now()
+		user.SuspendedAt = &now
+		user.SessionsRevokedAt = &now
+
+		if err := config.RefreshTokensRepository.RevokeAllForUser(user.ID); err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+
+		var err error
+		user, err = config.UserRepository.Update(user)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+	}
+
+	render.JSON(w, r, user.ToModel(true, true))
+}
+
+// Reactivate godoc
+// @Summary Reactivate a user
+// @Description Allow a suspended user to log in again, only available to administrators
+// @ID reactivate-user
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 200 {object} User "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /users/{id}/reactivate [post]
+func (config *Config) Reactivate(w http.ResponseWriter, r *http.Request) {
+	user := config.findUser(w, r)
+
+	if user == nil {
+		return
+	}
+
+	if user.SuspendedAt != nil {
+		user.SuspendedAt = nil
+
+		var err error
+		user, err = config.UserRepository.Update(user)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+	}
+
+	render.JSON(w, r, user.ToModel(true, true))
+}
+
+// Unlock godoc
+// @Summary Unlock a user
+// @Description Clear the failed login attempts of a user, only available to administrators
+// @ID unlock-user
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 200 {object} User "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /users/{id}/unlock [post]
+func (config *Config) Unlock(w http.ResponseWriter, r *http.Request) {
+	user := config.findUser(w, r)
+
+	if user == nil {
+		return
+	}
+
+	if err := config.LoginThrottlesRepository.Reset(authentication.AccountThrottleKey(user.Email)); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, user.ToModel(true, true))
+}
+
+// Delete godoc
+// @Summary Delete a user
+// @Description Move a user to the trash and end its sessions, only available to administrators
+// @ID delete-user
+// @Tags users
+// @Param id path int true "User ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /users/{id} [delete]
+func (config *Config) Delete(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	user := config.findUser(w, r)
+
+	if user == nil {
+		return
+	}
+
+	if loggedUser.ID == user.ID {
+		render.Render(w, r, errors.ErrForbidden("cannot delete your own account"))
+		return
+	}
+
+	if err := config.RefreshTokensRepository.RevokeAllForUser(user.ID); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if err := config.UserRepository.Delete(user); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// Private
+
+// findUser returns the user of the id URL parameter, the error is rendered
+// when nil is returned
+func (config *Config) findUser(w http.ResponseWriter, r *http.Request) *dbmodel.User {
+	id := chi.URLParam(r, "id")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return nil
+	}
+
+	user, err := config.UserRepository.FindByID(uint(idUint), true)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if user == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return user
+}
+
+func queryInt(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if number < 1 {
+		return 0, strconv.ErrRange
+	}
+
+	return number, nil
+}
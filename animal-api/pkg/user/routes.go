@@ -0,0 +1,29 @@
+package user
+
+import (
+	"feldrise.com/animal-api/config"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+)
+
+func New(configuration *config.Config) *Config {
+	return &Config{configuration}
+}
+
+func (config *Config) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	// Administrators
+	router.Use(authentication.RequireRole(model.RoleAdmin))
+
+	router.Get("/", config.GetAll)
+	router.Get("/{id}", config.Get)
+	router.Delete("/{id}", config.Delete)
+	router.Put("/{id}/role", config.UpdateRole)
+	router.Post("/{id}/suspend", config.Suspend)
+	router.Post("/{id}/reactivate", config.Reactivate)
+	router.Post("/{id}/unlock", config.Unlock)
+
+	return router
+}
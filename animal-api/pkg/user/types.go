@@ -0,0 +1,12 @@
+package user
+
+import "feldrise.com/animal-api/config"
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+type Config struct {
+	*config.Config
+}
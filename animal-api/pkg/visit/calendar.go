@@ -0,0 +1,127 @@
+package visit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/helper"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/render"
+)
+
+// GetCalendar godoc
+// @Summary Get the calendar of the visits
+// @Description Get the visits of all the animals overlapping a period, ordered by date, with their animal and its owner. The period is the current day in the clinic's time zone by default and lasts 31 days at most. The clients only get the visits of their animals.
+// @Tags visits
+// @Param from query string false "only the visits ending after this date, RFC 3339"
+// @Param to query string false "only the visits starting before this date, RFC 3339"
+// @Param vet query int false "only the visits assigned to this veterinarian"
+// @Param status query string false "only the visits of these statuses, separated by commas"
+// @Success 200 {array} Visit "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 500 {string} string "internal server error"
+// @Router /visits [get]
+func (config *Config) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+	query := r.URL.Query()
+
+	filter := &dbmodel.VisitsFilter{
+		OwnerID: dbmodel.AnimalsFilterForUser(loggedUser).OwnerID,
+	}
+
+	var err error
+
+	if filter.From, err = queryTime(query.Get("from")); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if filter.To, err = queryTime(query.Get("to")); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if filter.From == nil {
+		now := time.Now().In(config.Location)
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, config.Location)
+		filter.From = &today
+	}
+
+	if filter.To == nil {
+		end := filter.From.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	if !filter.To.After(*filter.From) {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid to parameter, the period ends before it starts")))
+		return
+	}
+
+	if filter.To.Sub(*filter.From) > model.MaxVisitsCalendarPeriod {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("the period can't be longer than %d days", model.MaxVisitsCalendarPeriod/(24*time.Hour))))
+		return
+	}
+
+	if vet := query.Get("vet"); vet != "" {
+		vetID, err := strconv.ParseUint(vet, 10, 64)
+
+		if err != nil {
+			render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid vet parameter")))
+			return
+		}
+
+		id := uint(vetID)
+		filter.VetID = &id
+	}
+
+	if status := query.Get("status"); status != "" {
+		for _, status := range strings.Split(status, ",") {
+			if !helper.Contains(model.VisitStatuses, status) {
+				render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid status parameter, unknown status %q", status)))
+				return
+			}
+
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	dbVisits, err := config.VisitsRepository.FindAll(filter, &dbmodel.VisitsFieldsToInclude{
+		Animal: true,
+		Owner:  true,
+	})
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	visits := make([]model.Visit, 0, len(dbVisits))
+
+	for _, dbVisit := range dbVisits {
+		visits = append(visits, *dbVisit.ToModel())
+	}
+
+	render.JSON(w, r, visits)
+}
+
+// Private
+
+func queryTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q, RFC 3339 expected", value)
+	}
+
+	return &date, nil
+}
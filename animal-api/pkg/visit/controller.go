@@ -1,57 +1,53 @@
 package visit
 
 import (
-	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"feldrise.com/animal-api/database/dbmodel"
 	"feldrise.com/animal-api/helper"
 	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/calendar"
 	"feldrise.com/animal-api/pkg/errors"
 	"feldrise.com/animal-api/pkg/model"
-	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 )
 
 // Get godoc
 // @Summary Get a visit
-// @Description Get a visit by ID and for a specific cat
+// @Description Get a visit by ID and for a specific animal, with its treatments and the history of its status. Requested with the text/calendar media type, the visit is an iCalendar file to add to a calendar application.
 // @Tags visits
-// @Param catid path int true "Cat ID"
+// @Produce json,text/calendar
+// @Param animalid path int true "Animal ID"
 // @Param id path int true "Visit ID"
 // @Success 200 {object} Visit "ok"
 // @Failure 400 {string} string "bad request"
 // @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
 // @Failure 500 {string} string "internal server error"
-// @Router /cats/{catid}/visits/{id} [get]
+// @Router /{animalid}/visits/{id} [get]
 func (config *Config) Get(w http.ResponseWriter, r *http.Request) {
-	catID := chi.URLParam(r, "catid")
-	visitID := chi.URLParam(r, "id")
-	catIDUint, err := strconv.ParseUint(catID, 10, 64)
-	visitIDUint, err := strconv.ParseUint(visitID, 10, 64)
+	dbAnimal := config.findAnimal(w, r)
 
-	if err != nil {
-		render.Render(w, r, errors.ErrInvalidRequest(err))
+	if dbAnimal == nil {
 		return
 	}
 
-	dbVisit, err := config.VisitsRepository.FindByID(uint(visitIDUint), &dbmodel.VisitsFieldsToInclude{
-		Cat: true,
+	dbVisit := config.findVisit(w, r, dbAnimal, &dbmodel.VisitsFieldsToInclude{
+		Animal:      true,
+		Treatments:  true,
+		Transitions: true,
 	})
 
-	if err != nil {
-		render.Render(w, r, errors.ErrServerError(err))
-		return
-	}
-
 	if dbVisit == nil {
-		render.Render(w, r, errors.ErrNotFound())
 		return
 	}
 
-	if dbVisit.CatID != uint(catIDUint) {
-		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+	if calendar.Accepts(r) {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="visit-%d.ics"`, dbVisit.ID))
+		calendar.Write(w, config.Config, "", []*dbmodel.Visit{dbVisit})
 		return
 	}
 
@@ -60,27 +56,26 @@ func (config *Config) Get(w http.ResponseWriter, r *http.Request) {
 
 // GetAll godoc
 // @Summary Get all visits
-// @Description Get all visits for a specific cat
+// @Description Get all visits for a specific animal
 // @Tags visits
-// @Param catid path int true "Cat ID"
+// @Param animalid path int true "Animal ID"
 // @Success 200 {array} Visit "ok"
 // @Failure 400 {string} string "bad request"
 // @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
 // @Failure 500 {string} string "internal server error"
-// @Router /cats/{catid}/visits [get]
+// @Router /{animalid}/visits [get]
 func (config *Config) GetAll(w http.ResponseWriter, r *http.Request) {
-	catID := chi.URLParam(r, "catid")
-	catIDUint, err := strconv.ParseUint(catID, 10, 64)
+	dbAnimal := config.findAnimal(w, r)
 
-	if err != nil {
-		render.Render(w, r, errors.ErrInvalidRequest(err))
+	if dbAnimal == nil {
 		return
 	}
 
 	dbVisits, err := config.VisitsRepository.FindAll(&dbmodel.VisitsFilter{
-		CatID: uint(catIDUint),
+		AnimalID: dbAnimal.ID,
 	}, &dbmodel.VisitsFieldsToInclude{
-		Cat: true,
+		Animal: true,
 	})
 
 	if err != nil {
@@ -99,17 +94,19 @@ func (config *Config) GetAll(w http.ResponseWriter, r *http.Request) {
 
 // Create godoc
 // @Summary Create a visit
-// @Description Create a visit for a specific cat
+// @Description Create a visit for a specific animal, with the reason for consultation and optionally its veterinarian and SOAP notes
 // @Tags visits
-// @Param catid path int true "Cat ID"
+// @Param animalid path int true "Animal ID"
 // @Accept json
 // @Produce json
-// @Param request body Visit true "Visit info"
-// @Success 200 {object} Visit "ok"
+// @Param request body VisitCreatePayload true "Visit info"
+// @Success 201 {object} Visit "created"
 // @Failure 400 {string} string "bad request"
 // @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the veterinarian has another visit at this time"
 // @Failure 500 {string} string "internal server error"
-// @Router /cats/{catid}/visits [post]
+// @Router /{animalid}/visits [post]
 func (config *Config) Create(w http.ResponseWriter, r *http.Request) {
 	loggedUser := authentication.ForContext(r.Context())
 
@@ -118,49 +115,85 @@ func (config *Config) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	catID := chi.URLParam(r, "catid")
-	catIDUint, err := strconv.ParseUint(catID, 10, 64)
+	dbAnimal := config.findAnimal(w, r)
 
-	if err != nil {
-		render.Render(w, r, errors.ErrInvalidRequest(err))
+	if dbAnimal == nil {
 		return
 	}
 
 	var data model.VisitCreatePayload
 
-	err = render.Bind(r, &data)
+	err := render.Bind(r, &data)
 	if err != nil {
 		render.Render(w, r, errors.ErrInvalidRequest(err))
 		return
 	}
 
-	dbVisit, err := config.VisitsRepository.Create(&dbmodel.Visit{
-		Date:  *data.Date,
-		CatID: uint(catIDUint),
-	})
+	if data.VetID != nil && !config.checkVet(w, r, *data.VetID) {
+		return
+	}
+
+	visit := &dbmodel.Visit{
+		Date:     *data.Date,
+		AnimalID: dbAnimal.ID,
+		Reason:   data.Reason,
+		VetID:    data.VetID,
+		Duration: model.DefaultVisitDuration,
+		Status:   model.VisitStatusConfirmed,
+	}
+
+	if data.Duration != nil {
+		visit.Duration = *data.Duration
+	}
+
+	if data.Status != nil {
+		visit.Status = *data.Status
+	}
+
+	if data.Notes != nil {
+		notes := model.SOAPNotes{}
+		data.Notes.Apply(&notes)
+		visit.Notes = dbmodel.SOAPNotes(notes)
+	}
+
+	dbVisit, err := config.VisitsRepository.Book(visit)
+
+	if err == dbmodel.ErrVisitConflict {
+		render.Render(w, r, errors.ErrConflict(err.Error()))
+		return
+	}
+
+	if err == dbmodel.ErrVisitVetUnavailable {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
 
 	if err != nil {
 		render.Render(w, r, errors.ErrServerError(err))
 		return
 	}
 
+	render.Status(r, http.StatusCreated)
 	render.JSON(w, r, dbVisit.ToModel())
 }
 
 // Update godoc
 // @Summary Update a visit
-// @Description Update a visit for a specific cat
+// @Description Update a visit for a specific animal. The status changes through the actions of the visit, and the completed, cancelled and missed visits can't be changed anymore. With the following scope, the reason, veterinarian, duration and date shift apply to the next open visits of its series too.
 // @Tags visits
-// @Param catid path int true "Cat ID"
+// @Param animalid path int true "Animal ID"
 // @Param id path int true "Visit ID"
 // @Accept json
 // @Produce json
-// @Param request body map[string]interface{} true "Visit info"
+// @Param scope query string false "this (default) or following"
+// @Param request body VisitUpdatePayload true "Visit info"
 // @Success 200 {object} Visit "ok"
-// @Failure 400 {string} string "bad request"
+// @Failure 400 {object} ErrResponse "bad request"
 // @Failure 401 {string} string "unauthorized"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the veterinarian has another visit at this time, or the visit is closed"
 // @Failure 500 {string} string "internal server error"
-// @Router /cats/{catid}/visits/{id} [put]
+// @Router /{animalid}/visits/{id} [put]
 func (config *Config) Update(w http.ResponseWriter, r *http.Request) {
 	loggedUser := authentication.ForContext(r.Context())
 
@@ -169,49 +202,241 @@ func (config *Config) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	catID := chi.URLParam(r, "catid")
-	visitID := chi.URLParam(r, "id")
-	catIDUint, err := strconv.ParseUint(catID, 10, 64)
-	visitIDUint, err := strconv.ParseUint(visitID, 10, 64)
+	dbAnimal := config.findAnimal(w, r)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	dbVisit := config.findVisit(w, r, dbAnimal, nil)
 
+	if dbVisit == nil {
+		return
+	}
+
+	if !checkOpen(w, r, dbVisit) {
+		return
+	}
+
+	data := &model.VisitUpdatePayload{}
+
+	forbidden, err := helper.DecodeAllowed(r.Body, model.VisitUpdateFields[loggedUser.RoleID], data)
 	if err != nil {
 		render.Render(w, r, errors.ErrInvalidRequest(err))
 		return
 	}
 
-	dbVisit, err := config.VisitsRepository.FindByID(uint(visitIDUint), nil)
+	if len(forbidden) > 0 {
+		render.Render(w, r, errors.ErrForbiddenFields(forbidden))
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if data.VetID != nil && *data.VetID != 0 && !config.checkVet(w, r, *data.VetID) {
+		return
+	}
+
+	scope, ok := queryScope(w, r)
+
+	if !ok {
+		return
+	}
+
+	// The following visits are looked up before the visit moves
+	var dbFollowing []*dbmodel.Visit
+
+	if scope == model.VisitScopeFollowing {
+		if dbFollowing, err = config.VisitsRepository.FindFollowing(dbVisit); err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+	}
+
+	// The following visits move like this one, and the notes are its own
+	for _, dbFollowingVisit := range dbFollowing {
+		if data.Date != nil {
+			dbFollowingVisit.Date = model.ShiftDate(dbFollowingVisit.Date, dbVisit.Date, *data.Date, config.Location)
+		}
+
+		applyVisitUpdate(dbFollowingVisit, data)
+	}
+
+	if data.Date != nil {
+		dbVisit.Date = *data.Date
+	}
+
+	applyVisitUpdate(dbVisit, data)
+
+	if data.Notes != nil {
+		notes := model.SOAPNotes(dbVisit.Notes)
+		data.Notes.Apply(&notes)
+		dbVisit.Notes = dbmodel.SOAPNotes(notes)
+	}
+
+	if len(dbFollowing) > 0 {
+		err = config.VisitsRepository.BookAll(append([]*dbmodel.Visit{dbVisit}, dbFollowing...))
+	} else {
+		dbVisit, err = config.VisitsRepository.Book(dbVisit)
+	}
+
+	if err == dbmodel.ErrVisitConflict {
+		render.Render(w, r, errors.ErrConflict(err.Error()))
+		return
+	}
+
+	if err == dbmodel.ErrVisitVetUnavailable {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
 
 	if err != nil {
 		render.Render(w, r, errors.ErrServerError(err))
 		return
 	}
 
+	render.JSON(w, r, dbVisit.ToModel())
+}
+
+// Delete godoc
+// @Summary Delete a visit
+// @Description Move a visit of a specific animal to the trash, only available to the staff
+// @Tags visits
+// @Param animalid path int true "Animal ID"
+// @Param id path int true "Visit ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 500 {string} string "internal server error"
+// @Router /{animalid}/visits/{id} [delete]
+func (config *Config) Delete(w http.ResponseWriter, r *http.Request) {
+	dbAnimal := config.findAnimal(w, r)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	dbVisit := config.findVisit(w, r, dbAnimal, nil)
+
 	if dbVisit == nil {
-		render.Render(w, r, errors.ErrNotFound())
 		return
 	}
 
-	if dbVisit.CatID != uint(catIDUint) {
-		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+	if err := config.VisitsRepository.Delete(dbVisit); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
 		return
 	}
 
-	var data map[string]interface{}
+	render.NoContent(w, r)
+}
+
+// Private
+
+// findAnimal returns the animal of the animalid URL parameter when the logged
+// user can access it, the error is rendered when nil is returned
+func (config *Config) findAnimal(w http.ResponseWriter, r *http.Request) *dbmodel.Animal {
+	loggedUser := authentication.ForContext(r.Context())
+
+	animalID := chi.URLParam(r, "animalid")
+	animalIDUint, err := strconv.ParseUint(animalID, 10, 64)
 
-	err = json.NewDecoder(r.Body).Decode(&data)
 	if err != nil {
 		render.Render(w, r, errors.ErrInvalidRequest(err))
-		return
+		return nil
 	}
 
-	helper.ApplyChanges(data, dbVisit)
+	dbAnimal, err := config.AnimalsRepository.FindByID(uint(animalIDUint), dbmodel.AnimalsFilterForUser(loggedUser), nil)
 
-	dbVisit, err = config.VisitsRepository.Update(dbVisit)
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbAnimal == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbAnimal
+}
+
+// findVisit returns the visit of the id URL parameter with the fields to
+// include when it belongs to the animal, the error is rendered when nil is
+// returned
+func (config *Config) findVisit(w http.ResponseWriter, r *http.Request, animal *dbmodel.Animal, include *dbmodel.VisitsFieldsToInclude) *dbmodel.Visit {
+	visitID := chi.URLParam(r, "id")
+	visitIDUint, err := strconv.ParseUint(visitID, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return nil
+	}
+
+	dbVisit, err := config.VisitsRepository.FindByID(uint(visitIDUint), include)
 
 	if err != nil {
 		render.Render(w, r, errors.ErrServerError(err))
-		return
+		return nil
 	}
 
-	render.JSON(w, r, dbVisit.ToModel())
+	if dbVisit == nil || dbVisit.AnimalID != animal.ID {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbVisit
+}
+
+// checkOpen checks the visit isn't closed, the error is rendered when false
+// is returned
+func checkOpen(w http.ResponseWriter, r *http.Request, visit *dbmodel.Visit) bool {
+	if helper.Contains(model.VisitClosedStatuses, visit.Status) {
+		render.Render(w, r, errors.ErrConflict(fmt.Sprintf("the visit is %s, it can't be changed anymore", visit.Status)))
+		return false
+	}
+
+	return true
+}
+
+// checkVet checks the user exists and is a veterinarian, the error is
+// rendered when false is returned
+func (config *Config) checkVet(w http.ResponseWriter, r *http.Request, vetID uint) bool {
+	vet, err := config.UserRepository.FindByID(vetID, false)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return false
+	}
+
+	if vet == nil || vet.RoleID != model.RoleVeterinaire {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid vet_id property, the user is not a veterinarian")))
+		return false
+	}
+
+	return true
+}
+
+// applyVisitUpdate sets the reason, veterinarian and duration of the payload
+// to the visit, the ones a series shares
+func applyVisitUpdate(visit *dbmodel.Visit, data *model.VisitUpdatePayload) {
+	if data.Reason != nil {
+		visit.Reason = *data.Reason
+	}
+
+	if data.VetID != nil {
+		visit.VetID = data.VetID
+
+		if *data.VetID == 0 {
+			visit.VetID = nil
+		}
+	}
+
+	if data.Duration != nil {
+		visit.Duration = *data.Duration
+	}
 }
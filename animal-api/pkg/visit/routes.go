@@ -2,6 +2,8 @@ package visit
 
 import (
 	"feldrise.com/animal-api/config"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/model"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -12,10 +14,33 @@ func New(configuration *config.Config) *Config {
 func (config *Config) Routes() *chi.Mux {
 	router := chi.NewRouter()
 
-	router.Get("/", config.GetAll)
-	router.Post("/", config.Create)
-	router.Get("/{id}", config.Get)
-	router.Put("/{id}", config.Update)
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/", config.GetAll)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Post("/", config.Create)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Post("/series", config.CreateSeries)
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/{id}", config.Get)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Put("/{id}", config.Update)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Delete("/{id}", config.Delete)
+
+	router.With(authentication.RequireRole(model.StaffRoles...)).Post("/{id}/confirm", config.Confirm)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Post("/{id}/check-in", config.CheckIn)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Post("/{id}/start", config.Start)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Post("/{id}/complete", config.Complete)
+	router.With(authentication.RequireRole(model.AllRoles...)).Post("/{id}/cancel", config.Cancel)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Post("/{id}/no-show", config.NoShow)
+
+	router.With(authentication.RequireRole(model.StaffRoles...)).Post("/{id}/treatments", config.CreateTreatment)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Put("/{id}/treatments/{treatmentid}", config.UpdateTreatment)
+	router.With(authentication.RequireRole(model.StaffRoles...)).Delete("/{id}/treatments/{treatmentid}", config.DeleteTreatment)
+
+	return router
+}
+
+// CalendarRoutes lists the visits of all the animals, mounted apart from the
+// visits of an animal
+func (config *Config) CalendarRoutes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.With(authentication.RequireRole(model.AllRoles...)).Get("/", config.GetCalendar)
 
 	return router
 }
@@ -0,0 +1,115 @@
+package visit
+
+import (
+	"fmt"
+	"net/http"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/render"
+)
+
+// CreateSeries godoc
+// @Summary Create a series of visits
+// @Description Create the visits of a specific animal repeated by a recurrence like FREQ=WEEKLY;INTERVAL=2;COUNT=6, at the clock time of the first one in the clinic's time zone. Either all the visits are created or none when the veterinarian has another visit at one of their dates. They are then changed one by one or with the following ones of the series.
+// @Tags visits
+// @Param animalid path int true "Animal ID"
+// @Accept json
+// @Produce json
+// @Param request body VisitSeriesCreatePayload true "Series info"
+// @Success 201 {object} VisitSeries "created"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the veterinarian has another visit at one of the dates"
+// @Failure 500 {string} string "internal server error"
+// @Router /{animalid}/visits/series [post]
+func (config *Config) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	if loggedUser == nil {
+		render.Render(w, r, errors.ErrUnauthorized("unauthorized"))
+		return
+	}
+
+	dbAnimal := config.findAnimal(w, r)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	var data model.VisitSeriesCreatePayload
+
+	err := render.Bind(r, &data)
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if data.VetID != nil && !config.checkVet(w, r, *data.VetID) {
+		return
+	}
+
+	recurrence, err := model.ParseRecurrence(data.Recurrence, config.Location)
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid recurrence property, %w", err)))
+		return
+	}
+
+	dates, err := recurrence.Occurrences(*data.Date, config.Location)
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid recurrence property, %w", err)))
+		return
+	}
+
+	// An UNTIL before the first visit leaves no visit
+	if len(dates) == 0 {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid recurrence property, no visit before UNTIL")))
+		return
+	}
+
+	duration := model.DefaultVisitDuration
+
+	if data.Duration != nil {
+		duration = *data.Duration
+	}
+
+	visits := make([]*dbmodel.Visit, 0, len(dates))
+
+	for _, date := range dates {
+		visits = append(visits, &dbmodel.Visit{
+			Date:     date,
+			AnimalID: dbAnimal.ID,
+			Reason:   data.Reason,
+			VetID:    data.VetID,
+			Duration: duration,
+			Status:   model.VisitStatusConfirmed,
+		})
+	}
+
+	dbSeries, err := config.VisitsRepository.BookSeries(&dbmodel.VisitSeries{
+		AnimalID:   dbAnimal.ID,
+		Recurrence: recurrence.String(),
+	}, visits)
+
+	if err == dbmodel.ErrVisitConflict {
+		render.Render(w, r, errors.ErrConflict("the veterinarian has another visit at one of the dates of the series"))
+		return
+	}
+
+	if err == dbmodel.ErrVisitVetUnavailable {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, dbSeries.ToModel())
+}
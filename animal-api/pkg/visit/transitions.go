@@ -0,0 +1,276 @@
+package visit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/render"
+)
+
+// Confirm godoc
+// @Summary Confirm a requested visit
+// @Description Confirm a visit requested by a client. Only available to the staff. The transition is recorded with who made it and why.
+// @Tags visits
+// @Param animalid path int true "Animal ID"
+// @Param id path int true "Visit ID"
+// @Accept json
+// @Produce json
+// @Param request body VisitTransitionPayload false "Reason"
+// @Success 200 {object} Visit "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the action can't be taken on a visit of this status"
+// @Failure 500 {string} string "internal server error"
+// @Router /{animalid}/visits/{id}/confirm [post]
+func (config *Config) Confirm(w http.ResponseWriter, r *http.Request) {
+	config.transition(w, r, model.VisitActionConfirm)
+}
+
+// CheckIn godoc
+// @Summary Check in a visit
+// @Description Record the animal of a confirmed visit arrived at the clinic. Only available to the staff. The transition is recorded with who made it and why.
+// @Tags visits
+// @Param animalid path int true "Animal ID"
+// @Param id path int true "Visit ID"
+// @Accept json
+// @Produce json
+// @Param request body VisitTransitionPayload false "Reason"
+// @Success 200 {object} Visit "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the action can't be taken on a visit of this status"
+// @Failure 500 {string} string "internal server error"
+// @Router /{animalid}/visits/{id}/check-in [post]
+func (config *Config) CheckIn(w http.ResponseWriter, r *http.Request) {
+	config.transition(w, r, model.VisitActionCheckIn)
+}
+
+// Start godoc
+// @Summary Start a visit
+// @Description Start a confirmed or checked in visit. Only available to the staff. The transition is recorded with who made it and why.
+// @Tags visits
+// @Param animalid path int true "Animal ID"
+// @Param id path int true "Visit ID"
+// @Accept json
+// @Produce json
+// @Param request body VisitTransitionPayload false "Reason"
+// @Success 200 {object} Visit "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the action can't be taken on a visit of this status"
+// @Failure 500 {string} string "internal server error"
+// @Router /{animalid}/visits/{id}/start [post]
+func (config *Config) Start(w http.ResponseWriter, r *http.Request) {
+	config.transition(w, r, model.VisitActionStart)
+}
+
+// Complete godoc
+// @Summary Complete a visit
+// @Description Complete a visit in progress, it can't be changed anymore. With follow_up_days, a follow-up with the same veterinarian is scheduled that many days later at the same time. Only available to the staff. The transition is recorded with who made it and why.
+// @Tags visits
+// @Param animalid path int true "Animal ID"
+// @Param id path int true "Visit ID"
+// @Accept json
+// @Produce json
+// @Param request body VisitTransitionPayload false "Reason"
+// @Success 200 {object} Visit "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the action can't be taken on a visit of this status, or the veterinarian has another visit at the time of the follow-up"
+// @Failure 500 {string} string "internal server error"
+// @Router /{animalid}/visits/{id}/complete [post]
+func (config *Config) Complete(w http.ResponseWriter, r *http.Request) {
+	config.transition(w, r, model.VisitActionComplete)
+}
+
+// Cancel godoc
+// @Summary Cancel a visit
+// @Description Cancel a visit with a reason. The clients can cancel the visits of their animals until they are checked in, the staff until they start. With the following scope, the next open visits of its series are cancelled too. The transition is recorded with who made it and why.
+// @Tags visits
+// @Param animalid path int true "Animal ID"
+// @Param id path int true "Visit ID"
+// @Accept json
+// @Produce json
+// @Param scope query string false "this (default) or following"
+// @Param request body VisitTransitionPayload false "Reason"
+// @Success 200 {object} Visit "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the action can't be taken on a visit of this status"
+// @Failure 500 {string} string "internal server error"
+// @Router /{animalid}/visits/{id}/cancel [post]
+func (config *Config) Cancel(w http.ResponseWriter, r *http.Request) {
+	config.transition(w, r, model.VisitActionCancel)
+}
+
+// NoShow godoc
+// @Summary Record a no-show
+// @Description Record the animal of a confirmed visit never came, once the visit should have started. Only available to the staff. The transition is recorded with who made it and why.
+// @Tags visits
+// @Param animalid path int true "Animal ID"
+// @Param id path int true "Visit ID"
+// @Accept json
+// @Produce json
+// @Param request body VisitTransitionPayload false "Reason"
+// @Success 200 {object} Visit "ok"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the action can't be taken on a visit of this status"
+// @Failure 500 {string} string "internal server error"
+// @Router /{animalid}/visits/{id}/no-show [post]
+func (config *Config) NoShow(w http.ResponseWriter, r *http.Request) {
+	config.transition(w, r, model.VisitActionNoShow)
+}
+
+// Private
+
+// transition takes the action on the visit of the id URL parameter when the
+// logged user's role allows it from the visit's status
+func (config *Config) transition(w http.ResponseWriter, r *http.Request, name string) {
+	loggedUser := authentication.ForContext(r.Context())
+	action := model.VisitActions[name]
+
+	dbAnimal := config.findAnimal(w, r)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	dbVisit := config.findVisit(w, r, dbAnimal, nil)
+
+	if dbVisit == nil {
+		return
+	}
+
+	data := &model.VisitTransitionPayload{}
+
+	// The body is optional
+	if err := json.NewDecoder(r.Body).Decode(data); err != nil && err != io.EOF {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if err := data.Validate(&action); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if len(action.From[loggedUser.RoleID]) == 0 {
+		render.Render(w, r, errors.ErrForbidden(fmt.Sprintf("cannot %s a visit", name)))
+		return
+	}
+
+	if !action.Allowed(loggedUser.RoleID, dbVisit.Status) {
+		render.Render(w, r, errors.ErrConflict(fmt.Sprintf("cannot %s a visit with the %s status", name, dbVisit.Status)))
+		return
+	}
+
+	if action.To == model.VisitStatusNoShow && dbVisit.Date.After(time.Now()) {
+		render.Render(w, r, errors.ErrConflict("the visit hasn't started yet"))
+		return
+	}
+
+	scope, ok := queryScope(w, r)
+
+	if !ok {
+		return
+	}
+
+	if scope == model.VisitScopeFollowing && !action.Series {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("cannot %s the following visits", name)))
+		return
+	}
+
+	var followUp *dbmodel.Visit
+
+	if data.FollowUpDays != nil {
+		followUp = &dbmodel.Visit{
+			Date:              dbVisit.Date.In(config.Location).AddDate(0, 0, *data.FollowUpDays),
+			AnimalID:          dbVisit.AnimalID,
+			Reason:            model.FollowUpReason(dbVisit.Reason),
+			VetID:             dbVisit.VetID,
+			AppointmentTypeID: dbVisit.AppointmentTypeID,
+			Duration:          dbVisit.Duration,
+			Status:            model.VisitStatusConfirmed,
+		}
+	}
+
+	// The following visits are looked up before the visit changes
+	var dbFollowing []*dbmodel.Visit
+	var err error
+
+	if scope == model.VisitScopeFollowing {
+		if dbFollowing, err = config.VisitsRepository.FindFollowing(dbVisit); err != nil {
+			render.Render(w, r, errors.ErrServerError(err))
+			return
+		}
+	}
+
+	// The following visits the action can't be taken on are left unchanged
+	allowed := make([]*dbmodel.Visit, 0, len(dbFollowing))
+
+	for _, dbFollowingVisit := range dbFollowing {
+		if action.Allowed(loggedUser.RoleID, dbFollowingVisit.Status) {
+			allowed = append(allowed, dbFollowingVisit)
+		}
+	}
+
+	dbVisit, err = config.VisitsRepository.Transition(dbVisit, &dbmodel.VisitTransition{
+		UserID:     &loggedUser.ID,
+		FromStatus: dbVisit.Status,
+		ToStatus:   action.To,
+		Reason:     data.Reason,
+	}, followUp, allowed...)
+
+	if err == dbmodel.ErrVisitStatusChanged || err == dbmodel.ErrVisitConflict {
+		render.Render(w, r, errors.ErrConflict(err.Error()))
+		return
+	}
+
+	if err == dbmodel.ErrVisitVetUnavailable {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, dbVisit.ToModel())
+}
+
+// queryScope returns the scope URL parameter, the error is rendered when
+// false is returned
+func queryScope(w http.ResponseWriter, r *http.Request) (string, bool) {
+	scope := r.URL.Query().Get("scope")
+
+	switch scope {
+	case "", model.VisitScopeThis:
+		return model.VisitScopeThis, true
+	case model.VisitScopeFollowing:
+		return scope, true
+	}
+
+	render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("invalid scope parameter, this or following expected")))
+	return "", false
+}
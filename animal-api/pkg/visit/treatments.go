@@ -0,0 +1,252 @@
+package visit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"feldrise.com/animal-api/database/dbmodel"
+	"feldrise.com/animal-api/helper"
+	"feldrise.com/animal-api/pkg/authentication"
+	"feldrise.com/animal-api/pkg/errors"
+	"feldrise.com/animal-api/pkg/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// CreateTreatment godoc
+// @Summary Record a treatment
+// @Description Record a treatment of the catalog given or prescribed during a visit. Only available to the staff.
+// @Tags visits
+// @Param animalid path int true "Animal ID"
+// @Param id path int true "Visit ID"
+// @Accept json
+// @Produce json
+// @Param request body VisitTreatmentCreatePayload true "Treatment info"
+// @Success 201 {object} VisitTreatment "created"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the visit is closed"
+// @Failure 500 {string} string "internal server error"
+// @Router /{animalid}/visits/{id}/treatments [post]
+func (config *Config) CreateTreatment(w http.ResponseWriter, r *http.Request) {
+	dbAnimal := config.findAnimal(w, r)
+
+	if dbAnimal == nil {
+		return
+	}
+
+	dbVisit := config.findVisit(w, r, dbAnimal, nil)
+
+	if dbVisit == nil || !checkOpen(w, r, dbVisit) {
+		return
+	}
+
+	data := &model.VisitTreatmentCreatePayload{}
+
+	if err := render.Bind(r, data); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	dbTreatment, err := config.TreatmentsRepository.FindByID(*data.TreatmentID)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	if dbTreatment == nil {
+		render.Render(w, r, errors.ErrInvalidRequest(fmt.Errorf("unknown treatment_id")))
+		return
+	}
+
+	dbVisitTreatment := &dbmodel.VisitTreatment{
+		AnimalID:     dbAnimal.ID,
+		VisitID:      dbVisit.ID,
+		TreatmentID:  dbTreatment.ID,
+		Kind:         model.TreatmentAdministered,
+		Dosage:       *data.Dosage,
+		Unit:         data.Unit,
+		Route:        data.Route,
+		Frequency:    data.Frequency,
+		DurationDays: data.DurationDays,
+		Notes:        data.Notes,
+	}
+
+	if data.Kind != nil {
+		dbVisitTreatment.Kind = *data.Kind
+	}
+
+	dbVisitTreatment, err = config.VisitTreatmentsRepository.Create(dbVisitTreatment)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	dbVisitTreatment.Treatment = *dbTreatment
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, dbVisitTreatment.ToModel())
+}
+
+// UpdateTreatment godoc
+// @Summary Update a treatment of a visit
+// @Description Update the dosage, route or prescription of a treatment recorded during a visit. Only available to the staff.
+// @Tags visits
+// @Param animalid path int true "Animal ID"
+// @Param id path int true "Visit ID"
+// @Param treatmentid path int true "Visit treatment ID"
+// @Accept json
+// @Produce json
+// @Param request body VisitTreatmentUpdatePayload true "Treatment info"
+// @Success 200 {object} VisitTreatment "ok"
+// @Failure 400 {object} ErrResponse "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the visit is closed"
+// @Failure 500 {string} string "internal server error"
+// @Router /{animalid}/visits/{id}/treatments/{treatmentid} [put]
+func (config *Config) UpdateTreatment(w http.ResponseWriter, r *http.Request) {
+	loggedUser := authentication.ForContext(r.Context())
+
+	dbVisitTreatment := config.findVisitTreatment(w, r)
+
+	if dbVisitTreatment == nil {
+		return
+	}
+
+	data := &model.VisitTreatmentUpdatePayload{}
+
+	forbidden, err := helper.DecodeAllowed(r.Body, model.VisitTreatmentUpdateFields[loggedUser.RoleID], data)
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if len(forbidden) > 0 {
+		render.Render(w, r, errors.ErrForbiddenFields(forbidden))
+		return
+	}
+
+	if err := data.Validate(); err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return
+	}
+
+	if data.Kind != nil {
+		dbVisitTreatment.Kind = *data.Kind
+	}
+
+	if data.Dosage != nil {
+		dbVisitTreatment.Dosage = *data.Dosage
+	}
+
+	if data.Unit != nil {
+		dbVisitTreatment.Unit = *data.Unit
+	}
+
+	if data.Route != nil {
+		dbVisitTreatment.Route = *data.Route
+	}
+
+	if data.Frequency != nil {
+		dbVisitTreatment.Frequency = *data.Frequency
+	}
+
+	if data.DurationDays != nil {
+		dbVisitTreatment.DurationDays = data.DurationDays
+
+		if *data.DurationDays == 0 {
+			dbVisitTreatment.DurationDays = nil
+		}
+	}
+
+	if data.Notes != nil {
+		dbVisitTreatment.Notes = *data.Notes
+	}
+
+	dbVisitTreatment, err = config.VisitTreatmentsRepository.Update(dbVisitTreatment)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.JSON(w, r, dbVisitTreatment.ToModel())
+}
+
+// DeleteTreatment godoc
+// @Summary Delete a treatment of a visit
+// @Description Permanently delete a treatment recorded by mistake during a visit. Only available to the staff.
+// @Tags visits
+// @Param animalid path int true "Animal ID"
+// @Param id path int true "Visit ID"
+// @Param treatmentid path int true "Visit treatment ID"
+// @Success 204 "no content"
+// @Failure 400 {string} string "bad request"
+// @Failure 401 {string} string "unauthorized"
+// @Failure 403 {string} string "forbidden"
+// @Failure 404 {string} string "not found"
+// @Failure 409 {string} string "the visit is closed"
+// @Failure 500 {string} string "internal server error"
+// @Router /{animalid}/visits/{id}/treatments/{treatmentid} [delete]
+func (config *Config) DeleteTreatment(w http.ResponseWriter, r *http.Request) {
+	dbVisitTreatment := config.findVisitTreatment(w, r)
+
+	if dbVisitTreatment == nil {
+		return
+	}
+
+	if err := config.VisitTreatmentsRepository.Delete(dbVisitTreatment); err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return
+	}
+
+	render.NoContent(w, r)
+}
+
+// Private
+
+// findVisitTreatment returns the treatment of the treatmentid URL parameter
+// when it was recorded during the visit and the visit isn't closed, the error
+// is rendered when nil is returned
+func (config *Config) findVisitTreatment(w http.ResponseWriter, r *http.Request) *dbmodel.VisitTreatment {
+	dbAnimal := config.findAnimal(w, r)
+
+	if dbAnimal == nil {
+		return nil
+	}
+
+	dbVisit := config.findVisit(w, r, dbAnimal, nil)
+
+	if dbVisit == nil || !checkOpen(w, r, dbVisit) {
+		return nil
+	}
+
+	id := chi.URLParam(r, "treatmentid")
+	idUint, err := strconv.ParseUint(id, 10, 64)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrInvalidRequest(err))
+		return nil
+	}
+
+	dbVisitTreatment, err := config.VisitTreatmentsRepository.FindByID(uint(idUint), dbVisit.ID)
+
+	if err != nil {
+		render.Render(w, r, errors.ErrServerError(err))
+		return nil
+	}
+
+	if dbVisitTreatment == nil {
+		render.Render(w, r, errors.ErrNotFound())
+		return nil
+	}
+
+	return dbVisitTreatment
+}